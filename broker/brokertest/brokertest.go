@@ -0,0 +1,493 @@
+// Package brokertest provides a fake Pact Broker for tests, in the manner of
+// net/http/httptest.
+//
+// A Server implements the HAL resources of the Pact Broker that are used to publish
+// pacts, to fetch the pacts for the verification of a provider (with consumer
+// version selectors, pending and WIP pacts), to publish verification results and to
+// record deployments and releases. Its state is kept in memory, so that tests can
+// seed it and assert on what was published to it:
+//
+//	b := brokertest.NewServer(t)
+//	b.AddPact(brokertest.Pact{ConsumerVersion: "4c2f8a1", Branch: "main", Content: pact})
+//
+//	err := provider.NewVerifier().VerifyProvider(t, provider.VerifyRequest{
+//		BrokerURL:                  b.URL,
+//		Provider:                   "users",
+//		ProviderVersion:            "1.0.0",
+//		ProviderBranch:             "main",
+//		ConsumerVersionSelectors:   []provider.Selector{&provider.ConsumerVersionSelector{MainBranch: true}},
+//		PublishVerificationResults: true,
+//	})
+//
+//	for _, r := range b.VerificationResults() {
+//		...
+//	}
+package brokertest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Server is a fake Pact Broker
+type Server struct {
+	*httptest.Server
+
+	t  testing.TB
+	mu sync.Mutex
+
+	username string
+	password string
+	token    string
+
+	environments []string
+	mainBranches map[string]string
+	versions     []*Version
+	pacts        []*Pact
+	results      []*verificationResult
+	deployments  []Deployment
+	requests     []PactsForVerificationRequest
+}
+
+// Version is a version of a pacticipant (a consumer or provider)
+type Version struct {
+	Pacticipant string
+	Number      string
+	Branch      string
+	Tags        []string
+	CreatedAt   time.Time
+}
+
+// Pact is a pact published for a version of its consumer
+type Pact struct {
+	// Consumer and Provider default to the names in the Content
+	Consumer string
+	Provider string
+
+	ConsumerVersion string
+
+	// Branch and Tags of the consumer version
+	Branch string
+	Tags   []string
+
+	// Content is the pact file. Defaults to a pact without interactions.
+	Content []byte
+
+	// CreatedAt defaults to the time the pact was added
+	CreatedAt time.Time
+}
+
+// VerificationResult is the result of the verification of a pact by a version of
+// the provider
+type VerificationResult struct {
+	Consumer string
+	Provider string
+
+	// ConsumerVersion is the version of the consumer of the verified pact. If the
+	// same pact was published for several versions, it is the latest.
+	ConsumerVersion string
+
+	ProviderVersion string
+	Success         bool
+	BuildURL        string
+
+	// TestResults are the results of the interactions, as published by the verifier
+	TestResults json.RawMessage
+
+	// CreatedAt defaults to the time the result was added
+	CreatedAt time.Time
+}
+
+type verificationResult struct {
+	VerificationResult
+
+	// pactVersion identifies the content of the verified pact
+	pactVersion string
+}
+
+// Deployment is a version of a pacticipant that is deployed, or released, to an
+// environment
+type Deployment struct {
+	Pacticipant         string
+	Version             string
+	Environment         string
+	ApplicationInstance string
+
+	// Released is true for a release (e.g. of a mobile app) rather than a deployment.
+	// Several versions may be released to an environment at once.
+	Released bool
+}
+
+// PactsForVerificationRequest is a request of a verifier for the pacts of a provider
+type PactsForVerificationRequest struct {
+	Provider                 string     `json:"-"`
+	ProviderVersionBranch    string     `json:"providerVersionBranch,omitempty"`
+	ProviderVersionTags      []string   `json:"providerVersionTags,omitempty"`
+	ConsumerVersionSelectors []Selector `json:"consumerVersionSelectors,omitempty"`
+	IncludePendingStatus     bool       `json:"includePendingStatus"`
+	IncludeWipPactsSince     string     `json:"includeWipPactsSince,omitempty"`
+}
+
+// Selector is a consumer version selector, see https://docs.pact.io/selectors
+type Selector struct {
+	Tag                string `json:"tag,omitempty"`
+	FallbackTag        string `json:"fallbackTag,omitempty"`
+	Latest             bool   `json:"latest,omitempty"`
+	Consumer           string `json:"consumer,omitempty"`
+	DeployedOrReleased bool   `json:"deployedOrReleased,omitempty"`
+	Deployed           bool   `json:"deployed,omitempty"`
+	Released           bool   `json:"released,omitempty"`
+	Environment        string `json:"environment,omitempty"`
+	MainBranch         bool   `json:"mainBranch,omitempty"`
+	MatchingBranch     bool   `json:"matchingBranch,omitempty"`
+	Branch             string `json:"branch,omitempty"`
+}
+
+// NewServer starts a fake Pact Broker, which is closed when the test completes. Like
+// a new Pact Broker, it has the environments test and production, and does not
+// require authentication.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		t:            t,
+		environments: []string{"test", "production"},
+		mainBranches: make(map[string]string),
+	}
+	s.Server = httptest.NewServer(s.handler())
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// SetBasicAuth requires requests to authenticate with the username and password
+func (s *Server) SetBasicAuth(username string, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.username = username
+	s.password = password
+}
+
+// SetToken requires requests to authenticate with the bearer token
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+}
+
+// AddEnvironment adds an environment that versions may be deployed to
+func (s *Server) AddEnvironment(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !contains(s.environments, name) {
+		s.environments = append(s.environments, name)
+	}
+}
+
+// SetMainBranch sets the main branch of the pacticipant. Otherwise the first branch
+// named develop, main or master of a version of the pacticipant is its main branch.
+func (s *Server) SetMainBranch(pacticipant string, branch string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mainBranches[pacticipant] = branch
+}
+
+// AddVersion adds a version of a pacticipant, or adds the branch and tags to an
+// existing version
+func (s *Server) AddVersion(v Version) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := s.addVersion(v.Pacticipant, v.Number, v.Branch, v.Tags)
+	if !v.CreatedAt.IsZero() {
+		version.CreatedAt = v.CreatedAt
+	}
+}
+
+// AddPact publishes a pact, as the consumer would. The test fails if the pact is
+// not valid.
+func (s *Server) AddPact(p Pact) {
+	s.t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.addPact(p); err != nil {
+		s.t.Fatalf("brokertest: unable to add the pact: %v", err)
+	}
+}
+
+// AddVerificationResult publishes the result of the verification of the pact of the
+// consumer version, as the provider would. The test fails if there is no such pact.
+// Use AddVersion to give the provider version a branch or tags.
+func (s *Server) AddVerificationResult(r VerificationResult) {
+	s.t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pact := s.pact(r.Consumer, r.Provider, r.ConsumerVersion)
+	if pact == nil {
+		s.t.Fatalf("brokertest: there is no pact between %s version %s and %s", r.Consumer, r.ConsumerVersion, r.Provider)
+	}
+	s.addVersion(r.Provider, r.ProviderVersion, "", nil)
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	s.results = append(s.results, &verificationResult{VerificationResult: r, pactVersion: pactVersion(pact.Content)})
+}
+
+// RecordDeployment records that the version of the pacticipant is deployed to the
+// environment, replacing the version previously deployed there (to the same
+// application instance)
+func (s *Server) RecordDeployment(pacticipant string, version string, environment string, applicationInstance string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addVersion(pacticipant, version, "", nil)
+	s.recordDeployment(Deployment{Pacticipant: pacticipant, Version: version, Environment: environment, ApplicationInstance: applicationInstance})
+}
+
+// RecordRelease records that the version of the pacticipant is released to the
+// environment
+func (s *Server) RecordRelease(pacticipant string, version string, environment string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addVersion(pacticipant, version, "", nil)
+	s.recordDeployment(Deployment{Pacticipant: pacticipant, Version: version, Environment: environment, Released: true})
+}
+
+// Versions returns the versions of the pacticipants, in the order they were created
+func (s *Server) Versions() []Version {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := make([]Version, 0, len(s.versions))
+	for _, v := range s.versions {
+		version := *v
+		version.Tags = append([]string(nil), v.Tags...)
+		versions = append(versions, version)
+	}
+
+	return versions
+}
+
+// Pacts returns the published pacts, in the order they were created, with the
+// branch and tags of their consumer version
+func (s *Server) Pacts() []Pact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pacts := make([]Pact, 0, len(s.pacts))
+	for _, p := range s.pacts {
+		pact := *p
+		if v := s.version(p.Consumer, p.ConsumerVersion); v != nil {
+			pact.Branch = v.Branch
+			pact.Tags = append([]string(nil), v.Tags...)
+		}
+		pacts = append(pacts, pact)
+	}
+
+	return pacts
+}
+
+// VerificationResults returns the published verification results, in the order they
+// were published
+func (s *Server) VerificationResults() []VerificationResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]VerificationResult, 0, len(s.results))
+	for _, r := range s.results {
+		results = append(results, r.VerificationResult)
+	}
+
+	return results
+}
+
+// Deployments returns the versions that are currently deployed or released
+func (s *Server) Deployments() []Deployment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Deployment{}, s.deployments...)
+}
+
+// PactsForVerificationRequests returns the requests of verifiers for the pacts of a
+// provider, in the order they were made
+func (s *Server) PactsForVerificationRequests() []PactsForVerificationRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]PactsForVerificationRequest{}, s.requests...)
+}
+
+// version returns the version of the pacticipant, if any
+func (s *Server) version(pacticipant string, number string) *Version {
+	for _, v := range s.versions {
+		if v.Pacticipant == pacticipant && v.Number == number {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// pacticipant returns whether the pacticipant is known, as it has a version or pact
+func (s *Server) pacticipant(name string) bool {
+	for _, v := range s.versions {
+		if v.Pacticipant == name {
+			return true
+		}
+	}
+	for _, p := range s.pacts {
+		if p.Provider == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addVersion returns the version of the pacticipant, which is created if it does not
+// exist, with the branch (if it does not have one) and tags added
+func (s *Server) addVersion(pacticipant string, number string, branch string, tags []string) *Version {
+	v := s.version(pacticipant, number)
+	if v == nil {
+		v = &Version{Pacticipant: pacticipant, Number: number, CreatedAt: time.Now()}
+		s.versions = append(s.versions, v)
+	}
+	if v.Branch == "" && branch != "" {
+		v.Branch = branch
+		if _, ok := s.mainBranches[pacticipant]; !ok && (branch == "develop" || branch == "main" || branch == "master") {
+			s.mainBranches[pacticipant] = branch
+		}
+	}
+	for _, tag := range tags {
+		if !contains(v.Tags, tag) {
+			v.Tags = append(v.Tags, tag)
+		}
+	}
+
+	return v
+}
+
+// pact returns the pact between the version of the consumer and the provider, if any
+func (s *Server) pact(consumer string, provider string, consumerVersion string) *Pact {
+	for _, p := range s.pacts {
+		if p.Consumer == consumer && p.Provider == provider && p.ConsumerVersion == consumerVersion {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// latestPact returns the pact of the latest consumer version with the content of the
+// pact version, if any
+func (s *Server) latestPact(consumer string, provider string, version string) *Pact {
+	var pact *Pact
+	for _, p := range s.pacts {
+		if p.Consumer == consumer && p.Provider == provider && pactVersion(p.Content) == version {
+			pact = p
+		}
+	}
+
+	return pact
+}
+
+func (s *Server) addPact(p Pact) error {
+	if len(p.Content) == 0 {
+		content, err := json.Marshal(map[string]interface{}{
+			"consumer":     map[string]string{"name": p.Consumer},
+			"provider":     map[string]string{"name": p.Provider},
+			"interactions": []interface{}{},
+			"metadata":     map[string]interface{}{"pactSpecification": map[string]string{"version": "4.0"}},
+		})
+		if err != nil {
+			return err
+		}
+		p.Content = content
+	}
+
+	var names struct {
+		Consumer struct {
+			Name string `json:"name"`
+		} `json:"consumer"`
+		Provider struct {
+			Name string `json:"name"`
+		} `json:"provider"`
+	}
+	if err := json.Unmarshal(p.Content, &names); err != nil {
+		return err
+	}
+	if p.Consumer == "" {
+		p.Consumer = names.Consumer.Name
+	}
+	if p.Provider == "" {
+		p.Provider = names.Provider.Name
+	}
+	if p.Consumer == "" || p.Provider == "" || p.ConsumerVersion == "" {
+		return errors.New("a consumer, provider and consumer version are required")
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+
+	s.addVersion(p.Consumer, p.ConsumerVersion, p.Branch, p.Tags)
+	p.Tags = append([]string(nil), p.Tags...)
+	if existing := s.pact(p.Consumer, p.Provider, p.ConsumerVersion); existing != nil {
+		*existing = p
+	} else {
+		s.pacts = append(s.pacts, &p)
+	}
+	sort.SliceStable(s.pacts, func(i, j int) bool {
+		return s.pacts[i].CreatedAt.Before(s.pacts[j].CreatedAt)
+	})
+
+	return nil
+}
+
+func (s *Server) recordDeployment(d Deployment) {
+	if !d.Released {
+		deployments := s.deployments[:0]
+		for _, existing := range s.deployments {
+			if existing.Released || existing.Pacticipant != d.Pacticipant || existing.Environment != d.Environment || existing.ApplicationInstance != d.ApplicationInstance {
+				deployments = append(deployments, existing)
+			}
+		}
+		s.deployments = deployments
+	}
+	s.deployments = append(s.deployments, d)
+}
+
+// pactVersion identifies the content of a pact, regardless of its formatting
+func pactVersion(content []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(content, &v); err == nil {
+		if normalised, err := json.Marshal(v); err == nil {
+			content = normalised
+		}
+	}
+	sum := sha1.Sum(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
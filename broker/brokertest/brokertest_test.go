@@ -0,0 +1,271 @@
+package brokertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pact-foundation/pact-go/v2/broker"
+	"github.com/stretchr/testify/assert"
+)
+
+// verifier fetches and verifies pacts from the server as the pact verifier does,
+// following the HAL links from the index of the broker
+type verifier struct {
+	t      *testing.T
+	server *Server
+}
+
+func (v *verifier) do(method string, href string, body interface{}, status int) map[string]interface{} {
+	v.t.Helper()
+
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		assert.NoError(v.t, err)
+	}
+	req, err := http.NewRequest(method, href, bytes.NewReader(data))
+	assert.NoError(v.t, err)
+	req.Header.Set("Accept", "application/hal+json")
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(v.t, err)
+	defer res.Body.Close()
+	assert.Equal(v.t, status, res.StatusCode, "%s %s", method, href)
+
+	var doc map[string]interface{}
+	assert.NoError(v.t, json.NewDecoder(res.Body).Decode(&doc))
+	return doc
+}
+
+func href(doc map[string]interface{}, rel string, params map[string]string) string {
+	l, _ := doc["_links"].(map[string]interface{})[rel].(map[string]interface{})
+	h, _ := l["href"].(string)
+	for k, v := range params {
+		h = strings.ReplaceAll(h, "{"+k+"}", v)
+	}
+	return h
+}
+
+// pactsForVerification returns the pacts for verification of the request
+func (v *verifier) pactsForVerification(r PactsForVerificationRequest) []map[string]interface{} {
+	v.t.Helper()
+
+	index := v.do(http.MethodGet, v.server.URL, nil, http.StatusOK)
+	doc := v.do(http.MethodPost, href(index, "pb:provider-pacts-for-verification", map[string]string{"provider": r.Provider}), r, http.StatusOK)
+
+	var pacts []map[string]interface{}
+	for _, p := range doc["_embedded"].(map[string]interface{})["pacts"].([]interface{}) {
+		pacts = append(pacts, p.(map[string]interface{}))
+	}
+	return pacts
+}
+
+// publish publishes the result of the verification of the pact, with the branch of
+// the provider version
+func (v *verifier) publish(pact map[string]interface{}, version string, branch string, success bool) {
+	v.t.Helper()
+
+	doc := v.do(http.MethodGet, href(pact, "self", nil), nil, http.StatusOK)
+	provider := v.do(http.MethodGet, href(doc, "pb:provider", nil), nil, http.StatusOK)
+	v.do(http.MethodPut, href(provider, "pb:branch-version", map[string]string{"branch": branch, "version": version}), map[string]interface{}{}, http.StatusOK)
+	v.do(http.MethodPost, href(doc, "pb:publish-verification-results", nil), map[string]interface{}{
+		"success":                    success,
+		"providerApplicationVersion": version,
+		"testResults":                []interface{}{map[string]interface{}{"interactionId": "1", "success": success}},
+	}, http.StatusCreated)
+}
+
+func descriptions(pacts []map[string]interface{}) []string {
+	var d []string
+	for _, p := range pacts {
+		d = append(d, p["shortDescription"].(string))
+	}
+	return d
+}
+
+func TestPublishAndVerify(t *testing.T) {
+	b := NewServer(t)
+	dir := t.TempDir()
+	pact := `{"consumer": {"name": "web"}, "provider": {"name": "users"}, "interactions": [], "metadata": {"pactSpecification": {"version": "4.0"}}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "web-users.json"), []byte(pact), 0644))
+
+	client, err := broker.NewClient(broker.Config{URL: b.URL})
+	assert.NoError(t, err)
+	_, err = client.Publish(context.Background(), broker.PublishRequest{PactFiles: []string{dir}, ConsumerVersion: "1.0.0", Branch: "main", Tags: []string{"prod"}})
+	assert.NoError(t, err)
+
+	pacts := b.Pacts()
+	assert.Len(t, pacts, 1)
+	assert.Equal(t, "web", pacts[0].Consumer)
+	assert.Equal(t, "users", pacts[0].Provider)
+	assert.Equal(t, "main", pacts[0].Branch)
+	assert.Equal(t, []string{"prod"}, pacts[0].Tags)
+	assert.JSONEq(t, pact, string(pacts[0].Content))
+
+	v := &verifier{t: t, server: b}
+	request := PactsForVerificationRequest{
+		Provider:                 "users",
+		ProviderVersionBranch:    "main",
+		ConsumerVersionSelectors: []Selector{{MainBranch: true}},
+		IncludePendingStatus:     true,
+	}
+	selected := v.pactsForVerification(request)
+	assert.Len(t, selected, 1)
+	assert.Equal(t, "latest from main branch", selected[0]["shortDescription"])
+	assert.Equal(t, true, selected[0]["verificationProperties"].(map[string]interface{})["pending"])
+
+	v.publish(selected[0], "2.0.0", "main", true)
+	results := b.VerificationResults()
+	assert.Len(t, results, 1)
+	assert.Equal(t, "web", results[0].Consumer)
+	assert.Equal(t, "1.0.0", results[0].ConsumerVersion)
+	assert.Equal(t, "2.0.0", results[0].ProviderVersion)
+	assert.True(t, results[0].Success)
+	assert.JSONEq(t, `[{"interactionId": "1", "success": true}]`, string(results[0].TestResults))
+
+	selected = v.pactsForVerification(request)
+	assert.Equal(t, false, selected[0]["verificationProperties"].(map[string]interface{})["pending"])
+
+	request.ProviderVersionBranch = "feature"
+	selected = v.pactsForVerification(request)
+	assert.Equal(t, true, selected[0]["verificationProperties"].(map[string]interface{})["pending"])
+
+	requests := b.PactsForVerificationRequests()
+	assert.Len(t, requests, 3)
+	assert.Equal(t, "users", requests[0].Provider)
+	assert.Equal(t, []Selector{{MainBranch: true}}, requests[0].ConsumerVersionSelectors)
+}
+
+func TestSelectors(t *testing.T) {
+	b := NewServer(t)
+	start := time.Now().Add(-time.Hour)
+	for i, p := range []Pact{
+		{Consumer: "web", ConsumerVersion: "1", Branch: "main", Tags: []string{"prod"}},
+		{Consumer: "web", ConsumerVersion: "2", Branch: "main"},
+		{Consumer: "web", ConsumerVersion: "3", Branch: "feature"},
+		{Consumer: "mobile", ConsumerVersion: "1", Branch: "master"},
+		{Consumer: "mobile", ConsumerVersion: "2", Branch: "feature"},
+	} {
+		p.Provider = "users"
+		p.Content = []byte(`{"consumer": {"name": "` + p.Consumer + `"}, "provider": {"name": "users"}, "interactions": [{"description": "` + p.ConsumerVersion + `"}]}`)
+		p.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		b.AddPact(p)
+	}
+	b.RecordDeployment("web", "1", "production", "")
+	b.RecordRelease("mobile", "1", "production")
+	b.AddPact(Pact{Consumer: "web", Provider: "orders", ConsumerVersion: "3"})
+
+	v := &verifier{t: t, server: b}
+	tests := []struct {
+		name      string
+		selectors []Selector
+		branch    string
+		want      []string
+	}{
+		{name: "default", want: []string{"latest", "latest"}},
+		{name: "main branch", selectors: []Selector{{MainBranch: true}}, want: []string{"latest from main branch", "latest from main branch"}},
+		{name: "branch of consumer", selectors: []Selector{{Branch: "feature", Consumer: "web"}}, want: []string{"latest from branch feature of web"}},
+		{name: "matching branch", selectors: []Selector{{MatchingBranch: true}}, branch: "feature", want: []string{"latest from branch feature matching the provider branch", "latest from branch feature matching the provider branch"}},
+		{name: "matching branch without a provider branch", selectors: []Selector{{MatchingBranch: true}}, want: nil},
+		{name: "tag", selectors: []Selector{{Tag: "prod"}}, want: []string{"all with tag prod"}},
+		{name: "fallback tag", selectors: []Selector{{Tag: "test", FallbackTag: "prod", Latest: true}}, want: []string{"latest with tag test (or fallback tag prod)"}},
+		{name: "deployed", selectors: []Selector{{Deployed: true}}, want: []string{"currently deployed"}},
+		{name: "deployed or released", selectors: []Selector{{DeployedOrReleased: true, Environment: "production"}}, want: []string{"currently deployed or released to production", "currently deployed or released to production"}},
+		{name: "combined", selectors: []Selector{{MainBranch: true}, {Deployed: true}}, want: []string{"latest from main branch", "latest from main branch", "currently deployed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v.t = t
+			selected := v.pactsForVerification(PactsForVerificationRequest{Provider: "users", ProviderVersionBranch: tt.branch, ConsumerVersionSelectors: tt.selectors})
+			assert.Equal(t, tt.want, descriptions(selected))
+		})
+	}
+}
+
+func TestSelectorsMergePactsWithTheSameContent(t *testing.T) {
+	b := NewServer(t)
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "1", Branch: "main", Tags: []string{"prod"}})
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "2", Branch: "main"})
+
+	v := &verifier{t: t, server: b}
+	selected := v.pactsForVerification(PactsForVerificationRequest{
+		Provider:                 "users",
+		ConsumerVersionSelectors: []Selector{{MainBranch: true}, {Tag: "prod", Latest: true}},
+	})
+	assert.Equal(t, []string{"latest from main branch, latest with tag prod"}, descriptions(selected))
+	assert.Equal(t, "Pact between web (2) and users", selected[0]["_links"].(map[string]interface{})["self"].(map[string]interface{})["name"])
+}
+
+func TestWIPPacts(t *testing.T) {
+	b := NewServer(t)
+	since := time.Now().Add(-time.Hour)
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "1", Branch: "main", CreatedAt: since.Add(-time.Hour)})
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "2", Branch: "feature", Content: []byte(`{"consumer": {"name": "web"}, "provider": {"name": "users"}, "interactions": [{"description": "new"}]}`)})
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "3", Branch: "verified", Content: []byte(`{"consumer": {"name": "web"}, "provider": {"name": "users"}, "interactions": [{"description": "verified"}]}`)})
+	b.AddVersion(Version{Pacticipant: "users", Number: "1.0.0", Branch: "main"})
+	b.AddVerificationResult(VerificationResult{Consumer: "web", Provider: "users", ConsumerVersion: "3", ProviderVersion: "1.0.0", Success: true})
+
+	v := &verifier{t: t, server: b}
+	request := PactsForVerificationRequest{
+		Provider:                 "users",
+		ProviderVersionBranch:    "main",
+		ConsumerVersionSelectors: []Selector{{MainBranch: true}},
+		IncludePendingStatus:     true,
+		IncludeWipPactsSince:     since.Format(time.RFC3339),
+	}
+	selected := v.pactsForVerification(request)
+	assert.Equal(t, []string{"latest from main branch", "work in progress"}, descriptions(selected))
+	properties := selected[1]["verificationProperties"].(map[string]interface{})
+	assert.Equal(t, true, properties["wip"])
+	assert.Equal(t, true, properties["pending"])
+
+	request.IncludeWipPactsSince = "yesterday"
+	index := v.do(http.MethodGet, b.URL, nil, http.StatusOK)
+	v.do(http.MethodPost, href(index, "pb:provider-pacts-for-verification", map[string]string{"provider": "users"}), request, http.StatusBadRequest)
+}
+
+func TestDeployments(t *testing.T) {
+	b := NewServer(t)
+	b.SetToken("secret")
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "1"})
+	b.AddPact(Pact{Consumer: "web", Provider: "users", ConsumerVersion: "2"})
+	b.RecordDeployment("web", "1", "production", "")
+
+	client, err := broker.NewClient(broker.Config{URL: b.URL, Token: "secret"})
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.NoError(t, client.RecordDeployment(ctx, broker.RecordDeploymentRequest{Pacticipant: "web", Version: "2", Environment: "production"}))
+	assert.NoError(t, client.RecordRelease(ctx, broker.RecordReleaseRequest{Pacticipant: "web", Version: "1", Environment: "test"}))
+	assert.Error(t, client.RecordDeployment(ctx, broker.RecordDeploymentRequest{Pacticipant: "web", Version: "2", Environment: "staging"}))
+
+	assert.Equal(t, []Deployment{
+		{Pacticipant: "web", Version: "2", Environment: "production"},
+		{Pacticipant: "web", Version: "1", Environment: "test", Released: true},
+	}, b.Deployments())
+}
+
+func TestAuthentication(t *testing.T) {
+	b := NewServer(t)
+	b.SetBasicAuth("user", "pass")
+
+	res, err := http.Get(b.URL)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, b.URL, nil)
+	req.SetBasicAuth("user", "pass")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
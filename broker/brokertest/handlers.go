@@ -0,0 +1,443 @@
+package brokertest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// hal is a HAL resource, or a link
+type hal map[string]interface{}
+
+type publishContracts struct {
+	PacticipantName          string   `json:"pacticipantName"`
+	PacticipantVersionNumber string   `json:"pacticipantVersionNumber"`
+	Branch                   string   `json:"branch"`
+	Tags                     []string `json:"tags"`
+	BuildURL                 string   `json:"buildUrl"`
+	Contracts                []struct {
+		ConsumerName  string `json:"consumerName"`
+		ProviderName  string `json:"providerName"`
+		Specification string `json:"specification"`
+		ContentType   string `json:"contentType"`
+		Content       string `json:"content"`
+	} `json:"contracts"`
+}
+
+type verificationResultRequest struct {
+	Success                    *bool           `json:"success"`
+	ProviderApplicationVersion string          `json:"providerApplicationVersion"`
+	BuildURL                   string          `json:"buildUrl"`
+	TestResults                json.RawMessage `json:"testResults"`
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.HandleFunc("POST /contracts/publish", s.publishContracts)
+	mux.HandleFunc("PUT /pacts/provider/{provider}/consumer/{consumer}/version/{version}", s.publishPact)
+	mux.HandleFunc("POST /pacts/provider/{provider}/for-verification", s.pactsForVerification)
+	mux.HandleFunc("GET /pacts/provider/{provider}/consumer/{consumer}/pact-version/{pactVersion}", s.getPact)
+	mux.HandleFunc("POST /pacts/provider/{provider}/consumer/{consumer}/pact-version/{pactVersion}/verification-results", s.publishVerificationResult)
+	mux.HandleFunc("GET /pacticipants/{pacticipant}", s.getPacticipant)
+	mux.HandleFunc("PUT /pacticipants/{pacticipant}/branches/{branch}/versions/{version}", s.addBranchVersion)
+	mux.HandleFunc("PUT /pacticipants/{pacticipant}/versions/{version}/tags/{tag}", s.addVersionTag)
+	mux.HandleFunc("GET /pacticipants/{pacticipant}/versions/{version}", s.getVersion)
+	mux.HandleFunc("POST /pacticipants/{pacticipant}/versions/{version}/deployed-versions/environment/{environment}", s.recordDeployedVersion)
+	mux.HandleFunc("POST /pacticipants/{pacticipant}/versions/{version}/released-versions/environment/{environment}", s.recordReleasedVersion)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.authorized(r) {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"error": map[string]string{"message": "Authentication required"}})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" && s.username == "" {
+		return true
+	}
+	if s.token != "" && r.Header.Get("Authorization") == "Bearer "+s.token {
+		return true
+	}
+	username, password, ok := r.BasicAuth()
+
+	return s.username != "" && ok && username == s.username && password == s.password
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links": hal{
+			"self":                               link(s.URL, "Index"),
+			"pb:publish-contracts":               link(s.URL+"/contracts/publish", "Publish contracts"),
+			"pb:publish-pact":                    templated(s.URL+"/pacts/provider/{provider}/consumer/{consumer}/version/{consumerApplicationVersion}", "Publish a pact"),
+			"pb:provider-pacts-for-verification": templated(s.URL+"/pacts/provider/{provider}/for-verification", "Pact versions to be verified for the specified provider"),
+			"pb:pacticipant":                     templated(s.URL+"/pacticipants/{pacticipant}", "Fetch pacticipant by name"),
+			"pb:pacticipant-version":             templated(s.URL+"/pacticipants/{pacticipant}/versions/{version}", "Get, create or delete a pacticipant version"),
+		},
+	})
+}
+
+func (s *Server) publishContracts(w http.ResponseWriter, r *http.Request) {
+	var request publishContracts
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeErrors(w, "body", err.Error())
+		return
+	}
+	if request.PacticipantName == "" {
+		writeErrors(w, "pacticipantName", "can't be blank")
+		return
+	}
+	if request.PacticipantVersionNumber == "" {
+		writeErrors(w, "pacticipantVersionNumber", "can't be blank")
+		return
+	}
+
+	pacts := make([]Pact, 0, len(request.Contracts))
+	for i, c := range request.Contracts {
+		content, err := base64.StdEncoding.DecodeString(c.Content)
+		if err != nil {
+			writeErrors(w, fmt.Sprintf("contracts.%d.content", i), "is not valid base64")
+			return
+		}
+		if c.ConsumerName != request.PacticipantName {
+			writeErrors(w, fmt.Sprintf("contracts.%d.consumerName", i), "must match the pacticipantName")
+			return
+		}
+		pacts = append(pacts, Pact{
+			Consumer:        c.ConsumerName,
+			Provider:        c.ProviderName,
+			ConsumerVersion: request.PacticipantVersionNumber,
+			Branch:          request.Branch,
+			Tags:            request.Tags,
+			Content:         content,
+		})
+	}
+
+	notices := []map[string]string{{"type": "success", "text": fmt.Sprintf("Created %s version %s", request.PacticipantName, request.PacticipantVersionNumber)}}
+	contracts := make([]hal, 0, len(pacts))
+	for _, p := range pacts {
+		if err := s.addPact(p); err != nil {
+			writeErrors(w, "contracts", err.Error())
+			return
+		}
+		notices = append(notices, map[string]string{"type": "success", "text": fmt.Sprintf("Pact published for %s version %s and provider %s", p.Consumer, p.ConsumerVersion, p.Provider)})
+		contracts = append(contracts, hal{
+			"name":  fmt.Sprintf("Pact between %s (%s) and %s", p.Consumer, p.ConsumerVersion, p.Provider),
+			"href":  s.pactURL(p.Provider, p.Consumer, pactVersion(p.Content)),
+			"title": "Pact",
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"notices": notices,
+		"_links": hal{
+			"pb:pacticipant-version": link(s.versionURL(request.PacticipantName, request.PacticipantVersionNumber), "Pacticipant version"),
+			"pb:contracts":           contracts,
+		},
+	})
+}
+
+func (s *Server) publishPact(w http.ResponseWriter, r *http.Request) {
+	var content json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+		writeErrors(w, "body", err.Error())
+		return
+	}
+
+	p := Pact{
+		Consumer:        r.PathValue("consumer"),
+		Provider:        r.PathValue("provider"),
+		ConsumerVersion: r.PathValue("version"),
+		Content:         content,
+	}
+	status := http.StatusCreated
+	if s.pact(p.Consumer, p.Provider, p.ConsumerVersion) != nil {
+		status = http.StatusOK
+	}
+	if err := s.addPact(p); err != nil {
+		writeErrors(w, "body", err.Error())
+		return
+	}
+
+	s.writePact(w, status, &p)
+}
+
+func (s *Server) pactsForVerification(w http.ResponseWriter, r *http.Request) {
+	var request PactsForVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeErrors(w, "body", err.Error())
+		return
+	}
+	request.Provider = r.PathValue("provider")
+
+	var since time.Time
+	if request.IncludeWipPactsSince != "" {
+		t, err := time.Parse(time.RFC3339, request.IncludeWipPactsSince)
+		if err != nil {
+			writeErrors(w, "includeWipPactsSince", "must be a date and time in ISO8601 format")
+			return
+		}
+		since = t
+	}
+	s.requests = append(s.requests, request)
+
+	pacts := make([]interface{}, 0)
+	for _, p := range s.selectPacts(request, since) {
+		notices := []map[string]string{{
+			"when": "before_verification",
+			"text": fmt.Sprintf("The pact at %s is being verified because it matches the following configured selection criterion: %s", p.url, strings.Join(p.descriptions, ", ")),
+		}}
+		if p.wip {
+			notices = append(notices, map[string]string{"when": "before_verification", "text": "This pact is being verified because it is a 'work in progress' pact (ie. it is the pact for the latest version of a consumer branch or tag, and has not yet been successfully verified)"})
+		}
+		if p.pending {
+			notices = append(notices, map[string]string{"when": "before_verification", "text": "This pact is in pending state for this version of " + request.Provider + " because a successful verification result has not yet been published. If this verification fails, it will not cause the overall build to fail."})
+		}
+
+		properties := map[string]interface{}{"notices": notices}
+		if request.IncludePendingStatus {
+			properties["pending"] = p.pending
+		}
+		if p.wip {
+			properties["wip"] = true
+		}
+		pacts = append(pacts, map[string]interface{}{
+			"shortDescription":       strings.Join(p.descriptions, ", "),
+			"verificationProperties": properties,
+			"_links": hal{
+				"self": named(p.url, "Pact", fmt.Sprintf("Pact between %s (%s) and %s", p.pact.Consumer, p.pact.ConsumerVersion, p.pact.Provider)),
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_embedded": map[string]interface{}{"pacts": pacts},
+		"_links": hal{
+			"self": link(s.URL+r.URL.Path, "Pacts to be verified"),
+		},
+	})
+}
+
+func (s *Server) getPact(w http.ResponseWriter, r *http.Request) {
+	pact := s.latestPact(r.PathValue("consumer"), r.PathValue("provider"), r.PathValue("pactVersion"))
+	if pact == nil {
+		writeNotFound(w)
+		return
+	}
+
+	s.writePact(w, http.StatusOK, pact)
+}
+
+func (s *Server) writePact(w http.ResponseWriter, status int, p *Pact) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(p.Content, &doc); err != nil {
+		writeErrors(w, "body", err.Error())
+		return
+	}
+	self := s.pactURL(p.Provider, p.Consumer, pactVersion(p.Content))
+	doc["_links"] = hal{
+		"self":                            link(self, "Pact"),
+		"pb:consumer":                     named(s.pacticipantURL(p.Consumer), "Consumer", p.Consumer),
+		"pb:consumer-version":             named(s.versionURL(p.Consumer, p.ConsumerVersion), "Consumer version", p.ConsumerVersion),
+		"pb:provider":                     named(s.pacticipantURL(p.Provider), "Provider", p.Provider),
+		"pb:publish-verification-results": link(self+"/verification-results", "Publish verification results"),
+	}
+
+	writeJSON(w, status, doc)
+}
+
+func (s *Server) publishVerificationResult(w http.ResponseWriter, r *http.Request) {
+	var request verificationResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeErrors(w, "body", err.Error())
+		return
+	}
+	if request.Success == nil {
+		writeErrors(w, "success", "can't be blank")
+		return
+	}
+	if request.ProviderApplicationVersion == "" {
+		writeErrors(w, "providerApplicationVersion", "can't be blank")
+		return
+	}
+
+	pact := s.latestPact(r.PathValue("consumer"), r.PathValue("provider"), r.PathValue("pactVersion"))
+	if pact == nil {
+		writeNotFound(w)
+		return
+	}
+
+	s.addVersion(pact.Provider, request.ProviderApplicationVersion, "", nil)
+	result := &verificationResult{
+		VerificationResult: VerificationResult{
+			Consumer:        pact.Consumer,
+			Provider:        pact.Provider,
+			ConsumerVersion: pact.ConsumerVersion,
+			ProviderVersion: request.ProviderApplicationVersion,
+			Success:         *request.Success,
+			BuildURL:        request.BuildURL,
+			TestResults:     request.TestResults,
+			CreatedAt:       time.Now(),
+		},
+		pactVersion: r.PathValue("pactVersion"),
+	}
+	s.results = append(s.results, result)
+
+	self := fmt.Sprintf("%s/verification-results/%d", s.pactURL(pact.Provider, pact.Consumer, result.pactVersion), len(s.results))
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"success":                    result.Success,
+		"providerApplicationVersion": result.ProviderVersion,
+		"_links": hal{
+			"self": link(self, "Verification result"),
+		},
+	})
+}
+
+func (s *Server) getPacticipant(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("pacticipant")
+	if !s.pacticipant(name) {
+		writeNotFound(w)
+		return
+	}
+
+	base := s.pacticipantURL(name)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":       name,
+		"mainBranch": s.mainBranches[name],
+		"_links": hal{
+			"self":              link(base, name),
+			"pb:version":        templated(base+"/versions/{version}", "Get, create or delete a version of "+name),
+			"pb:version-tag":    templated(base+"/versions/{version}/tags/{tag}", "Get, create or delete a tag for a version of "+name),
+			"pb:branch-version": templated(base+"/branches/{branch}/versions/{version}", "Get or add/create a version for a branch of "+name),
+		},
+	})
+}
+
+func (s *Server) addBranchVersion(w http.ResponseWriter, r *http.Request) {
+	s.addVersion(r.PathValue("pacticipant"), r.PathValue("version"), r.PathValue("branch"), nil)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_links": hal{"self": link(s.URL+r.URL.EscapedPath(), "Branch version")},
+	})
+}
+
+func (s *Server) addVersionTag(w http.ResponseWriter, r *http.Request) {
+	s.addVersion(r.PathValue("pacticipant"), r.PathValue("version"), "", []string{r.PathValue("tag")})
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"name":   r.PathValue("tag"),
+		"_links": hal{"self": link(s.URL+r.URL.EscapedPath(), "Tag")},
+	})
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request) {
+	v := s.version(r.PathValue("pacticipant"), r.PathValue("version"))
+	if v == nil {
+		writeNotFound(w)
+		return
+	}
+
+	base := s.versionURL(v.Pacticipant, v.Number)
+	deployments := make([]hal, 0, len(s.environments))
+	releases := make([]hal, 0, len(s.environments))
+	for _, environment := range s.environments {
+		title := fmt.Sprintf("Record deployment to %s", environment)
+		deployments = append(deployments, named(base+"/deployed-versions/environment/"+url.PathEscape(environment), title, environment))
+		title = fmt.Sprintf("Record release to %s", environment)
+		releases = append(releases, named(base+"/released-versions/environment/"+url.PathEscape(environment), title, environment))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"number":    v.Number,
+		"branch":    v.Branch,
+		"tags":      append([]string{}, v.Tags...),
+		"createdAt": v.CreatedAt.Format(time.RFC3339),
+		"_links": hal{
+			"self":                 link(base, "Version"),
+			"pb:pacticipant":       named(s.pacticipantURL(v.Pacticipant), "Pacticipant", v.Pacticipant),
+			"pb:record-deployment": deployments,
+			"pb:record-release":    releases,
+		},
+	})
+}
+
+func (s *Server) recordDeployedVersion(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ApplicationInstance string `json:"applicationInstance"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.recordVersion(w, r, Deployment{ApplicationInstance: body.ApplicationInstance})
+}
+
+func (s *Server) recordReleasedVersion(w http.ResponseWriter, r *http.Request) {
+	s.recordVersion(w, r, Deployment{Released: true})
+}
+
+func (s *Server) recordVersion(w http.ResponseWriter, r *http.Request, d Deployment) {
+	d.Pacticipant = r.PathValue("pacticipant")
+	d.Version = r.PathValue("version")
+	d.Environment = r.PathValue("environment")
+	if s.version(d.Pacticipant, d.Version) == nil || !contains(s.environments, d.Environment) {
+		writeNotFound(w)
+		return
+	}
+	s.recordDeployment(d)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"currentlyDeployed":   !d.Released,
+		"currentlySupported":  d.Released,
+		"applicationInstance": d.ApplicationInstance,
+		"_links":              hal{"self": link(s.URL+r.URL.EscapedPath(), "Deployed or released version")},
+	})
+}
+
+func (s *Server) pacticipantURL(pacticipant string) string {
+	return s.URL + "/pacticipants/" + url.PathEscape(pacticipant)
+}
+
+func (s *Server) versionURL(pacticipant string, version string) string {
+	return s.pacticipantURL(pacticipant) + "/versions/" + url.PathEscape(version)
+}
+
+func (s *Server) pactURL(provider string, consumer string, pactVersion string) string {
+	return fmt.Sprintf("%s/pacts/provider/%s/consumer/%s/pact-version/%s", s.URL, url.PathEscape(provider), url.PathEscape(consumer), pactVersion)
+}
+
+func link(href string, title string) hal {
+	return hal{"href": href, "title": title}
+}
+
+func named(href string, title string, name string) hal {
+	return hal{"href": href, "title": title, "name": name}
+}
+
+func templated(href string, title string) hal {
+	return hal{"href": href, "title": title, "templated": true}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/hal+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeErrors(w http.ResponseWriter, field string, message string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"errors": map[string][]string{field: {message}},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"message": "The requested document was not found on this server."}})
+}
@@ -0,0 +1,265 @@
+package brokertest
+
+import (
+	"fmt"
+	"time"
+)
+
+// selectedPact is a pact selected for verification, by the descriptions of the
+// selectors that selected it
+type selectedPact struct {
+	pact         *Pact
+	url          string
+	descriptions []string
+	pending      bool
+	wip          bool
+}
+
+// selectPacts selects the pacts of the provider for verification, as the Pact
+// Broker does. Pacts of different consumer versions with the same content are
+// verified once, as the pact of the latest of the versions. Without selectors, the
+// latest pact of each consumer is selected.
+func (s *Server) selectPacts(r PactsForVerificationRequest, wipSince time.Time) []*selectedPact {
+	var pacts []*Pact
+	for _, p := range s.pacts {
+		if p.Provider == r.Provider {
+			pacts = append(pacts, p)
+		}
+	}
+
+	selectors := r.ConsumerVersionSelectors
+	if len(selectors) == 0 {
+		selectors = []Selector{{Latest: true}}
+	}
+
+	var selected []*selectedPact
+	byVersion := make(map[string]*selectedPact)
+	add := func(p *Pact, description string) *selectedPact {
+		version := pactVersion(p.Content)
+		key := p.Consumer + "/" + version
+		sp, ok := byVersion[key]
+		if !ok {
+			sp = &selectedPact{pact: p, url: s.pactURL(p.Provider, p.Consumer, version)}
+			byVersion[key] = sp
+			selected = append(selected, sp)
+		}
+		if p.CreatedAt.After(sp.pact.CreatedAt) {
+			sp.pact = p
+		}
+		if !contains(sp.descriptions, description) {
+			sp.descriptions = append(sp.descriptions, description)
+		}
+		return sp
+	}
+
+	for _, selector := range selectors {
+		for _, p := range s.selectorPacts(selector, pacts, r) {
+			add(p, selector.description(r))
+		}
+	}
+	for _, sp := range selected {
+		sp.pending = r.IncludePendingStatus && s.pending(sp.pact, r)
+	}
+
+	if r.IncludePendingStatus && !wipSince.IsZero() {
+		for _, p := range s.wipPacts(pacts, wipSince) {
+			if _, ok := byVersion[p.Consumer+"/"+pactVersion(p.Content)]; ok || !s.pending(p, r) {
+				continue
+			}
+			sp := add(p, "work in progress")
+			sp.pending = true
+			sp.wip = true
+		}
+	}
+
+	return selected
+}
+
+// selectorPacts returns the pacts selected by the selector
+func (s *Server) selectorPacts(selector Selector, pacts []*Pact, r PactsForVerificationRequest) []*Pact {
+	var candidates []*Pact
+	for _, p := range pacts {
+		if selector.Consumer == "" || p.Consumer == selector.Consumer {
+			candidates = append(candidates, p)
+		}
+	}
+
+	switch {
+	case selector.Deployed || selector.Released || selector.DeployedOrReleased || selector.Environment != "":
+		deployed := selector.Deployed || selector.DeployedOrReleased || !selector.Released
+		released := selector.Released || selector.DeployedOrReleased || !selector.Deployed
+		return s.filter(candidates, func(p *Pact, _ *Version) bool {
+			for _, d := range s.deployments {
+				if d.Pacticipant == p.Consumer && d.Version == p.ConsumerVersion &&
+					(selector.Environment == "" || d.Environment == selector.Environment) &&
+					((d.Released && released) || (!d.Released && deployed)) {
+					return true
+				}
+			}
+			return false
+		})
+	case selector.MainBranch:
+		return latest(s.filter(candidates, func(p *Pact, v *Version) bool {
+			return v.Branch != "" && v.Branch == s.mainBranches[p.Consumer]
+		}))
+	case selector.MatchingBranch:
+		return latest(s.filter(candidates, func(_ *Pact, v *Version) bool {
+			return r.ProviderVersionBranch != "" && v.Branch == r.ProviderVersionBranch
+		}))
+	case selector.Branch != "":
+		return latest(s.filter(candidates, func(_ *Pact, v *Version) bool {
+			return v.Branch == selector.Branch
+		}))
+	case selector.Tag != "":
+		tagged := s.filter(candidates, func(_ *Pact, v *Version) bool {
+			return contains(v.Tags, selector.Tag)
+		})
+		if len(tagged) == 0 && selector.FallbackTag != "" {
+			return latest(s.filter(candidates, func(_ *Pact, v *Version) bool {
+				return contains(v.Tags, selector.FallbackTag)
+			}))
+		}
+		if selector.Latest {
+			return latest(tagged)
+		}
+		return tagged
+	default:
+		return latest(candidates)
+	}
+}
+
+// wipPacts returns the pacts of the latest versions of each branch and tag of the
+// consumers, that were created since the time
+func (s *Server) wipPacts(pacts []*Pact, since time.Time) []*Pact {
+	var wip []*Pact
+	for _, p := range pacts {
+		v := s.version(p.Consumer, p.ConsumerVersion)
+		if v == nil || p.CreatedAt.Before(since) {
+			continue
+		}
+
+		isLatest := func(matches func(v *Version) bool) bool {
+			candidates := latest(s.filter(pacts, func(_ *Pact, other *Version) bool {
+				return matches(other)
+			}))
+			for _, c := range candidates {
+				if c == p {
+					return true
+				}
+			}
+			return false
+		}
+		if v.Branch != "" && isLatest(func(other *Version) bool { return other.Branch == v.Branch }) {
+			wip = append(wip, p)
+			continue
+		}
+		for _, tag := range v.Tags {
+			if isLatest(func(other *Version) bool { return contains(other.Tags, tag) }) {
+				wip = append(wip, p)
+				break
+			}
+		}
+	}
+
+	return wip
+}
+
+// pending returns whether the pact has not been successfully verified by a version
+// of the provider from the branch (or with one of the tags) of the request
+func (s *Server) pending(p *Pact, r PactsForVerificationRequest) bool {
+	version := pactVersion(p.Content)
+	for _, result := range s.results {
+		if result.pactVersion != version || result.Provider != p.Provider || !result.Success {
+			continue
+		}
+		if r.ProviderVersionBranch == "" && len(r.ProviderVersionTags) == 0 {
+			return false
+		}
+
+		v := s.version(result.Provider, result.ProviderVersion)
+		if v == nil {
+			continue
+		}
+		if r.ProviderVersionBranch != "" && v.Branch == r.ProviderVersionBranch {
+			return false
+		}
+		for _, tag := range r.ProviderVersionTags {
+			if contains(v.Tags, tag) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// filter returns the pacts for which the function of the pact and its consumer
+// version is true
+func (s *Server) filter(pacts []*Pact, f func(p *Pact, v *Version) bool) []*Pact {
+	var filtered []*Pact
+	for _, p := range pacts {
+		if v := s.version(p.Consumer, p.ConsumerVersion); v != nil && f(p, v) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+// latest returns the latest of the pacts of each consumer
+func latest(pacts []*Pact) []*Pact {
+	var consumers []string
+	latest := make(map[string]*Pact)
+	for _, p := range pacts {
+		if _, ok := latest[p.Consumer]; !ok {
+			consumers = append(consumers, p.Consumer)
+		}
+		latest[p.Consumer] = p
+	}
+
+	result := make([]*Pact, 0, len(consumers))
+	for _, c := range consumers {
+		result = append(result, latest[c])
+	}
+
+	return result
+}
+
+// description describes the selector as the Pact Broker does, e.g. "latest from
+// branch main"
+func (selector Selector) description(r PactsForVerificationRequest) string {
+	var description string
+	switch {
+	case selector.Deployed || selector.Released || selector.DeployedOrReleased || selector.Environment != "":
+		switch {
+		case selector.Deployed && !selector.Released && !selector.DeployedOrReleased:
+			description = "currently deployed"
+		case selector.Released && !selector.Deployed && !selector.DeployedOrReleased:
+			description = "currently released"
+		default:
+			description = "currently deployed or released"
+		}
+		if selector.Environment != "" {
+			description += " to " + selector.Environment
+		}
+	case selector.MainBranch:
+		description = "latest from main branch"
+	case selector.MatchingBranch:
+		description = fmt.Sprintf("latest from branch %s matching the provider branch", r.ProviderVersionBranch)
+	case selector.Branch != "":
+		description = "latest from branch " + selector.Branch
+	case selector.Tag != "" && selector.FallbackTag != "":
+		description = fmt.Sprintf("latest with tag %s (or fallback tag %s)", selector.Tag, selector.FallbackTag)
+	case selector.Tag != "" && selector.Latest:
+		description = "latest with tag " + selector.Tag
+	case selector.Tag != "":
+		description = "all with tag " + selector.Tag
+	default:
+		description = "latest"
+	}
+	if selector.Consumer != "" {
+		description += " of " + selector.Consumer
+	}
+
+	return description
+}
@@ -0,0 +1,176 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// CanIDeployRequest asks whether a version of a pacticipant may be deployed to an
+// environment, i.e. whether it has successfully verified pacts with the versions of
+// the other pacticipants deployed there
+type CanIDeployRequest struct {
+	Pacticipant string
+	Version     string
+	Environment string
+
+	// RetryWhileUnknown asks again, every RetryInterval until the Timeout, while the
+	// answer or any of the verification results it is based on is unknown (e.g. while
+	// the provider is still verifying the pact)
+	RetryWhileUnknown bool
+
+	// RetryInterval is the time between retries. Defaults to 10s.
+	RetryInterval time.Duration
+
+	// Timeout bounds the time spent retrying. Defaults to 5 minutes.
+	Timeout time.Duration
+}
+
+// CanIDeployResult is the answer of the broker, and the verification results that
+// it is based on
+type CanIDeployResult struct {
+	// Deployable is nil if it is not yet known whether the version may be deployed
+	Deployable *bool  `json:"deployable"`
+	Reason     string `json:"reason"`
+
+	// Success, Failed and Unknown are the number of verification results of each kind
+	Success int `json:"success"`
+	Failed  int `json:"failed"`
+	Unknown int `json:"unknown"`
+
+	Notices []Notice    `json:"notices"`
+	Matrix  []MatrixRow `json:"matrix"`
+}
+
+// Answer returns yes, no or unknown
+func (r *CanIDeployResult) Answer() string {
+	switch {
+	case r.Deployable == nil:
+		return "unknown"
+	case *r.Deployable:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+// MatrixRow is the verification result of a pact between versions of a consumer and
+// provider
+type MatrixRow struct {
+	Consumer        string `json:"consumer"`
+	ConsumerVersion string `json:"consumerVersion"`
+	Provider        string `json:"provider"`
+	ProviderVersion string `json:"providerVersion,omitempty"`
+
+	// Success is nil if the pact has not been verified by the provider version
+	Success *bool `json:"success"`
+
+	// VerificationURL is the URL of the verification result, if any
+	VerificationURL string `json:"verificationUrl,omitempty"`
+}
+
+type matrixResponse struct {
+	Summary struct {
+		Deployable *bool  `json:"deployable"`
+		Reason     string `json:"reason"`
+		Success    int    `json:"success"`
+		Failed     int    `json:"failed"`
+		Unknown    int    `json:"unknown"`
+	} `json:"summary"`
+	Notices []Notice `json:"notices"`
+	Matrix  []struct {
+		Consumer struct {
+			Name    string `json:"name"`
+			Version struct {
+				Number string `json:"number"`
+			} `json:"version"`
+		} `json:"consumer"`
+		Provider struct {
+			Name    string `json:"name"`
+			Version *struct {
+				Number string `json:"number"`
+			} `json:"version"`
+		} `json:"provider"`
+		VerificationResult *struct {
+			Success bool            `json:"success"`
+			Links   map[string]link `json:"_links"`
+		} `json:"verificationResult"`
+	} `json:"matrix"`
+}
+
+// CanIDeploy asks the broker whether the version may be deployed to the environment
+func (c *Client) CanIDeploy(ctx context.Context, r CanIDeployRequest) (*CanIDeployResult, error) {
+	if r.Pacticipant == "" || r.Version == "" || r.Environment == "" {
+		return nil, errors.New("a pacticipant, version and environment must be given")
+	}
+	if r.RetryInterval <= 0 {
+		r.RetryInterval = 10 * time.Second
+	}
+	if r.Timeout <= 0 {
+		r.Timeout = 5 * time.Minute
+	}
+
+	href, err := c.index(ctx, "pb:can-i-deploy-pacticipant-version-to-environment", map[string]string{
+		"pacticipant": r.Pacticipant,
+		"version":     r.Version,
+		"environment": r.Environment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(r.Timeout)
+	for {
+		var res matrixResponse
+		if err := c.send(ctx, http.MethodGet, href, nil, &res); err != nil {
+			return nil, err
+		}
+		result := res.result()
+
+		known := result.Deployable != nil && result.Unknown == 0
+		if !r.RetryWhileUnknown || known || time.Now().Add(r.RetryInterval).After(deadline) {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(r.RetryInterval):
+		}
+	}
+}
+
+func (res *matrixResponse) result() *CanIDeployResult {
+	result := &CanIDeployResult{
+		Deployable: res.Summary.Deployable,
+		Reason:     res.Summary.Reason,
+		Success:    res.Summary.Success,
+		Failed:     res.Summary.Failed,
+		Unknown:    res.Summary.Unknown,
+		Notices:    res.Notices,
+		Matrix:     make([]MatrixRow, 0, len(res.Matrix)),
+	}
+	if result.Notices == nil {
+		result.Notices = make([]Notice, 0)
+	}
+
+	for _, m := range res.Matrix {
+		row := MatrixRow{
+			Consumer:        m.Consumer.Name,
+			ConsumerVersion: m.Consumer.Version.Number,
+			Provider:        m.Provider.Name,
+		}
+		if m.Provider.Version != nil {
+			row.ProviderVersion = m.Provider.Version.Number
+		}
+		if v := m.VerificationResult; v != nil {
+			success := v.Success
+			row.Success = &success
+			row.VerificationURL = v.Links["self"].Href
+		}
+		result.Matrix = append(result.Matrix, row)
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package broker
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanIDeploy(t *testing.T) {
+	yes, no := true, false
+	ctx := context.Background()
+	request := CanIDeployRequest{Pacticipant: "web", Version: "1.0.0+abc", Environment: "production"}
+
+	tests := []struct {
+		name     string
+		answers  []*bool
+		retry    bool
+		want     string
+		requests int
+	}{
+		{name: "deployable", answers: []*bool{&yes}, want: "yes", requests: 1},
+		{name: "not deployable", answers: []*bool{&no}, want: "no", requests: 1},
+		{name: "unknown", answers: []*bool{nil, &yes}, want: "unknown", requests: 1},
+		{name: "retry while unknown", answers: []*bool{nil, nil, &yes}, retry: true, want: "yes", requests: 3},
+		{name: "retry until the timeout", answers: []*bool{nil}, retry: true, want: "unknown", requests: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newFakeBroker(t)
+			b.answers = tt.answers
+			client, err := NewClient(Config{URL: b.URL})
+			assert.NoError(t, err)
+
+			r := request
+			r.RetryWhileUnknown = tt.retry
+			r.RetryInterval = 10 * time.Millisecond
+			r.Timeout = 35 * time.Millisecond
+
+			result, err := client.CanIDeploy(ctx, r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Answer())
+			assert.Len(t, b.canIDeploy, tt.requests)
+			assert.Equal(t, url.Values{"pacticipant": {"web"}, "version": {"1.0.0+abc"}, "environment": {"production"}}, b.canIDeploy[0])
+		})
+	}
+}
+
+func TestCanIDeployResult(t *testing.T) {
+	yes := true
+	b := newFakeBroker(t)
+	b.answers = []*bool{&yes}
+	client, err := NewClient(Config{URL: b.URL})
+	assert.NoError(t, err)
+
+	result, err := client.CanIDeploy(context.Background(), CanIDeployRequest{Pacticipant: "web", Version: "1.0.0", Environment: "production"})
+	assert.NoError(t, err)
+	assert.Equal(t, &CanIDeployResult{
+		Deployable: &yes,
+		Reason:     "All required verification results are published and successful",
+		Success:    1,
+		Notices:    []Notice{},
+		Matrix: []MatrixRow{
+			{Consumer: "web", ConsumerVersion: "1.0.0", Provider: "users", ProviderVersion: "2.0.0", Success: &yes, VerificationURL: b.URL + "/verification-results/1"},
+		},
+	}, result)
+
+	_, err = client.CanIDeploy(context.Background(), CanIDeployRequest{Pacticipant: "web"})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.answers = []*bool{nil}
+	_, err = client.CanIDeploy(ctx, CanIDeployRequest{Pacticipant: "web", Version: "1.0.0", Environment: "production", RetryWhileUnknown: true})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,301 @@
+// Package broker is a client of the Pact Broker (https://docs.pact.io/pact_broker)
+// and PactFlow, for publishing pacts, asking whether a version can be deployed and
+// recording deployments from Go.
+//
+// The client follows the HAL links of the index of the broker, so it works with any
+// broker that supports the relations it uses (e.g. pb:publish-contracts, from Pact
+// Broker 2.86.0).
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config is the configuration of a Client. Fields that are not given are read from
+// the PACT_BROKER_* environment variables.
+type Config struct {
+	// URL of the broker, e.g. https://broker.example.com. It may also be given by the
+	// PACT_BROKER_URL environment variable.
+	URL string
+
+	// Username and Password for basic authentication. They may also be given by the
+	// PACT_BROKER_USERNAME and PACT_BROKER_PASSWORD environment variables.
+	Username string
+	Password string
+
+	// Token for bearer authentication, e.g. a PactFlow API token. It may also be given
+	// by the PACT_BROKER_TOKEN environment variable, and is used instead of basic
+	// authentication if both are given.
+	Token string
+
+	// HTTPClient sends the requests to the broker. Defaults to a client with a 30s
+	// timeout.
+	HTTPClient *http.Client
+}
+
+// Client is a client of a Pact Broker
+type Client struct {
+	url        *url.URL
+	username   string
+	password   string
+	token      string
+	httpClient *http.Client
+
+	// detect detects the version of the code publishing pacts
+	detect func() VersionInfo
+}
+
+// NewClient returns a client of the broker of the configuration
+func NewClient(config Config) (*Client, error) {
+	brokerURL := valueOrFromEnvironment(config.URL, "PACT_BROKER_URL")
+	if brokerURL == "" {
+		return nil, errors.New("a broker URL must be given, or set with the PACT_BROKER_URL environment variable")
+	}
+	u, err := url.Parse(brokerURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid broker URL %q, expected an http or https URL", brokerURL)
+	}
+
+	c := &Client{
+		url:        u,
+		username:   valueOrFromEnvironment(config.Username, "PACT_BROKER_USERNAME"),
+		password:   valueOrFromEnvironment(config.Password, "PACT_BROKER_PASSWORD"),
+		token:      valueOrFromEnvironment(config.Token, "PACT_BROKER_TOKEN"),
+		httpClient: config.HTTPClient,
+		detect:     DetectVersion,
+	}
+	if (c.username == "") != (c.password == "") {
+		return nil, errors.New("both a username and password must be given for basic authentication, if one is given")
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return c, nil
+}
+
+// URL returns the URL of the broker
+func (c *Client) URL() string {
+	return c.url.String()
+}
+
+// Error is returned when the broker responds to a request with an error status
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+
+	// Messages are the error messages of the response, if any
+	Messages []string
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("broker request %s %s failed with status %d", e.Method, e.URL, e.StatusCode)
+	if len(e.Messages) == 0 {
+		return message
+	}
+
+	return message + ": " + strings.Join(e.Messages, "; ")
+}
+
+// link is a HAL link
+type link struct {
+	Href string `json:"href"`
+
+	// Name distinguishes links of the same relation, e.g. by environment
+	Name string `json:"name,omitempty"`
+}
+
+type resource struct {
+	Links map[string]json.RawMessage `json:"_links"`
+}
+
+// links returns the links of the relation, which may be a single link or a list
+func (r *resource) links(rel string) []link {
+	raw, ok := r.Links[rel]
+	if !ok {
+		return nil
+	}
+
+	var l link
+	if err := json.Unmarshal(raw, &l); err == nil {
+		return []link{l}
+	}
+	var list []link
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil
+	}
+
+	return list
+}
+
+// link returns the (first) link of the relation, with the parameters of a templated
+// link expanded
+func (r *resource) link(rel string, params map[string]string) (string, bool) {
+	links := r.links(rel)
+	if len(links) == 0 {
+		return "", false
+	}
+
+	return expand(links[0].Href, params), true
+}
+
+// expand expands the parameters of a templated link, e.g.
+// /pacticipants/{pacticipant}/versions/{version}
+func expand(href string, params map[string]string) string {
+	path, query, hasQuery := strings.Cut(href, "?")
+	for k, v := range params {
+		path = strings.ReplaceAll(path, "{"+k+"}", url.PathEscape(v))
+		query = strings.ReplaceAll(query, "{"+k+"}", url.QueryEscape(v))
+	}
+	if !hasQuery {
+		return path
+	}
+
+	return path + "?" + query
+}
+
+// index returns the link of the relation from the index of the broker
+func (c *Client) index(ctx context.Context, rel string, params map[string]string) (string, error) {
+	var index resource
+	if err := c.send(ctx, http.MethodGet, c.url.String(), nil, &index); err != nil {
+		return "", err
+	}
+
+	href, ok := index.link(rel, params)
+	if !ok {
+		return "", fmt.Errorf("the broker at %s does not support %s, it may need to be upgraded", c.url, rel)
+	}
+
+	return href, nil
+}
+
+// send sends a request to the broker with the body (if any) as JSON, and decodes the
+// JSON response into out (if given)
+func (c *Client) send(ctx context.Context, method string, href string, body interface{}, out interface{}) error {
+	u, err := c.url.Parse(href)
+	if err != nil {
+		return fmt.Errorf("invalid broker URL %q: %w", href, err)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/hal+json, application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach the broker: %w", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= 400 {
+		return &Error{
+			Method:     method,
+			URL:        u.String(),
+			StatusCode: res.StatusCode,
+			Messages:   errorMessages(data),
+		}
+	}
+
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unable to read the response of the broker to %s %s: %w", method, u, err)
+	}
+
+	return nil
+}
+
+// errorMessages reads the messages of an error response, which may be e.g.
+// {"errors": {"field": ["message"]}}, {"errors": ["message"]}, or
+// {"error": {"message": "message"}}, and may have error notices
+func errorMessages(data []byte) []string {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		if text := strings.TrimSpace(string(data)); text != "" && len(text) < 500 {
+			return []string{text}
+		}
+		return nil
+	}
+
+	var messages []string
+	var collect func(prefix string, v interface{})
+	collect = func(prefix string, v interface{}) {
+		switch e := v.(type) {
+		case string:
+			messages = append(messages, prefix+e)
+		case []interface{}:
+			for _, item := range e {
+				collect(prefix, item)
+			}
+		case map[string]interface{}:
+			if message, ok := e["message"].(string); ok {
+				messages = append(messages, prefix+message)
+				return
+			}
+			keys := make([]string, 0, len(e))
+			for k := range e {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				collect(prefix+k+": ", e[k])
+			}
+		}
+	}
+	collect("", doc["errors"])
+	collect("", doc["error"])
+
+	for _, n := range readNotices(doc) {
+		if n.Type == "error" {
+			messages = append(messages, n.Text)
+		}
+	}
+
+	return messages
+}
+
+func valueOrFromEnvironment(value string, envKey string) string {
+	if value != "" {
+		return value
+	}
+
+	return os.Getenv(envKey)
+}
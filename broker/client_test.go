@@ -0,0 +1,156 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker is a broker that records the requests made to it. Versions of
+// pacticipants are known to it if they are in versions (e.g. "web/1.0.0"), and
+// can-i-deploy answers with each of answers in turn, and then the last.
+type fakeBroker struct {
+	*httptest.Server
+	authorization []string
+
+	published   []publishContracts
+	canIDeploy  []url.Values
+	answers     []*bool
+	versions    map[string]bool
+	deployments []string
+	releases    []string
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	b := &fakeBroker{versions: map[string]bool{"web/1.0.0": true}}
+	mux := http.NewServeMux()
+	write := func(w http.ResponseWriter, status int, body interface{}) {
+		w.Header().Set("Content-Type", "application/hal+json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+	links := func(rels map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"_links": rels}
+	}
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		write(w, http.StatusOK, links(map[string]interface{}{
+			"pb:publish-contracts": map[string]interface{}{"href": b.URL + "/contracts/publish", "title": "Publish contracts"},
+			"pb:can-i-deploy-pacticipant-version-to-environment": map[string]interface{}{
+				"href":      b.URL + "/can-i-deploy?pacticipant={pacticipant}&version={version}&environment={environment}",
+				"templated": true,
+			},
+			"pb:pacticipant-version": map[string]interface{}{
+				"href":      b.URL + "/pacticipants/{pacticipant}/versions/{version}",
+				"templated": true,
+			},
+		}))
+	})
+	mux.HandleFunc("POST /contracts/publish", func(w http.ResponseWriter, r *http.Request) {
+		var request publishContracts
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if request.PacticipantName == "invalid" {
+			write(w, http.StatusBadRequest, map[string]interface{}{
+				"errors":  map[string]interface{}{"pacticipantVersionNumber": []string{"can't be blank"}},
+				"notices": []Notice{{Type: "error", Text: "Publishing failed"}},
+			})
+			return
+		}
+		b.published = append(b.published, request)
+		write(w, http.StatusOK, map[string]interface{}{
+			"notices": []Notice{{Type: "success", Text: fmt.Sprintf("Created %s version %s", request.PacticipantName, request.PacticipantVersionNumber)}},
+		})
+	})
+	mux.HandleFunc("GET /can-i-deploy", func(w http.ResponseWriter, r *http.Request) {
+		b.canIDeploy = append(b.canIDeploy, r.URL.Query())
+		var deployable *bool
+		if len(b.answers) > 0 {
+			deployable = b.answers[0]
+			if len(b.answers) > 1 {
+				b.answers = b.answers[1:]
+			}
+		}
+		verification := interface{}(nil)
+		reason := "There is no verified pact between web (1.0.0) and users"
+		if deployable != nil {
+			verification = map[string]interface{}{"success": *deployable, "_links": map[string]interface{}{"self": map[string]string{"href": b.URL + "/verification-results/1"}}}
+			reason = "All required verification results are published and successful"
+		}
+		write(w, http.StatusOK, map[string]interface{}{
+			"summary": map[string]interface{}{"deployable": deployable, "reason": reason, "success": 1, "failed": 0, "unknown": 0},
+			"matrix": []interface{}{map[string]interface{}{
+				"consumer":           map[string]interface{}{"name": "web", "version": map[string]string{"number": "1.0.0"}},
+				"provider":           map[string]interface{}{"name": "users", "version": map[string]string{"number": "2.0.0"}},
+				"verificationResult": verification,
+			}},
+		})
+	})
+	mux.HandleFunc("GET /pacticipants/{pacticipant}/versions/{version}", func(w http.ResponseWriter, r *http.Request) {
+		base := b.URL + r.URL.Path
+		if !b.versions[r.PathValue("pacticipant")+"/"+r.PathValue("version")] {
+			write(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"message": "Not found"}})
+			return
+		}
+		write(w, http.StatusOK, links(map[string]interface{}{
+			"pb:record-deployment": []map[string]string{
+				{"name": "production", "href": base + "/deployed-versions/environment/1"},
+				{"name": "test", "href": base + "/deployed-versions/environment/2"},
+			},
+			"pb:record-release": []map[string]string{
+				{"name": "production", "href": base + "/released-versions/environment/1"},
+			},
+		}))
+	})
+	mux.HandleFunc("POST /pacticipants/{pacticipant}/versions/{version}/{kind}/environment/{environment}", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		record := fmt.Sprintf("%s %s %s %v", r.PathValue("pacticipant"), r.PathValue("version"), r.PathValue("environment"), body["applicationInstance"])
+		if r.PathValue("kind") == "deployed-versions" {
+			b.deployments = append(b.deployments, record)
+		} else {
+			b.releases = append(b.releases, record)
+		}
+		write(w, http.StatusCreated, map[string]interface{}{})
+	})
+
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.authorization = append(b.authorization, r.Header.Get("Authorization"))
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(b.Close)
+
+	return b
+}
+
+func TestNewClient(t *testing.T) {
+	t.Setenv("PACT_BROKER_URL", "")
+	t.Setenv("PACT_BROKER_USERNAME", "")
+	t.Setenv("PACT_BROKER_PASSWORD", "")
+
+	_, err := NewClient(Config{})
+	assert.Error(t, err)
+
+	_, err = NewClient(Config{URL: "broker.example.com"})
+	assert.Error(t, err)
+
+	_, err = NewClient(Config{URL: "https://broker.example.com", Username: "user"})
+	assert.Error(t, err)
+
+	_, err = NewClient(Config{URL: "https://broker.example.com", Username: "user", Password: "pass"})
+	assert.NoError(t, err)
+}
+
+func TestExpand(t *testing.T) {
+	assert.Equal(t, "/pacticipants/my%20app/versions/1.0.0+abc",
+		expand("/pacticipants/{pacticipant}/versions/{version}", map[string]string{"pacticipant": "my app", "version": "1.0.0+abc"}))
+	assert.Equal(t, "/can-i-deploy?pacticipant=my+app&version=1.0.0%2Babc",
+		expand("/can-i-deploy?pacticipant={pacticipant}&version={version}", map[string]string{"pacticipant": "my app", "version": "1.0.0+abc"}))
+}
@@ -0,0 +1,77 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// RecordDeploymentRequest records that a version of a pacticipant was deployed to
+// an environment, replacing the version previously deployed there
+type RecordDeploymentRequest struct {
+	Pacticipant string
+	Version     string
+	Environment string
+
+	// ApplicationInstance distinguishes deployments of the pacticipant to the same
+	// environment, e.g. one per customer or region. It is optional.
+	ApplicationInstance string
+}
+
+// RecordReleaseRequest records that a version of a pacticipant was released to an
+// environment (e.g. a mobile app store), where it is supported alongside the other
+// released versions
+type RecordReleaseRequest struct {
+	Pacticipant string
+	Version     string
+	Environment string
+}
+
+// RecordDeployment records the deployment of the version to the environment
+func (c *Client) RecordDeployment(ctx context.Context, r RecordDeploymentRequest) error {
+	body := map[string]interface{}{}
+	if r.ApplicationInstance != "" {
+		body["applicationInstance"] = r.ApplicationInstance
+	}
+
+	return c.record(ctx, "pb:record-deployment", r.Pacticipant, r.Version, r.Environment, body)
+}
+
+// RecordRelease records the release of the version to the environment
+func (c *Client) RecordRelease(ctx context.Context, r RecordReleaseRequest) error {
+	return c.record(ctx, "pb:record-release", r.Pacticipant, r.Version, r.Environment, map[string]interface{}{})
+}
+
+// record follows the link of the relation to the environment from the pacticipant
+// version, e.g. pb:record-deployment, which has a link for each environment
+func (c *Client) record(ctx context.Context, rel string, pacticipant string, version string, environment string, body interface{}) error {
+	if pacticipant == "" || version == "" || environment == "" {
+		return errors.New("a pacticipant, version and environment must be given")
+	}
+
+	href, err := c.index(ctx, "pb:pacticipant-version", map[string]string{
+		"pacticipant": pacticipant,
+		"version":     version,
+	})
+	if err != nil {
+		return err
+	}
+
+	var v resource
+	if err := c.send(ctx, http.MethodGet, href, nil, &v); err != nil {
+		var brokerErr *Error
+		if errors.As(err, &brokerErr) && brokerErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("version %s of %s was not found in the broker", version, pacticipant)
+		}
+		return err
+	}
+
+	for _, l := range v.links(rel) {
+		if l.Name == environment {
+			return c.send(ctx, http.MethodPost, l.Href, body, nil)
+		}
+	}
+
+	return fmt.Errorf("the environment %s was not found in the broker", environment)
+}
@@ -0,0 +1,40 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDeployment(t *testing.T) {
+	b := newFakeBroker(t)
+	client, err := NewClient(Config{URL: b.URL, Username: "user", Password: "pass"})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, client.RecordDeployment(ctx, RecordDeploymentRequest{Pacticipant: "web", Version: "1.0.0", Environment: "production"}))
+	assert.NoError(t, client.RecordDeployment(ctx, RecordDeploymentRequest{Pacticipant: "web", Version: "1.0.0", Environment: "test", ApplicationInstance: "eu"}))
+	assert.Equal(t, []string{"web 1.0.0 1 <nil>", "web 1.0.0 2 eu"}, b.deployments)
+
+	err = client.RecordDeployment(ctx, RecordDeploymentRequest{Pacticipant: "web", Version: "2.0.0", Environment: "production"})
+	assert.EqualError(t, err, "version 2.0.0 of web was not found in the broker")
+
+	err = client.RecordDeployment(ctx, RecordDeploymentRequest{Pacticipant: "web", Version: "1.0.0", Environment: "staging"})
+	assert.EqualError(t, err, "the environment staging was not found in the broker")
+
+	assert.Error(t, client.RecordDeployment(ctx, RecordDeploymentRequest{Pacticipant: "web"}))
+}
+
+func TestRecordRelease(t *testing.T) {
+	b := newFakeBroker(t)
+	client, err := NewClient(Config{URL: b.URL})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, client.RecordRelease(ctx, RecordReleaseRequest{Pacticipant: "web", Version: "1.0.0", Environment: "production"}))
+	assert.Equal(t, []string{"web 1.0.0 1 <nil>"}, b.releases)
+
+	err = client.RecordRelease(ctx, RecordReleaseRequest{Pacticipant: "web", Version: "1.0.0", Environment: "test"})
+	assert.EqualError(t, err, "the environment test was not found in the broker")
+}
@@ -0,0 +1,214 @@
+package broker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// PublishRequest is the pacts to publish, and the version of the consumer they are
+// published for. The version, branch, tags and build URL are detected (see
+// DetectVersion) if they are not given.
+type PublishRequest struct {
+	// PactFiles are the pact files, or directories of pact files (*.json), to publish
+	PactFiles []string
+
+	// ConsumerVersion is the version of the consumer, e.g. the git commit. Defaults to
+	// the detected version.
+	ConsumerVersion string
+
+	// Branch of the consumer version. Defaults to the detected branch.
+	Branch string
+
+	// Tags of the consumer version. If nil, defaults to the detected tags.
+	Tags []string
+
+	// BuildURL is the URL of the CI build that published the pacts. Defaults to the
+	// detected build URL.
+	BuildURL string
+}
+
+// PublishResult is the result of publishing the pacts of a consumer
+type PublishResult struct {
+	Consumer        string   `json:"consumer"`
+	ConsumerVersion string   `json:"consumerVersion"`
+	Branch          string   `json:"branch,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+
+	// Pacts are the paths of the pact files that were published
+	Pacts []string `json:"pacts"`
+
+	// Notices are the messages of the broker about the published pacts, e.g. whether
+	// the pact changed
+	Notices []Notice `json:"notices"`
+}
+
+// Notice is a message of the broker, e.g. {"type": "success", "text": "..."}
+type Notice struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// contract is a pact in a request to publish contracts
+type contract struct {
+	ConsumerName  string `json:"consumerName"`
+	ProviderName  string `json:"providerName"`
+	Specification string `json:"specification"`
+	ContentType   string `json:"contentType"`
+	Content       string `json:"content"`
+}
+
+type publishContracts struct {
+	PacticipantName          string     `json:"pacticipantName"`
+	PacticipantVersionNumber string     `json:"pacticipantVersionNumber"`
+	Branch                   string     `json:"branch,omitempty"`
+	Tags                     []string   `json:"tags,omitempty"`
+	BuildURL                 string     `json:"buildUrl,omitempty"`
+	Contracts                []contract `json:"contracts"`
+}
+
+// Publish publishes the pacts to the broker. The pacts of each consumer are
+// published together, in the order of the consumer names.
+func (c *Client) Publish(ctx context.Context, r PublishRequest) ([]*PublishResult, error) {
+	files, err := pactFiles(r.PactFiles)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("there are no pact files to publish")
+	}
+
+	if r.ConsumerVersion == "" || r.Branch == "" || r.Tags == nil || r.BuildURL == "" {
+		detected := c.detect()
+		if r.ConsumerVersion == "" {
+			r.ConsumerVersion = detected.Version
+		}
+		if r.Branch == "" {
+			r.Branch = detected.Branch
+		}
+		if r.Tags == nil {
+			r.Tags = detected.Tags
+		}
+		if r.BuildURL == "" {
+			r.BuildURL = detected.BuildURL
+		}
+	}
+	if r.ConsumerVersion == "" {
+		return nil, errors.New("a consumer version must be given, as it could not be detected from git or the build information")
+	}
+
+	consumers := make(map[string]*publishContracts)
+	published := make(map[string][]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		pact, err := models.ParsePactFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read pact file %s: %w", file, err)
+		}
+		if pact.Consumer.Name == "" || pact.Provider.Name == "" {
+			return nil, fmt.Errorf("the pact file %s must name its consumer and provider", file)
+		}
+
+		request, ok := consumers[pact.Consumer.Name]
+		if !ok {
+			request = &publishContracts{
+				PacticipantName:          pact.Consumer.Name,
+				PacticipantVersionNumber: r.ConsumerVersion,
+				Branch:                   r.Branch,
+				Tags:                     r.Tags,
+				BuildURL:                 r.BuildURL,
+			}
+			consumers[pact.Consumer.Name] = request
+		}
+		request.Contracts = append(request.Contracts, contract{
+			ConsumerName:  pact.Consumer.Name,
+			ProviderName:  pact.Provider.Name,
+			Specification: "pact",
+			ContentType:   "application/json",
+			Content:       base64.StdEncoding.EncodeToString(data),
+		})
+		published[pact.Consumer.Name] = append(published[pact.Consumer.Name], file)
+	}
+
+	href, err := c.index(ctx, "pb:publish-contracts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(consumers))
+	for name := range consumers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]*PublishResult, 0, len(names))
+	for _, name := range names {
+		var response map[string]interface{}
+		if err := c.send(ctx, http.MethodPost, href, consumers[name], &response); err != nil {
+			return results, fmt.Errorf("unable to publish the pacts of %s: %w", name, err)
+		}
+
+		results = append(results, &PublishResult{
+			Consumer:        name,
+			ConsumerVersion: r.ConsumerVersion,
+			Branch:          r.Branch,
+			Tags:            r.Tags,
+			Pacts:           published[name],
+			Notices:         readNotices(response),
+		})
+	}
+
+	return results, nil
+}
+
+// pactFiles returns the files at the paths, and the pact files (*.json) of the
+// directories at the paths
+func pactFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+func readNotices(doc map[string]interface{}) []Notice {
+	notices := make([]Notice, 0)
+	list, _ := doc["notices"].([]interface{})
+	for _, v := range list {
+		data, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		var n Notice
+		if err := json.Unmarshal(data, &n); err == nil {
+			notices = append(notices, n)
+		}
+	}
+
+	return notices
+}
@@ -0,0 +1,125 @@
+package broker
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePact(t *testing.T, dir string, consumer string, provider string) string {
+	path := filepath.Join(dir, consumer+"-"+provider+".json")
+	pact := `{"consumer": {"name": "` + consumer + `"}, "provider": {"name": "` + provider + `"}, "interactions": [], "metadata": {"pactSpecification": {"version": "4.0"}}}`
+	assert.NoError(t, os.WriteFile(path, []byte(pact), 0644))
+
+	return path
+}
+
+func TestPublish(t *testing.T) {
+	b := newFakeBroker(t)
+	dir := t.TempDir()
+	usersPact := writePact(t, dir, "web", "users")
+	ordersPact := writePact(t, dir, "web", "orders")
+	workerPact := writePact(t, dir, "worker", "users")
+
+	client, err := NewClient(Config{URL: b.URL, Token: "secret"})
+	assert.NoError(t, err)
+	client.detect = func() VersionInfo {
+		return VersionInfo{Version: "4c2f8a1", Branch: "main", Tags: []string{"v1.0.0"}, BuildURL: "https://ci.example.com/1"}
+	}
+
+	results, err := client.Publish(context.Background(), PublishRequest{PactFiles: []string{dir}, Branch: "feature"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*PublishResult{
+		{
+			Consumer:        "web",
+			ConsumerVersion: "4c2f8a1",
+			Branch:          "feature",
+			Tags:            []string{"v1.0.0"},
+			Pacts:           []string{ordersPact, usersPact},
+			Notices:         []Notice{{Type: "success", Text: "Created web version 4c2f8a1"}},
+		},
+		{
+			Consumer:        "worker",
+			ConsumerVersion: "4c2f8a1",
+			Branch:          "feature",
+			Tags:            []string{"v1.0.0"},
+			Pacts:           []string{workerPact},
+			Notices:         []Notice{{Type: "success", Text: "Created worker version 4c2f8a1"}},
+		},
+	}, results)
+
+	assert.Len(t, b.published, 2)
+	web := b.published[0]
+	assert.Equal(t, "web", web.PacticipantName)
+	assert.Equal(t, "https://ci.example.com/1", web.BuildURL)
+	assert.Len(t, web.Contracts, 2)
+	assert.Equal(t, "orders", web.Contracts[0].ProviderName)
+	assert.Equal(t, "pact", web.Contracts[0].Specification)
+	content, err := base64.StdEncoding.DecodeString(web.Contracts[0].Content)
+	assert.NoError(t, err)
+	data, _ := os.ReadFile(ordersPact)
+	assert.Equal(t, data, content)
+
+	for _, a := range b.authorization {
+		assert.Equal(t, "Bearer secret", a)
+	}
+}
+
+func TestPublishBasicAuthFromEnvironment(t *testing.T) {
+	b := newFakeBroker(t)
+	t.Setenv("PACT_BROKER_URL", b.URL)
+	t.Setenv("PACT_BROKER_USERNAME", "user")
+	t.Setenv("PACT_BROKER_PASSWORD", "pass")
+	t.Setenv("PACT_BROKER_TOKEN", "")
+
+	client, err := NewClient(Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, b.URL, client.URL())
+	client.detect = func() VersionInfo { return VersionInfo{} }
+
+	_, err = client.Publish(context.Background(), PublishRequest{PactFiles: []string{writePact(t, t.TempDir(), "web", "users")}, ConsumerVersion: "1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", b.published[0].PacticipantVersionNumber)
+	assert.Nil(t, b.published[0].Tags)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("user", "pass")
+	assert.Equal(t, req.Header.Get("Authorization"), b.authorization[0])
+}
+
+func TestPublishErrors(t *testing.T) {
+	b := newFakeBroker(t)
+	client, err := NewClient(Config{URL: b.URL, Token: "secret"})
+	assert.NoError(t, err)
+	client.detect = func() VersionInfo { return VersionInfo{} }
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	_, err = client.Publish(ctx, PublishRequest{PactFiles: []string{dir}, ConsumerVersion: "1"})
+	assert.EqualError(t, err, "there are no pact files to publish")
+
+	_, err = client.Publish(ctx, PublishRequest{PactFiles: []string{writePact(t, dir, "web", "users")}})
+	assert.Error(t, err)
+
+	_, err = client.Publish(ctx, PublishRequest{PactFiles: []string{writePact(t, t.TempDir(), "invalid", "users")}, ConsumerVersion: "1"})
+	var brokerErr *Error
+	assert.True(t, errors.As(err, &brokerErr))
+	assert.Equal(t, http.StatusBadRequest, brokerErr.StatusCode)
+	assert.Equal(t, []string{"pacticipantVersionNumber: can't be blank", "Publishing failed"}, brokerErr.Messages)
+
+	old := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"_links": {}}`))
+	}))
+	defer old.Close()
+	client, err = NewClient(Config{URL: old.URL})
+	assert.NoError(t, err)
+	_, err = client.Publish(ctx, PublishRequest{PactFiles: []string{dir}, ConsumerVersion: "1"})
+	assert.ErrorContains(t, err, "does not support pb:publish-contracts")
+}
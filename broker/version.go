@@ -0,0 +1,99 @@
+package broker
+
+import (
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+)
+
+// VersionInfo identifies the version of the code that publishes pacts
+type VersionInfo struct {
+	// Version is the git commit, e.g. 4c2f8a1...
+	Version string
+
+	// Branch is the git branch
+	Branch string
+
+	// Tags are the git tags of the commit
+	Tags []string
+
+	// BuildURL is the URL of the CI build
+	BuildURL string
+}
+
+// ciBranches are the environment variables of CI systems that give the branch being
+// built, which is not known to git if the commit is checked out directly
+var ciBranches = []string{
+	"GITHUB_HEAD_REF",        // GitHub Actions, for pull requests
+	"CI_COMMIT_REF_NAME",     // GitLab
+	"BUILDKITE_BRANCH",       // Buildkite
+	"CIRCLE_BRANCH",          // CircleCI
+	"BITBUCKET_BRANCH",       // Bitbucket Pipelines
+	"TRAVIS_BRANCH",          // Travis CI
+	"BUILD_SOURCEBRANCHNAME", // Azure Pipelines
+	"BRANCH_NAME",            // Jenkins (multibranch pipelines)
+	"GIT_BRANCH",             // Jenkins (git plugin)
+}
+
+// DetectVersion detects the version of the code in the working directory from git,
+// and the environment variables of common CI systems. If git is not available, the
+// version is read from the build information of the binary (see runtime/debug), as
+// built by `go build` in a git repository.
+func DetectVersion() VersionInfo {
+	return detectVersion(os.Getenv, runGit, debug.ReadBuildInfo)
+}
+
+func detectVersion(getenv func(string) string, git func(args ...string) (string, error), buildInfo func() (*debug.BuildInfo, bool)) VersionInfo {
+	info := VersionInfo{}
+
+	if commit, err := git("rev-parse", "HEAD"); err == nil {
+		info.Version = commit
+		if branch, err := git("rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+			info.Branch = branch
+		}
+		if tags, err := git("tag", "--points-at", "HEAD"); err == nil && tags != "" {
+			info.Tags = strings.Fields(tags)
+		}
+	} else if bi, ok := buildInfo(); ok {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" {
+				info.Version = s.Value
+			}
+		}
+	}
+
+	if getenv("GITHUB_REF_TYPE") == "branch" && getenv("GITHUB_REF_NAME") != "" && getenv("GITHUB_HEAD_REF") == "" {
+		info.Branch = getenv("GITHUB_REF_NAME")
+	}
+	for _, key := range ciBranches {
+		if branch := getenv(key); branch != "" {
+			info.Branch = strings.TrimPrefix(branch, "origin/")
+			break
+		}
+	}
+
+	switch {
+	case getenv("GITHUB_RUN_ID") != "":
+		info.BuildURL = getenv("GITHUB_SERVER_URL") + "/" + getenv("GITHUB_REPOSITORY") + "/actions/runs/" + getenv("GITHUB_RUN_ID")
+	case getenv("CI_JOB_URL") != "":
+		info.BuildURL = getenv("CI_JOB_URL")
+	case getenv("BUILDKITE_BUILD_URL") != "":
+		info.BuildURL = getenv("BUILDKITE_BUILD_URL")
+	case getenv("CIRCLE_BUILD_URL") != "":
+		info.BuildURL = getenv("CIRCLE_BUILD_URL")
+	case getenv("BUILD_URL") != "":
+		info.BuildURL = getenv("BUILD_URL")
+	}
+
+	return info
+}
+
+func runGit(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,68 @@
+package broker
+
+import (
+	"errors"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectVersion(t *testing.T) {
+	git := func(out map[string]string) func(args ...string) (string, error) {
+		return func(args ...string) (string, error) {
+			key := args[len(args)-2] + " " + args[len(args)-1]
+			if v, ok := out[key]; ok {
+				return v, nil
+			}
+			return "", errors.New("not a git repository")
+		}
+	}
+	buildInfo := func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "b1d"}}}, true
+	}
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		git  map[string]string
+		want VersionInfo
+	}{
+		{
+			name: "git",
+			git:  map[string]string{"rev-parse HEAD": "4c2f8a1", "--abbrev-ref HEAD": "main", "--points-at HEAD": "v1.0.0\nv1.0"},
+			want: VersionInfo{Version: "4c2f8a1", Branch: "main", Tags: []string{"v1.0.0", "v1.0"}},
+		},
+		{
+			name: "build info",
+			want: VersionInfo{Version: "b1d"},
+		},
+		{
+			name: "GitHub Actions push",
+			env:  map[string]string{"GITHUB_REF_TYPE": "branch", "GITHUB_REF_NAME": "main", "GITHUB_SERVER_URL": "https://github.com", "GITHUB_REPOSITORY": "org/web", "GITHUB_RUN_ID": "42"},
+			git:  map[string]string{"rev-parse HEAD": "4c2f8a1", "--abbrev-ref HEAD": "HEAD"},
+			want: VersionInfo{Version: "4c2f8a1", Branch: "main", BuildURL: "https://github.com/org/web/actions/runs/42"},
+		},
+		{
+			name: "GitHub Actions pull request",
+			env:  map[string]string{"GITHUB_REF_TYPE": "branch", "GITHUB_REF_NAME": "1/merge", "GITHUB_HEAD_REF": "feature"},
+			git:  map[string]string{"rev-parse HEAD": "4c2f8a1"},
+			want: VersionInfo{Version: "4c2f8a1", Branch: "feature"},
+		},
+		{
+			name: "Jenkins",
+			env:  map[string]string{"GIT_BRANCH": "origin/main", "BUILD_URL": "https://jenkins.example.com/job/web/1/"},
+			git:  map[string]string{"rev-parse HEAD": "4c2f8a1"},
+			want: VersionInfo{Version: "4c2f8a1", Branch: "main", BuildURL: "https://jenkins.example.com/job/web/1/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectVersion(env(tt.env), git(tt.git), buildInfo))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package command
+
+import (
+	"github.com/pact-foundation/pact-go/v2/broker"
+
+	"github.com/spf13/cobra"
+)
+
+// brokerFlags are the flags of the commands that talk to a Pact Broker
+type brokerFlags struct {
+	url      string
+	username string
+	password string
+	token    string
+}
+
+func (f *brokerFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.url, "broker-url", "b", "", "URL of the Pact Broker (default $PACT_BROKER_URL)")
+	cmd.Flags().StringVar(&f.username, "broker-username", "", "Username for basic authentication (default $PACT_BROKER_USERNAME)")
+	cmd.Flags().StringVar(&f.password, "broker-password", "", "Password for basic authentication (default $PACT_BROKER_PASSWORD)")
+	cmd.Flags().StringVar(&f.token, "broker-token", "", "Token for bearer authentication (default $PACT_BROKER_TOKEN)")
+}
+
+func (f *brokerFlags) client() (*broker.Client, error) {
+	return broker.NewClient(broker.Config{
+		URL:      f.url,
+		Username: f.username,
+		Password: f.password,
+		Token:    f.token,
+	})
+}
@@ -0,0 +1,140 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pact-foundation/pact-go/v2/broker"
+
+	"github.com/spf13/cobra"
+)
+
+var canIDeployBroker brokerFlags
+var canIDeployPacticipant string
+var canIDeployVersion string
+var canIDeployEnvironment string
+var canIDeployRetryWhileUnknown bool
+var canIDeployRetryInterval time.Duration
+var canIDeployTimeout time.Duration
+var canIDeployFormat string
+
+var canIDeployCmd = &cobra.Command{
+	Use:   "can-i-deploy",
+	Short: "Check with a Pact Broker whether a version can be deployed",
+	Long: `Ask a Pact Broker whether a version of a pacticipant can be deployed to an
+environment, i.e. whether its pacts have been successfully verified with the
+versions of the other pacticipants deployed there.
+
+The verification results are printed as a table, or as JSON (--format json).
+If the answer is not yet known (e.g. while a provider is verifying the pact),
+--retry-while-unknown asks again until the --timeout.
+
+The command exits with status:
+  0  the version can be deployed
+  1  the version cannot be deployed, or it is unknown whether it can be
+  2  the broker could not be asked, e.g. it could not be reached
+
+The broker URL and credentials default to the PACT_BROKER_URL,
+PACT_BROKER_USERNAME, PACT_BROKER_PASSWORD and PACT_BROKER_TOKEN environment
+variables.`,
+	Example: `  pact-go can-i-deploy --pacticipant web --version 4c2f8a1 --to-environment production --retry-while-unknown`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if canIDeployFormat != "text" && canIDeployFormat != "json" {
+			log.Println("[ERROR] --format must be one of 'text' or 'json'")
+			os.Exit(2)
+		}
+
+		client, err := canIDeployBroker.client()
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(2)
+		}
+
+		result, err := client.CanIDeploy(context.Background(), broker.CanIDeployRequest{
+			Pacticipant:       canIDeployPacticipant,
+			Version:           canIDeployVersion,
+			Environment:       canIDeployEnvironment,
+			RetryWhileUnknown: canIDeployRetryWhileUnknown,
+			RetryInterval:     canIDeployRetryInterval,
+			Timeout:           canIDeployTimeout,
+		})
+		if err != nil {
+			log.Println("[ERROR] unable to ask the broker:", err)
+			os.Exit(2)
+		}
+
+		if err := writeCanIDeployResult(os.Stdout, result, canIDeployFormat); err != nil {
+			log.Println("[ERROR] unable to write the result:", err)
+			os.Exit(2)
+		}
+		if result.Answer() != "yes" {
+			os.Exit(1)
+		}
+	},
+}
+
+func writeCanIDeployResult(w io.Writer, result *broker.CanIDeployResult, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(result)
+	}
+
+	if len(result.Matrix) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CONSUMER\tC.VERSION\tPROVIDER\tP.VERSION\tSUCCESS?\tRESULT")
+		for _, row := range result.Matrix {
+			success := "???"
+			if row.Success != nil {
+				success = fmt.Sprint(*row.Success)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", row.Consumer, row.ConsumerVersion, row.Provider, row.ProviderVersion, success, row.VerificationURL)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "Computer says %s\n", result.Answer()); err != nil {
+		return err
+	}
+	if result.Reason != "" {
+		if _, err := fmt.Fprintf(w, "  %s\n", result.Reason); err != nil {
+			return err
+		}
+	}
+	for _, n := range result.Notices {
+		if _, err := fmt.Fprintf(w, "  %s\n", n.Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	canIDeployBroker.register(canIDeployCmd)
+	canIDeployCmd.Flags().StringVarP(&canIDeployPacticipant, "pacticipant", "p", "", "Name of the pacticipant (consumer or provider) to deploy")
+	canIDeployCmd.Flags().StringVarP(&canIDeployVersion, "version", "e", "", "Version of the pacticipant to deploy")
+	canIDeployCmd.Flags().StringVar(&canIDeployEnvironment, "to-environment", "", "Environment to deploy to")
+	canIDeployCmd.Flags().BoolVar(&canIDeployRetryWhileUnknown, "retry-while-unknown", false, "Ask again while it is unknown whether the version can be deployed")
+	canIDeployCmd.Flags().DurationVar(&canIDeployRetryInterval, "retry-interval", 10*time.Second, "Time between retries")
+	canIDeployCmd.Flags().DurationVar(&canIDeployTimeout, "timeout", 5*time.Minute, "Time to retry for, while the answer is unknown")
+	canIDeployCmd.Flags().StringVarP(&canIDeployFormat, "format", "f", "text", "Output format, one of 'text' or 'json'")
+	_ = canIDeployCmd.MarkFlagRequired("pacticipant")
+	_ = canIDeployCmd.MarkFlagRequired("version")
+	_ = canIDeployCmd.MarkFlagRequired("to-environment")
+	RootCmd.AddCommand(canIDeployCmd)
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/broker"
+)
+
+func TestWriteCanIDeployResult(t *testing.T) {
+	yes := true
+	result := &broker.CanIDeployResult{
+		Reason:  "There is no verified pact between web (4c2f8a1) and users",
+		Unknown: 1,
+		Notices: []broker.Notice{{Type: "warning", Text: "The pact has not been verified"}},
+		Matrix: []broker.MatrixRow{
+			{Consumer: "web", ConsumerVersion: "4c2f8a1", Provider: "orders", ProviderVersion: "1.2.0", Success: &yes, VerificationURL: "https://broker/results/1"},
+			{Consumer: "web", ConsumerVersion: "4c2f8a1", Provider: "users"},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := writeCanIDeployResult(&text, result, "text"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "CONSUMER  C.VERSION  PROVIDER  P.VERSION  SUCCESS?  RESULT\n" +
+		"web       4c2f8a1    orders    1.2.0      true      https://broker/results/1\n" +
+		"web       4c2f8a1    users                ???       \n" +
+		"\n" +
+		"Computer says unknown\n" +
+		"  There is no verified pact between web (4c2f8a1) and users\n" +
+		"  The pact has not been verified\n"
+	if text.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeCanIDeployResult(&out, result, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded broker.CanIDeployResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Deployable != nil || decoded.Unknown != 1 || len(decoded.Matrix) != 2 {
+		t.Fatalf("unexpected JSON result %s", out.String())
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/pact-foundation/pact-go/v2/openapi"
+
+	"github.com/spf13/cobra"
+)
+
+var checkOpenAPIPact string
+var checkOpenAPISpec string
+var checkOpenAPIFormat string
+
+var checkOpenAPICmd = &cobra.Command{
+	Use:   "check-openapi",
+	Short: "Check a pact against an OpenAPI document",
+	Long: `Check every HTTP interaction of a pact file against the OpenAPI 3 document
+of the provider, without running the provider.
+
+Paths, methods, status codes, parameters, headers and body fields that the
+document does not allow are reported, including the types of values allowed by
+matchers. The command exits with a non-zero status if any are found.`,
+	Example: `  pact-go check-openapi --pact pacts/web-users.json --spec openapi.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if checkOpenAPIFormat != "text" && checkOpenAPIFormat != "json" {
+			log.Println("[ERROR] --format must be one of 'text' or 'json'")
+			os.Exit(1)
+		}
+
+		report, err := openapi.CheckPactFile(checkOpenAPIPact, checkOpenAPISpec)
+		if err != nil {
+			log.Println("[ERROR] unable to check the pact:", err)
+			os.Exit(1)
+		}
+
+		if err = writeOpenAPIReport(os.Stdout, report, checkOpenAPIFormat); err != nil {
+			log.Println("[ERROR] unable to write the report:", err)
+			os.Exit(1)
+		}
+
+		if !report.OK() {
+			os.Exit(1)
+		}
+	},
+}
+
+func writeOpenAPIReport(w io.Writer, report *openapi.Report, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(report)
+	}
+
+	for _, v := range report.Violations {
+		if _, err := fmt.Fprintln(w, v); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d interaction(s) checked, %d violation(s) found\n", report.Interactions, len(report.Violations))
+
+	return err
+}
+
+func init() {
+	checkOpenAPICmd.Flags().StringVar(&checkOpenAPIPact, "pact", "", "Path to the pact file")
+	checkOpenAPICmd.Flags().StringVar(&checkOpenAPISpec, "spec", "", "Path to the OpenAPI document, in YAML or JSON format")
+	checkOpenAPICmd.Flags().StringVarP(&checkOpenAPIFormat, "format", "f", "text", "Output format, one of 'text' or 'json'")
+	_ = checkOpenAPICmd.MarkFlagRequired("pact")
+	_ = checkOpenAPICmd.MarkFlagRequired("spec")
+	RootCmd.AddCommand(checkOpenAPICmd)
+}
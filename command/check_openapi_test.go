@@ -0,0 +1,40 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/openapi"
+)
+
+func TestWriteOpenAPIReport(t *testing.T) {
+	report := &openapi.Report{
+		Interactions: 2,
+		Violations: []openapi.Violation{
+			{Interaction: "a request for a user", Location: "response.status", Message: "status 500 is not documented"},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := writeOpenAPIReport(&text, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "a request for a user: response.status: status 500 is not documented\n2 interaction(s) checked, 1 violation(s) found\n"
+	if text.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeOpenAPIReport(&out, report, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded openapi.Report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Interactions != 2 || len(decoded.Violations) != 1 || !strings.Contains(out.String(), `"location": "response.status"`) {
+		t.Fatalf("unexpected JSON report %s", out.String())
+	}
+}
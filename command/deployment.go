@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/pact-foundation/pact-go/v2/broker"
+
+	"github.com/spf13/cobra"
+)
+
+var recordDeploymentBroker brokerFlags
+var recordDeploymentPacticipant string
+var recordDeploymentVersion string
+var recordDeploymentEnvironment string
+var recordDeploymentApplicationInstance string
+
+var recordReleaseBroker brokerFlags
+var recordReleasePacticipant string
+var recordReleaseVersion string
+var recordReleaseEnvironment string
+
+var recordDeploymentCmd = &cobra.Command{
+	Use:   "record-deployment",
+	Short: "Record the deployment of a version in a Pact Broker",
+	Long: `Record in a Pact Broker that a version of a pacticipant was deployed to an
+environment, replacing the version previously deployed there (of the same
+application instance, if any). Run it after deploying, so that can-i-deploy
+checks the pacts of other pacticipants against the deployed version.
+
+The command exits with status 1 if the deployment could not be recorded.`,
+	Example: `  pact-go record-deployment --pacticipant web --version 4c2f8a1 --environment production`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		client, err := recordDeploymentBroker.client()
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+
+		err = client.RecordDeployment(context.Background(), broker.RecordDeploymentRequest{
+			Pacticipant:         recordDeploymentPacticipant,
+			Version:             recordDeploymentVersion,
+			Environment:         recordDeploymentEnvironment,
+			ApplicationInstance: recordDeploymentApplicationInstance,
+		})
+		if err != nil {
+			log.Println("[ERROR] unable to record the deployment:", err)
+			os.Exit(1)
+		}
+		log.Printf("[INFO] recorded the deployment of %s version %s to %s", recordDeploymentPacticipant, recordDeploymentVersion, recordDeploymentEnvironment)
+	},
+}
+
+var recordReleaseCmd = &cobra.Command{
+	Use:   "record-release",
+	Short: "Record the release of a version in a Pact Broker",
+	Long: `Record in a Pact Broker that a version of a pacticipant was released to an
+environment, e.g. a mobile app or library, of which several versions may be in
+use at once.
+
+The command exits with status 1 if the release could not be recorded.`,
+	Example: `  pact-go record-release --pacticipant mobile --version 2.1.0 --environment production`,
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		client, err := recordReleaseBroker.client()
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+
+		err = client.RecordRelease(context.Background(), broker.RecordReleaseRequest{
+			Pacticipant: recordReleasePacticipant,
+			Version:     recordReleaseVersion,
+			Environment: recordReleaseEnvironment,
+		})
+		if err != nil {
+			log.Println("[ERROR] unable to record the release:", err)
+			os.Exit(1)
+		}
+		log.Printf("[INFO] recorded the release of %s version %s to %s", recordReleasePacticipant, recordReleaseVersion, recordReleaseEnvironment)
+	},
+}
+
+func init() {
+	recordDeploymentBroker.register(recordDeploymentCmd)
+	recordDeploymentCmd.Flags().StringVarP(&recordDeploymentPacticipant, "pacticipant", "p", "", "Name of the pacticipant that was deployed")
+	recordDeploymentCmd.Flags().StringVarP(&recordDeploymentVersion, "version", "e", "", "Version of the pacticipant that was deployed")
+	recordDeploymentCmd.Flags().StringVar(&recordDeploymentEnvironment, "environment", "", "Environment the version was deployed to")
+	recordDeploymentCmd.Flags().StringVar(&recordDeploymentApplicationInstance, "application-instance", "", "Application instance the version was deployed to, if several are deployed to the environment")
+	_ = recordDeploymentCmd.MarkFlagRequired("pacticipant")
+	_ = recordDeploymentCmd.MarkFlagRequired("version")
+	_ = recordDeploymentCmd.MarkFlagRequired("environment")
+	RootCmd.AddCommand(recordDeploymentCmd)
+
+	recordReleaseBroker.register(recordReleaseCmd)
+	recordReleaseCmd.Flags().StringVarP(&recordReleasePacticipant, "pacticipant", "p", "", "Name of the pacticipant that was released")
+	recordReleaseCmd.Flags().StringVarP(&recordReleaseVersion, "version", "e", "", "Version of the pacticipant that was released")
+	recordReleaseCmd.Flags().StringVar(&recordReleaseEnvironment, "environment", "", "Environment the version was released to")
+	_ = recordReleaseCmd.MarkFlagRequired("pacticipant")
+	_ = recordReleaseCmd.MarkFlagRequired("version")
+	_ = recordReleaseCmd.MarkFlagRequired("environment")
+	RootCmd.AddCommand(recordReleaseCmd)
+}
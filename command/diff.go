@@ -0,0 +1,75 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/pact-foundation/pact-go/v2/diff"
+
+	"github.com/spf13/cobra"
+)
+
+var diffFormat string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <previous pact> <current pact>",
+	Short: "Report the changes between two versions of a pact",
+	Long: `Compare two versions of a pact file, e.g. before and after a change to the
+consumer, and report the changes to the contract.
+
+Interactions are matched by their description and provider state(s). Added
+and removed interactions are reported, along with the breaking changes to
+matched interactions: fields that requests now require, matchers that now
+allow fewer values, fields that responses no longer have and changed status
+codes. The command exits with a non-zero status if there are breaking changes.`,
+	Example: `  pact-go diff main/pacts/web-users.json pacts/web-users.json --format json`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if diffFormat != "text" && diffFormat != "json" {
+			log.Println("[ERROR] --format must be one of 'text' or 'json'")
+			os.Exit(1)
+		}
+
+		report, err := diff.CompareFiles(args[0], args[1])
+		if err != nil {
+			log.Println("[ERROR] unable to compare the pacts:", err)
+			os.Exit(1)
+		}
+
+		if err = writeDiffReport(os.Stdout, report, diffFormat); err != nil {
+			log.Println("[ERROR] unable to write the report:", err)
+			os.Exit(1)
+		}
+
+		if len(report.Breaking()) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func writeDiffReport(w io.Writer, report *diff.Report, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(report)
+	}
+
+	for _, c := range report.Changes {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d change(s) found, %d breaking\n", len(report.Changes), len(report.Breaking()))
+
+	return err
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "Output format, one of 'text' or 'json'")
+	RootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/diff"
+)
+
+func TestWriteDiffReport(t *testing.T) {
+	report := &diff.Report{
+		Changes: []diff.Change{
+			{Kind: diff.StatusChanged, Breaking: true, Interaction: "a request for a user", Location: "response.status", Message: "the status changed from 200 to 201"},
+			{Kind: diff.InteractionAdded, Interaction: "a request to delete a user", ProviderStates: []string{"a user exists"}, Message: "the interaction was added"},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := writeDiffReport(&text, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "BREAKING a request for a user: response.status: the status changed from 200 to 201\n" +
+		"INFO a request to delete a user (given a user exists): the interaction was added\n" +
+		"2 change(s) found, 1 breaking\n"
+	if text.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeDiffReport(&out, report, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded diff.Report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Changes) != 2 || !strings.Contains(out.String(), `"kind": "status-changed"`) {
+		t.Fatalf("unexpected JSON report %s", out.String())
+	}
+}
@@ -0,0 +1,130 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/lint"
+
+	"github.com/spf13/cobra"
+)
+
+var lintConfigFile string
+var lintFailOn string
+var lintRules []string
+var lintFormat string
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <pact file or directory>...",
+	Short: "Check pacts for contract smells",
+	Long: `Check pact files (or directories of pact files) for contract smells, i.e.
+interactions that are likely to make verification brittle, or the intent of
+the contract unclear:
+
+  exact-body                 response bodies and message contents without matchers
+  duplicate-description      descriptions and provider states used by more than one interaction
+  mutating-without-state     POST, PUT, PATCH and DELETE requests without a provider state
+  timestamp-without-matcher  dates and times without a matcher
+
+The severity of each rule (off, info, warning or error) may be set in a YAML
+configuration file, or with --rule. The command exits with a non-zero status
+if there are findings at or above the --fail-on severity.`,
+	Example: `  pact-go lint ./pacts --fail-on warning --rule exact-body=off`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if lintFormat != "text" && lintFormat != "json" {
+			log.Println("[ERROR] --format must be one of 'text' or 'json'")
+			os.Exit(1)
+		}
+
+		config, err := lintConfig(lintConfigFile, lintFailOn, lintRules)
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+
+		report, err := lint.CheckFiles(args, config)
+		if err != nil {
+			log.Println("[ERROR] unable to check the pacts:", err)
+			os.Exit(1)
+		}
+
+		if err = writeLintReport(os.Stdout, report, lintFormat); err != nil {
+			log.Println("[ERROR] unable to write the report:", err)
+			os.Exit(1)
+		}
+
+		if report.Failed() {
+			os.Exit(1)
+		}
+	},
+}
+
+// lintConfig reads the configuration file, if any, and applies the --fail-on and
+// --rule name=severity flags to it
+func lintConfig(path string, failOn string, rules []string) (*lint.Config, error) {
+	config := &lint.Config{}
+	if path != "" {
+		c, err := lint.LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		config = c
+	}
+
+	if failOn != "" {
+		severity, err := lint.ParseSeverity(failOn)
+		if err != nil {
+			return nil, fmt.Errorf("--fail-on: %w", err)
+		}
+		config.FailOn = severity
+	}
+
+	for _, r := range rules {
+		name, value, ok := strings.Cut(r, "=")
+		if !ok {
+			return nil, fmt.Errorf("--rule must be given as name=severity, got %q", r)
+		}
+		severity, err := lint.ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("--rule %s: %w", name, err)
+		}
+		if config.Rules == nil {
+			config.Rules = make(map[string]lint.Severity)
+		}
+		config.Rules[strings.TrimSpace(name)] = severity
+	}
+
+	return config, nil
+}
+
+func writeLintReport(w io.Writer, report *lint.Report, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(report)
+	}
+
+	for _, f := range report.Findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d finding(s), %d at or above %s\n", len(report.Findings), len(report.Failures()), report.FailOn)
+
+	return err
+}
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintConfigFile, "config", "c", "", "Path to a YAML configuration file of rule severities")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "", "Lowest severity that fails the check, one of 'info', 'warning', 'error' or 'off' (default 'error')")
+	lintCmd.Flags().StringArrayVar(&lintRules, "rule", nil, "Severity of a rule as name=severity, e.g. exact-body=off (may be repeated)")
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "text", "Output format, one of 'text' or 'json'")
+	RootCmd.AddCommand(lintCmd)
+}
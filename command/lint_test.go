@@ -0,0 +1,69 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/lint"
+)
+
+func TestLintConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lint.yaml")
+	if err := os.WriteFile(path, []byte("failOn: info\nrules:\n  exact-body: off\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := lintConfig(path, "warning", []string{"exact-body=error", "duplicate-description=info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.FailOn != lint.Warning || config.Rules["exact-body"] != lint.Error || config.Rules["duplicate-description"] != lint.Info {
+		t.Fatalf("unexpected configuration %+v", config)
+	}
+
+	for _, rules := range [][]string{{"exact-body"}, {"exact-body=fatal"}} {
+		if _, err := lintConfig("", "", rules); err == nil {
+			t.Fatalf("expected an error for --rule %v", rules)
+		}
+	}
+	if _, err := lintConfig("", "fatal", nil); err == nil {
+		t.Fatal("expected an error for --fail-on fatal")
+	}
+}
+
+func TestWriteLintReport(t *testing.T) {
+	report := &lint.Report{
+		FailOn: lint.Error,
+		Findings: []lint.Finding{
+			{Rule: "duplicate-description", Severity: lint.Error, Pact: "pacts/web-users.json", Interaction: "a request", Message: "the description and provider state(s) are used by 2 interactions, so they cannot be told apart"},
+			{Rule: "mutating-without-state", Severity: lint.Warning, Interaction: "a request to delete a user", Location: "request.method", Message: "the DELETE request has no provider state"},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := writeLintReport(&text, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "ERROR duplicate-description: pacts/web-users.json: a request: the description and provider state(s) are used by 2 interactions, so they cannot be told apart\n" +
+		"WARNING mutating-without-state: a request to delete a user: request.method: the DELETE request has no provider state\n" +
+		"2 finding(s), 1 at or above error\n"
+	if text.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeLintReport(&out, report, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded lint.Report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Findings) != 2 || !strings.Contains(out.String(), `"failOn": "error"`) {
+		t.Fatalf("unexpected JSON report %s", out.String())
+	}
+}
@@ -0,0 +1,175 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pact-foundation/pact-go/v2/migrate"
+	"github.com/pact-foundation/pact-go/v2/models"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateTo string
+var migrateOutputDir string
+var migrateStrict bool
+var migrateDryRun bool
+var migrateFormat string
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate <pact file or directory>...",
+	Short: "Upgrade pact files to a later specification version",
+	Long: `Convert V2 and V3 pact files (or directories of pact files), including V3
+message pacts, to the layout of a later specification version.
+
+Provider states, matching rules (including the JSONPath rules of V2 pacts) and
+generators are converted. Anything that cannot be converted losslessly is kept
+as it was, and reported. With --strict, pacts with any such issues are not
+written, and the command exits with a non-zero status.
+
+Pact files are replaced, unless --output-dir is given.`,
+	Example: `  pact-go migrate --to v4 ./pacts --output-dir ./pacts-v4`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if migrateFormat != "text" && migrateFormat != "json" {
+			log.Println("[ERROR] --format must be one of 'text' or 'json'")
+			os.Exit(1)
+		}
+
+		to, err := migrate.ParseVersion(migrateTo)
+		if err != nil {
+			log.Println("[ERROR] --to:", err)
+			os.Exit(1)
+		}
+
+		files, err := pactFiles(args)
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+
+		reports, failed := migrateFiles(files, to, migrateOutputDir, migrateStrict, migrateDryRun)
+
+		if err = writeMigrateReports(os.Stdout, reports, migrateFormat); err != nil {
+			log.Println("[ERROR] unable to write the report:", err)
+			os.Exit(1)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// pactFiles returns the files at the paths, and the pact files (*.json) of the
+// directories at the paths
+func pactFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// migrateFiles migrates each file, writing it to the output directory (or replacing
+// it), and reports whether any of them failed
+func migrateFiles(files []string, to models.SpecificationVersion, outputDir string, strict bool, dryRun bool) ([]*migrate.Report, bool) {
+	reports := make([]*migrate.Report, 0, len(files))
+	failed := false
+
+	for _, file := range files {
+		pact, err := models.Load(file)
+		if err != nil {
+			log.Println("[ERROR]", err)
+			failed = true
+			continue
+		}
+
+		report, err := migrate.Migrate(pact, to)
+		if err != nil {
+			log.Printf("[ERROR] unable to migrate pact file %s: %v", file, err)
+			failed = true
+			continue
+		}
+		report.Pact = file
+		reports = append(reports, report)
+
+		if strict && !report.Lossless() {
+			failed = true
+			continue
+		}
+		if dryRun {
+			continue
+		}
+
+		output := file
+		if outputDir != "" {
+			output = filepath.Join(outputDir, filepath.Base(file))
+		}
+		if err := pact.Save(output); err != nil {
+			log.Printf("[ERROR] unable to write pact file %s: %v", output, err)
+			failed = true
+			continue
+		}
+		report.Output = output
+	}
+
+	return reports, failed
+}
+
+func writeMigrateReports(w io.Writer, reports []*migrate.Report, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(reports)
+	}
+
+	issues := 0
+	for _, r := range reports {
+		status := "not written"
+		if r.Output != "" {
+			status = "written to " + r.Output
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s to %s, %s\n", r.Pact, r.From, r.To, status); err != nil {
+			return err
+		}
+		for _, i := range r.Issues {
+			if _, err := fmt.Fprintf(w, "  LOSSY %s\n", i); err != nil {
+				return err
+			}
+		}
+		issues += len(r.Issues)
+	}
+	_, err := fmt.Fprintf(w, "%d pact(s), %d issue(s)\n", len(reports), issues)
+
+	return err
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "v4", "Specification version to migrate to, one of 'v3' or 'v4'")
+	migrateCmd.Flags().StringVarP(&migrateOutputDir, "output-dir", "o", "", "Directory to write the migrated pact files to, instead of replacing them")
+	migrateCmd.Flags().BoolVar(&migrateStrict, "strict", false, "Do not write pacts that cannot be migrated losslessly, and exit with a non-zero status")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Report the issues of migrating the pacts, without writing them")
+	migrateCmd.Flags().StringVarP(&migrateFormat, "format", "f", "text", "Output format, one of 'text' or 'json'")
+	RootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+func TestMigrateFiles(t *testing.T) {
+	dir := t.TempDir()
+	lossless := `{"interactions": [{"description": "a request", "providerState": "a user", "request": {"method": "GET", "path": "/"}, "response": {"status": 200}}],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}}`
+	lossy := `{"interactions": [{"description": "a request", "request": {"method": "GET", "path": "/"}, "response": {"status": 200, "body": {"name": "billy"},
+		"matchingRules": {"$.body..name": {"match": "type"}}}}], "metadata": {"pactSpecification": {"version": "2.0.0"}}}`
+	for name, pact := range map[string]string{"lossless.json": lossless, "lossy.json": lossy} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(pact), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := pactFiles([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 pact files, got %v", files)
+	}
+
+	reports, failed := migrateFiles(files, models.V4, "", false, true)
+	if failed || len(reports) != 2 || reports[0].Output != "" {
+		t.Fatalf("unexpected dry run reports %+v", reports)
+	}
+
+	output := filepath.Join(dir, "v4")
+	reports, failed = migrateFiles(files, models.V4, output, true, false)
+	if !failed {
+		t.Fatal("expected the lossy migration to fail with --strict")
+	}
+	if _, err := os.Stat(filepath.Join(output, "lossy.json")); !os.IsNotExist(err) {
+		t.Fatal("expected the lossy pact not to be written with --strict")
+	}
+
+	pact, err := models.Load(filepath.Join(output, "lossless.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pact.Version() != models.V4 {
+		t.Fatalf("expected a V4 pact, got %s", pact.Version())
+	}
+
+	var text bytes.Buffer
+	if err := writeMigrateReports(&text, reports, "text"); err != nil {
+		t.Fatal(err)
+	}
+	expected := filepath.Join(dir, "lossless.json") + ": 2.0.0 to 4.0.0, written to " + filepath.Join(output, "lossless.json") + "\n" +
+		filepath.Join(dir, "lossy.json") + ": 2.0.0 to 4.0.0, not written\n" +
+		"  LOSSY a request: response.matchingRules.body.$..name: the path is not supported by V3 or V4 matching rules, and is kept as it was\n" +
+		"2 pact(s), 1 issue(s)\n"
+	if text.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := writeMigrateReports(&out, reports, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"to": "4.0.0"`) {
+		t.Fatalf("unexpected JSON report %s", out.String())
+	}
+}
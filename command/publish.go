@@ -0,0 +1,115 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/broker"
+
+	"github.com/spf13/cobra"
+)
+
+var publishBroker brokerFlags
+var publishConsumerVersion string
+var publishBranch string
+var publishTags []string
+var publishBuildURL string
+var publishFormat string
+
+var publishCmd = &cobra.Command{
+	Use:   "publish <pact directory or file>...",
+	Short: "Publish pacts to a Pact Broker",
+	Long: `Publish pact files (or directories of pact files) to a Pact Broker or
+PactFlow, for a version of the consumer.
+
+The consumer version, branch and tags default to the git commit, branch and
+tags of the working directory (or the branch and build URL given by common CI
+systems). If git is not available, the version is read from the build
+information of the binary.
+
+The broker URL and credentials default to the PACT_BROKER_URL,
+PACT_BROKER_USERNAME, PACT_BROKER_PASSWORD and PACT_BROKER_TOKEN environment
+variables.`,
+	Example: `  pact-go publish ./pacts --broker-url https://broker.example.com --branch main`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if publishFormat != "text" && publishFormat != "json" {
+			log.Println("[ERROR] --format must be one of 'text' or 'json'")
+			os.Exit(1)
+		}
+
+		client, err := publishBroker.client()
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+
+		request := broker.PublishRequest{
+			PactFiles:       args,
+			ConsumerVersion: publishConsumerVersion,
+			Branch:          publishBranch,
+			BuildURL:        publishBuildURL,
+		}
+		if cmd.Flags().Changed("tag") {
+			request.Tags = publishTags
+		}
+
+		results, err := client.Publish(context.Background(), request)
+		if werr := writePublishResults(os.Stdout, results, publishFormat); werr != nil {
+			log.Println("[ERROR] unable to write the results:", werr)
+			os.Exit(1)
+		}
+		if err != nil {
+			log.Println("[ERROR] unable to publish the pacts:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func writePublishResults(w io.Writer, results []*broker.PublishResult, format string) error {
+	if format == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		if results == nil {
+			results = []*broker.PublishResult{}
+		}
+		return e.Encode(results)
+	}
+
+	for _, r := range results {
+		version := r.ConsumerVersion
+		if r.Branch != "" {
+			version += " (branch " + r.Branch + ")"
+		}
+		if len(r.Tags) > 0 {
+			version += " tagged " + strings.Join(r.Tags, ", ")
+		}
+		if _, err := fmt.Fprintf(w, "Published %d pact(s) for %s version %s\n", len(r.Pacts), r.Consumer, version); err != nil {
+			return err
+		}
+		for _, n := range r.Notices {
+			if _, err := fmt.Fprintf(w, "  %s\n", n.Text); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	publishBroker.register(publishCmd)
+	publishCmd.Flags().StringVarP(&publishConsumerVersion, "consumer-app-version", "a", "", "Version of the consumer (default the git commit)")
+	publishCmd.Flags().StringVar(&publishBranch, "branch", "", "Branch of the consumer version (default the git branch)")
+	publishCmd.Flags().StringArrayVarP(&publishTags, "tag", "t", nil, "Tag of the consumer version, may be repeated (default the git tags of the commit)")
+	publishCmd.Flags().StringVar(&publishBuildURL, "build-url", "", "URL of the CI build that published the pacts (default detected from the CI environment)")
+	publishCmd.Flags().StringVarP(&publishFormat, "format", "f", "text", "Output format, one of 'text' or 'json'")
+	RootCmd.AddCommand(publishCmd)
+}
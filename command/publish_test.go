@@ -0,0 +1,40 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/broker"
+)
+
+func TestWritePublishResults(t *testing.T) {
+	results := []*broker.PublishResult{
+		{
+			Consumer:        "web",
+			ConsumerVersion: "4c2f8a1",
+			Branch:          "main",
+			Tags:            []string{"v1.0.0"},
+			Pacts:           []string{"pacts/web-users.json"},
+			Notices:         []broker.Notice{{Type: "success", Text: "Created web version 4c2f8a1"}},
+		},
+	}
+
+	var text bytes.Buffer
+	if err := writePublishResults(&text, results, "text"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Published 1 pact(s) for web version 4c2f8a1 (branch main) tagged v1.0.0\n" +
+		"  Created web version 4c2f8a1\n"
+	if text.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, text.String())
+	}
+
+	var out bytes.Buffer
+	if err := writePublishResults(&out, nil, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Fatalf("unexpected JSON results %s", out.String())
+	}
+}
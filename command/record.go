@@ -0,0 +1,119 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pact-foundation/pact-go/v2/proxy"
+	"github.com/pact-foundation/pact-go/v2/recorder"
+
+	"github.com/spf13/cobra"
+)
+
+var recordTarget string
+var recordPort int
+var recordConsumer string
+var recordProvider string
+var recordFormat string
+var recordOutput string
+var recordPackage string
+var recordOverwrite bool
+
+var recordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record consumer interactions from real traffic",
+	Long: `Start a proxy in front of a provider, and record the requests and responses
+passing through it as consumer interactions. Matchers are inferred for values
+that are likely to vary, such as identifiers, UUIDs and timestamps.
+
+When stopped (e.g. with Ctrl+C), the interactions are written as a V4 pact file,
+or as Go source using the consumer DSL (--format go) to refine and commit.`,
+	Example: `  pact-go record --target http://localhost:8080 --port 9000 --consumer web --provider users`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		if recordFormat != "pact" && recordFormat != "go" {
+			log.Println("[ERROR] --format must be one of 'pact' or 'go'")
+			os.Exit(1)
+		}
+
+		target, err := url.Parse(recordTarget)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			log.Println("[ERROR] --target must be the URL of the provider, e.g. http://localhost:8080")
+			os.Exit(1)
+		}
+
+		rec := recorder.New(recordConsumer, recordProvider)
+		port, err := proxy.HTTPReverseProxy(proxy.Options{
+			TargetScheme:  target.Scheme,
+			TargetAddress: target.Host,
+			TargetPath:    target.Path,
+			ProxyPort:     recordPort,
+			Middleware: []proxy.Middleware{
+				rec.Middleware(),
+			},
+		})
+		if err != nil {
+			log.Println("[ERROR] unable to start the recording proxy:", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Recording requests to http://localhost:%d, forwarding to %s. Press Ctrl+C to stop.\n", port, target)
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+
+		output := recordOutput
+		if output == "" {
+			output = defaultRecordOutput(recordFormat, recordConsumer, recordProvider)
+		}
+
+		if err = writeRecording(rec, recordFormat, output); err != nil {
+			log.Println("[ERROR] unable to write the recorded interactions:", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Wrote %d interaction(s) to %s\n", len(rec.Interactions()), output)
+	},
+}
+
+// defaultRecordOutput is the file the recording is written to, if not given
+func defaultRecordOutput(format string, consumer string, provider string) string {
+	if format == "go" {
+		return fmt.Sprintf("%s_%s_pact_test.go", consumer, provider)
+	}
+
+	return filepath.Join("pacts", fmt.Sprintf("%s-%s.json", consumer, provider))
+}
+
+func writeRecording(rec *recorder.Recorder, format string, output string) error {
+	if format == "pact" {
+		return rec.WritePact(output, recordOverwrite)
+	}
+
+	src, err := rec.GoSource(recordPackage)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
+
+func init() {
+	recordCmd.Flags().StringVarP(&recordTarget, "target", "t", "", "URL of the provider to record, e.g. http://localhost:8080")
+	recordCmd.Flags().IntVarP(&recordPort, "port", "p", 0, "Port of the recording proxy. Defaults to a random port")
+	recordCmd.Flags().StringVar(&recordConsumer, "consumer", "consumer", "Name of the consumer")
+	recordCmd.Flags().StringVar(&recordProvider, "provider", "provider", "Name of the provider")
+	recordCmd.Flags().StringVarP(&recordFormat, "format", "f", "pact", "Output format, one of 'pact' or 'go'")
+	recordCmd.Flags().StringVarP(&recordOutput, "output", "o", "", "File to write. Defaults to pacts/<consumer>-<provider>.json, or <consumer>_<provider>_pact_test.go")
+	recordCmd.Flags().StringVar(&recordPackage, "package", "main", "Package of the generated Go source")
+	recordCmd.Flags().BoolVar(&recordOverwrite, "overwrite", false, "Overwrite the pact file, instead of merging the recorded interactions into it")
+	_ = recordCmd.MarkFlagRequired("target")
+	RootCmd.AddCommand(recordCmd)
+}
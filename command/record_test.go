@@ -0,0 +1,46 @@
+package command
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/recorder"
+)
+
+func TestDefaultRecordOutput(t *testing.T) {
+	if out := defaultRecordOutput("pact", "web", "users"); out != filepath.Join("pacts", "web-users.json") {
+		t.Fatalf("unexpected pact output %s", out)
+	}
+
+	if out := defaultRecordOutput("go", "web", "users"); out != "web_users_pact_test.go" {
+		t.Fatalf("unexpected go output %s", out)
+	}
+}
+
+func TestWriteRecording(t *testing.T) {
+	rec := recorder.New("web", "users")
+	u, _ := url.Parse("/users/1")
+	rec.Record("GET", u, http.Header{}, nil, 200, http.Header{"Content-Type": {"application/json"}}, []byte(`{"id": 1}`))
+
+	dir := t.TempDir()
+	for format, file := range map[string]string{
+		"pact": filepath.Join(dir, "web-users.json"),
+		"go":   filepath.Join(dir, "web_users_pact_test.go"),
+	} {
+		if err := writeRecording(rec, format, file); err != nil {
+			t.Fatalf("unable to write %s: %v", format, err)
+		}
+
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(contents), "a GET request to /users/1") {
+			t.Fatalf("expected %s to contain the recorded interaction, got %s", file, contents)
+		}
+	}
+}
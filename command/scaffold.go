@@ -0,0 +1,133 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/pact-foundation/pact-go/v2/internal/codegen"
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/pact-foundation/pact-go/v2/openapi"
+
+	"github.com/spf13/cobra"
+)
+
+var scaffoldSpec string
+var scaffoldOperations []string
+var scaffoldStatus int
+var scaffoldConsumer string
+var scaffoldProvider string
+var scaffoldPackage string
+var scaffoldOutput string
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Generate consumer tests",
+	Long:  "Generate consumer tests from a description of the provider",
+}
+
+var scaffoldOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Generate consumer tests from an OpenAPI document",
+	Long: `Generate a Go consumer test for operations of an OpenAPI 3 document.
+
+The requests and responses use matchers derived from the types, formats and
+patterns of the documented schemas, so that tests start from the documented
+shape of the provider. Only required parameters, headers and properties are
+included, as optional fields may not be returned by the provider.`,
+	Example: `  pact-go scaffold openapi --spec openapi.yaml --operation getUser --consumer web --provider users`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		doc, err := openapi.Load(scaffoldSpec)
+		if err != nil {
+			log.Println("[ERROR]", err)
+			os.Exit(1)
+		}
+
+		src, err := scaffoldSource(doc, scaffoldOperations, scaffoldStatus)
+		if err != nil {
+			log.Println("[ERROR] unable to generate the consumer test:", err)
+			os.Exit(1)
+		}
+
+		if scaffoldOutput == "" {
+			fmt.Print(string(src))
+			return
+		}
+
+		if err = os.WriteFile(scaffoldOutput, src, 0644); err != nil {
+			log.Println("[ERROR] unable to write the consumer test:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// scaffoldSource returns a Go test file with an interaction for each operation, or for
+// every operation of the document if none are given
+func scaffoldSource(doc *openapi.Document, operationIDs []string, status int) ([]byte, error) {
+	if len(operationIDs) == 0 {
+		var err error
+		if operationIDs, err = doc.OperationIDs(); err != nil {
+			return nil, err
+		}
+	}
+
+	file := codegen.TestFile{
+		Generator: "pact-go scaffold openapi",
+		Package:   scaffoldPackage,
+		Consumer:  scaffoldConsumer,
+		Provider:  scaffoldProvider,
+	}
+
+	for _, id := range operationIDs {
+		i, err := doc.Interaction(id, status)
+		if err != nil {
+			return nil, err
+		}
+
+		file.Interactions = append(file.Interactions, codegen.Interaction{
+			Description:     i.Description,
+			Method:          i.Method,
+			Path:            i.Path,
+			PathMatcher:     i.PathMatcher,
+			Query:           i.Query,
+			RequestHeaders:  i.RequestHeaders,
+			RequestBody:     scaffoldBody(i.RequestHeaders, i.RequestBody),
+			Status:          i.Status,
+			ResponseHeaders: i.ResponseHeaders,
+			ResponseBody:    scaffoldBody(i.ResponseHeaders, i.ResponseBody),
+		})
+	}
+
+	return file.Source()
+}
+
+func scaffoldBody(headers matchers.HeadersMatcher, body interface{}) *codegen.Body {
+	if body == nil {
+		return nil
+	}
+
+	contentType := "application/json"
+	if values := headers["Content-Type"]; len(values) > 0 {
+		contentType = fmt.Sprint(values[0].GetValue())
+	}
+
+	return &codegen.Body{
+		ContentType: contentType,
+		JSON:        body,
+	}
+}
+
+func init() {
+	scaffoldOpenAPICmd.Flags().StringVar(&scaffoldSpec, "spec", "", "Path to the OpenAPI document, in YAML or JSON format")
+	scaffoldOpenAPICmd.Flags().StringArrayVar(&scaffoldOperations, "operation", nil, "ID of an operation to generate an interaction for. May be repeated. Defaults to all operations")
+	scaffoldOpenAPICmd.Flags().IntVar(&scaffoldStatus, "status", 0, "Status code of the documented response to use. Defaults to the first successful response")
+	scaffoldOpenAPICmd.Flags().StringVar(&scaffoldConsumer, "consumer", "consumer", "Name of the consumer")
+	scaffoldOpenAPICmd.Flags().StringVar(&scaffoldProvider, "provider", "provider", "Name of the provider")
+	scaffoldOpenAPICmd.Flags().StringVar(&scaffoldPackage, "package", "main", "Package of the generated Go source")
+	scaffoldOpenAPICmd.Flags().StringVarP(&scaffoldOutput, "output", "o", "", "File to write. Defaults to standard output")
+	_ = scaffoldOpenAPICmd.MarkFlagRequired("spec")
+	scaffoldCmd.AddCommand(scaffoldOpenAPICmd)
+	RootCmd.AddCommand(scaffoldCmd)
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/openapi"
+)
+
+func TestScaffoldSource(t *testing.T) {
+	doc, err := openapi.Load("../openapi/testdata/users.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := scaffoldSource(doc, []string{"getUser", "createUser"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"// Code generated by pact-go scaffold openapi.",
+		"WithRequestPathMatcher(\"GET\", matchers.Regex(\"/users/10\", `^/users/\\d+$`)",
+		`UponReceiving("a request to create a user")`,
+		`"id":        matchers.Integer(10),`,
+		`WillRespondWith(201,`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Fatalf("expected the source to contain %s, got %s", expected, src)
+		}
+	}
+
+	src, err = scaffoldSource(doc, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(src), "t.Run(") != 5 {
+		t.Fatalf("expected an interaction for every operation, got %s", src)
+	}
+
+	if _, err = scaffoldSource(doc, []string{"unknown"}, 0); err == nil {
+		t.Fatal("expected an error for an unknown operation")
+	}
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/stub"
+
+	"github.com/spf13/cobra"
+)
+
+var stubDirs []string
+var stubPort int
+var stubStateHeader string
+var stubStateParameter string
+var stubCORS bool
+
+var stubCmd = &cobra.Command{
+	Use:   "stub [pact directory or file]...",
+	Short: "Serve the responses of the interactions of pact files",
+	Long: `Start a stub server of the provider, answering requests with the responses of the
+HTTP interactions of pact files, e.g. to develop or test a consumer without the
+real provider. Requests are matched following the matching rules of the
+interactions, and the generators of the responses are applied.
+
+The provider state of the interaction to respond with may be given with the
+X-Pact-Provider-State header, or the pact-provider-state query parameter.
+Without one, interactions without provider states are preferred.
+
+Requests that match no interaction are answered with a 404 response, and logged
+with the closest interaction and why it did not match.`,
+	Example: `  pact-go stub --dir ./pacts --port 8080
+  curl -H 'X-Pact-Provider-State: a user exists' http://localhost:8080/users/1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setLogLevel(verbose, logLevel)
+
+		paths := stubPaths(stubDirs, args)
+		server, err := stub.Load(paths, stub.Options{
+			StateHeader:    stubStateHeader,
+			StateParameter: stubStateParameter,
+			CORS:           stubCORS,
+		})
+		if err != nil {
+			log.Println("[ERROR] unable to load the pact files:", err)
+			os.Exit(1)
+		}
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", stubPort))
+		if err != nil {
+			log.Println("[ERROR] unable to start the stub server:", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Serving %d interaction(s) from %s on http://localhost:%d. Press Ctrl+C to stop.\n",
+			len(server.Interactions()), strings.Join(paths, ", "), listener.Addr().(*net.TCPAddr).Port)
+
+		if err = http.Serve(listener, server); err != nil {
+			log.Println("[ERROR] stub server stopped:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// stubPaths returns the pact files and directories to serve, which default to the
+// pacts directory
+func stubPaths(dirs []string, args []string) []string {
+	paths := append(append([]string{}, dirs...), args...)
+	if len(paths) == 0 {
+		return []string{"pacts"}
+	}
+
+	return paths
+}
+
+func init() {
+	stubCmd.Flags().StringArrayVarP(&stubDirs, "dir", "d", nil, "Directory (or file) of the pact files to serve (may be repeated). Defaults to ./pacts")
+	stubCmd.Flags().IntVarP(&stubPort, "port", "p", 0, "Port of the stub server. Defaults to a random port")
+	stubCmd.Flags().StringVar(&stubStateHeader, "state-header", stub.DefaultStateHeader, "Request header naming the provider state of the interaction to respond with")
+	stubCmd.Flags().StringVar(&stubStateParameter, "state-param", stub.DefaultStateParameter, "Query parameter naming the provider state of the interaction to respond with")
+	stubCmd.Flags().BoolVar(&stubCORS, "cors", false, "Answer CORS preflight requests and allow any origin, for use by browsers")
+	RootCmd.AddCommand(stubCmd)
+}
@@ -0,0 +1,16 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStubPaths(t *testing.T) {
+	if paths := stubPaths(nil, nil); !reflect.DeepEqual(paths, []string{"pacts"}) {
+		t.Fatalf("expected the pacts directory by default, got %v", paths)
+	}
+
+	if paths := stubPaths([]string{"a"}, []string{"b.json"}); !reflect.DeepEqual(paths, []string{"a", "b.json"}) {
+		t.Fatalf("expected the directories and files, got %v", paths)
+	}
+}
@@ -0,0 +1,31 @@
+package consumer
+
+import (
+	"github.com/pact-foundation/pact-go/v2/internal/dsl"
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+	"github.com/pact-foundation/pact-go/v2/internal/runner"
+	"github.com/pact-foundation/pact-go/v2/lint"
+)
+
+// PactConflictError is returned by ExecuteTest when an interaction with the same
+// description and provider state(s), but different content, has already been
+// written to the pact file by the same test run - by another test, or another
+// package's test binary. Interactions written by an earlier run are replaced.
+//
+// Interactions from concurrently running tests and processes are otherwise merged
+// into the shared pact file. Use errors.As to inspect the conflicting interaction.
+type PactConflictError = pactfile.ConflictError
+
+// TimeoutError is returned by ExecuteTestContext when the context expires, or is
+// cancelled, before the integration test completes. It wraps the context error, so
+// errors.Is(err, context.DeadlineExceeded) may be used to detect a timeout.
+type TimeoutError = runner.TimeoutError
+
+// InteractionError is returned by ExecuteTest when an interaction was configured
+// incorrectly, for example with a matcher not supported by the specification version
+// of the pact. It identifies the interaction, and the path of the invalid field.
+type InteractionError = dsl.InteractionError
+
+// LintError is returned by ExecuteTest when MockHTTPProviderConfig.Lint is set, and
+// the pact has contract smells at or above its FailOn severity. Failures lists them.
+type LintError = lint.FailedError
@@ -9,21 +9,26 @@ package consumer
 // 3. Need to ensure only v2 or v3 matchers are added
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
-	"unicode"
-	"unicode/utf8"
 
-	"github.com/pact-foundation/pact-go/v2/command"
 	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+	"github.com/pact-foundation/pact-go/v2/internal/runner"
+	"github.com/pact-foundation/pact-go/v2/internal/version"
+	"github.com/pact-foundation/pact-go/v2/lint"
 	logging "github.com/pact-foundation/pact-go/v2/log"
 	"github.com/pact-foundation/pact-go/v2/models"
 	"github.com/pact-foundation/pact-go/v2/utils"
@@ -70,17 +75,31 @@ type MockHTTPProviderConfig struct {
 	// Defaults to 10s
 	ClientTimeout time.Duration
 
-	// TLS enables a mock service behind a self-signed certificate
-	// TODO: document and test this
+	// TLS enables a mock service behind the self-signed certificate of the mock
+	// server. MockServerConfig.HTTPClient trusts this certificate.
 	TLS bool
+
+	// Lint checks the pact of each test for contract smells before it is written (see
+	// the lint package). Findings are logged, and if the check fails ExecuteTest
+	// returns a *LintError and the pact is not written. Defaults to no check.
+	Lint *lint.Config
+
+	// OverwriteInteractions replaces interactions written to the pact file with
+	// different content by other tests of the same run, instead of failing with a
+	// *PactConflictError. Interactions written by an earlier run are always replaced.
+	OverwriteInteractions bool
 }
 
 // httpMockProvider is the entrypoint for http consumer tests
-// This object is not thread safe
+// This object is not thread safe, use forTest to obtain an isolated
+// copy for each parallel test
 type httpMockProvider struct {
 	specificationVersion models.SpecificationVersion
 	config               MockHTTPProviderConfig
 	mockserver           *native.MockServer
+
+	// interactions added since the last test, checked for configuration errors
+	interactions []*Interaction
 }
 
 // MockServerConfig stores the address configuration details of the server for the current executing test
@@ -89,6 +108,42 @@ type MockServerConfig struct {
 	Port      int
 	Host      string
 	TLSConfig *tls.Config
+
+	// TLS is true if the mock server serves HTTPS
+	TLS bool
+}
+
+// BaseURL returns the URL of the mock server, e.g. http://127.0.0.1:1234 or https://[::1]:1234
+func (c MockServerConfig) BaseURL() string {
+	scheme := "http"
+	if c.TLS {
+		scheme = "https"
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(strings.Trim(c.Host, "[]"), strconv.Itoa(c.Port)),
+	}
+
+	return u.String()
+}
+
+// HTTPClient returns a client for the mock server, which trusts the certificate of
+// the mock server when TLS is enabled
+func (c MockServerConfig) HTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.TLS {
+		tlsConfig := c.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = GetTLSConfigForTLSMockServer()
+		}
+		transport.TLSClientConfig = tlsConfig.Clone()
+	}
+
+	return &http.Client{
+		Transport: transport,
+	}
 }
 
 // configure validates the configuration for the consumer test
@@ -113,7 +168,10 @@ func (p *httpMockProvider) configure() error {
 	}
 
 	p.mockserver = native.NewHTTPPact(p.config.Consumer, p.config.Provider)
-	p.mockserver.WithMetadata("pact-go", "version", strings.TrimPrefix(command.Version, "v"))
+	p.mockserver.WithMetadata("pact-go", "version", strings.TrimPrefix(version.Version, "v"))
+	if p.config.Lint != nil {
+		p.mockserver.WithPactCheck(lintPact(p.config.Lint))
+	}
 	switch p.specificationVersion {
 	case models.V2:
 		p.mockserver.WithSpecificationVersion(native.SPECIFICATION_VERSION_V2)
@@ -127,12 +185,58 @@ func (p *httpMockProvider) configure() error {
 	return nil
 }
 
+// newInteraction adds an interaction to the pact
+func (p *httpMockProvider) newInteraction(version models.SpecificationVersion) *Interaction {
+	i := &Interaction{
+		specificationVersion: version,
+		interaction:          p.mockserver.NewInteraction(""),
+	}
+	p.interactions = append(p.interactions, i)
+
+	return i
+}
+
+// interactionErrors returns the configuration errors of the interactions added
+// since the last test, if any
+func (p *httpMockProvider) interactionErrors() error {
+	errs := make([]error, 0)
+	for _, i := range p.interactions {
+		if err := i.configErrors.Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // ExecuteTest runs the current test case against a Mock Service.
 // Will cleanup interactions between tests within a suite
 // and write the pact file if successful
 func (p *httpMockProvider) ExecuteTest(t *testing.T, integrationTest func(MockServerConfig) error) error {
+	t.Helper()
+
+	return p.ExecuteTestContext(context.Background(), t, func(_ context.Context, config MockServerConfig) error {
+		return integrationTest(config)
+	})
+}
+
+// ExecuteTestContext runs the current test case against a Mock Service, as per ExecuteTest.
+// If ctx expires before the integration test completes, the mock server is shut down
+// and a *TimeoutError is returned. The integration test should pass ctx to its
+// client, so that it stops promptly.
+//
+// Unless ctx can never expire, the integration test runs in its own goroutine, so it
+// must not call t.FailNow (or t.Fatal, require.*, etc.) and should return an error
+// instead. If it does, ExecuteTestContext returns an error and writes no pact.
+func (p *httpMockProvider) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(context.Context, MockServerConfig) error) error {
+	t.Helper()
 	log.Println("[DEBUG] pact verify")
 
+	if err := p.interactionErrors(); err != nil {
+		p.reset()
+		return err
+	}
+
 	var err error
 	if p.config.AllowedMockServerPorts != "" && p.config.Port <= 0 {
 		p.config.Port, err = utils.FindPortInRange(p.config.AllowedMockServerPorts)
@@ -150,26 +254,43 @@ func (p *httpMockProvider) ExecuteTest(t *testing.T, integrationTest func(MockSe
 		return err
 	}
 
-	// Run the integration test
-	err = integrationTest(MockServerConfig{
+	config := MockServerConfig{
 		Port:      p.config.Port,
 		Host:      p.config.Host,
 		TLSConfig: GetTLSConfigForTLSMockServer(),
+		TLS:       p.config.TLS,
+	}
+
+	// Run the integration test
+	err = runner.Run(ctx, t.Name(), func(ctx context.Context) error {
+		return integrationTest(ctx, config)
 	})
 
+	// The test was abandoned, so any mismatches are incidental
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return err
+	}
+
 	res, mismatches := p.mockserver.Verify(p.config.Port, p.config.PactDir)
-	p.displayMismatches(t, mismatches)
 
-	if err != nil {
-		return err
+	var mismatchErr *MismatchError
+	if !res || len(mismatches) > 0 {
+		log.Println("[INFO] pact validation failed, mismatches:", len(mismatches))
+		mismatchErr = &MismatchError{
+			TestName:     t.Name(),
+			Mismatches:   mismatches,
+			interactions: p.mockserver.Interactions(),
+		}
+		t.Logf("Pact verification failed for %s:\n\n%s", t.Name(), mismatchErr.Diff())
 	}
 
-	if !res {
-		return fmt.Errorf("pact validation failed: %+v %+v", res, mismatches)
+	if err != nil {
+		return err
 	}
 
-	if len(mismatches) > 0 {
-		return fmt.Errorf("pact validation failed: %+v", mismatches)
+	if mismatchErr != nil {
+		return mismatchErr
 	}
 
 	p.mockserver.CleanupPlugins()
@@ -177,140 +298,54 @@ func (p *httpMockProvider) ExecuteTest(t *testing.T, integrationTest func(MockSe
 	return p.writePact()
 }
 
-// Clear state between tests
-func (p *httpMockProvider) reset() {
-	p.mockserver.CleanupMockServer(p.config.Port)
-	p.mockserver.CleanupPlugins()
-	p.config.Port = 0
-	err := p.configure()
-	if err != nil {
-		log.Println("[ERROR] failed to configure the mock server")
-	}
-}
-
-// TODO: improve / pretty print this to make it really easy to understand the problems
-// See existing Pact/Ruby code examples
-func (p *httpMockProvider) displayMismatches(t *testing.T, mismatches []native.MismatchedRequest) {
-	if len(mismatches) > 0 {
+// forTest creates a copy of the provider with its own native pact, so that the
+// interactions and mock server of one test are isolated from any other test
+// running concurrently. The native pact is released when the test completes.
+func (p *httpMockProvider) forTest(t *testing.T) *httpMockProvider {
+	t.Helper()
 
-		if len(callerInfo()) > 0 {
-			fmt.Printf("\n\n%s:\n", callerInfo()[len(callerInfo())-1])
-		}
-		fmt.Println("\tPact Verification Failed for:", t.Name())
-		fmt.Println()
-		fmt.Println("\t\tDiff:")
-		log.Println("[INFO] pact validation failed, errors: ")
-		for _, m := range mismatches {
-			formattedRequest := fmt.Sprintf("%s %s", m.Method, m.Path)
-			switch m.Type {
-			case "missing-request":
-				fmt.Printf("\t\texpected: \t%s (Expected request that was not received)\n", formattedRequest)
-			case "request-not-found":
-				fmt.Printf("\t\tactual: \t%s (Unexpected request was received)\n", formattedRequest)
-			default:
-				// TODO:
-			}
-
-			for _, detail := range m.Mismatches {
-				switch detail.Type {
-				case "HeaderMismatch":
-					fmt.Printf("\t\t\t%s\n:", detail.Mismatch)
-					fmt.Println("\t\t\texpected: \t", detail.Expected)
-					fmt.Println("\t\t\tactual: \t", detail.Actual)
-				case "BodyMismatch":
-					fmt.Printf("\t\t\t%s\n", detail.Mismatch)
-					fmt.Println("\t\t\texpected:\t", detail.Expected)
-					fmt.Println("\t\t\tactual:\t\t", detail.Actual)
-				}
-			}
-		}
-		fmt.Println()
-		fmt.Println()
+	isolated := &httpMockProvider{
+		specificationVersion: p.specificationVersion,
+		config:               p.config,
 	}
-}
 
-// Stolen from "github.com/stretchr/testify/assert"
-func callerInfo() []string {
-
-	var pc uintptr
-	var ok bool
-	var file string
-	var line int
-	var name string
-
-	callers := []string{}
-	for i := 0; ; i++ {
-		pc, file, line, ok = runtime.Caller(i)
-		if !ok {
-			// The breaks below failed to terminate the loop, and we ran off the
-			// end of the call stack.
-			break
-		}
-
-		// This is a huge edge case, but it will panic if this is the case, see #180
-		if file == "<autogenerated>" {
-			break
-		}
-
-		f := runtime.FuncForPC(pc)
-		if f == nil {
-			break
-		}
-		name = f.Name()
-
-		// testing.tRunner is the standard library function that calls
-		// tests. Subtests are called directly by tRunner, without going through
-		// the Test/Benchmark/Example function that contains the t.Run calls, so
-		// with subtests we should break when we hit tRunner, without adding it
-		// to the list of callers.
-		if name == "testing.tRunner" {
-			break
-		}
-
-		parts := strings.Split(file, "/")
-		file = parts[len(parts)-1]
-		if len(parts) > 1 {
-			dir := parts[len(parts)-2]
-			if (dir != "assert" && dir != "mock" && dir != "require") || file == "mock_test.go" {
-				callers = append(callers, fmt.Sprintf("%s:%d", file, line))
-			}
-		}
+	// A fixed port can't be shared between concurrent tests
+	isolated.config.Port = 0
 
-		// Drop the package
-		segments := strings.Split(name, ".")
-		name = segments[len(segments)-1]
-		if isTest(name, "Test") ||
-			isTest(name, "Benchmark") ||
-			isTest(name, "Example") {
-			break
-		}
+	if err := isolated.configure(); err != nil {
+		t.Fatalf("unable to configure pact for test %s: %v", t.Name(), err)
 	}
+	t.Cleanup(func() {
+		isolated.mockserver.FreePact()
+	})
 
-	return callers
+	return isolated
 }
 
-// Stolen from the `go test` tool.
-// isTest tells whether name looks like a test (or benchmark, according to prefix).
-// It is a Test (say) if there is a character after Test that is not a lower-case letter.
-// We don't want TesticularCancer.
-func isTest(name, prefix string) bool {
-	if !strings.HasPrefix(name, prefix) {
-		return false
-	}
-	if len(name) == len(prefix) { // "Test" is ok
-		return true
+// Clear state between tests
+func (p *httpMockProvider) reset() {
+	p.mockserver.CleanupMockServer(p.config.Port)
+	p.mockserver.CleanupPlugins()
+	p.mockserver.FreePact()
+	p.interactions = nil
+	p.config.Port = 0
+	err := p.configure()
+	if err != nil {
+		log.Println("[ERROR] failed to configure the mock server")
 	}
-	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
-	return !unicode.IsLower(r)
 }
 
 // writePact may be called after each interaction with a mock server is completed
-// the shared core is threadsafe and will merge, as long as the requests come from a single process
-// (that is, there isn't separate) instances of the FFI running simultaneously
+// the interactions are merged with the existing pact file under an exclusive file lock,
+// so concurrently running tests (and test binaries) may share a pact file.
 func (p *httpMockProvider) writePact() error {
 	log.Println("[DEBUG] write pact file")
 	if p.config.Port != 0 {
-		return p.mockserver.WritePactFile(p.config.Port, p.config.PactDir)
+		mode := pactfile.MergeMode
+		if p.config.OverwriteInteractions {
+			mode = pactfile.UpdateMode
+		}
+		return p.mockserver.WritePactFile(p.config.Port, p.config.PactDir, mode)
 	}
 	return errors.New("pact server not yet started")
 }
@@ -0,0 +1,69 @@
+package consumer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockServerConfigBaseURL(t *testing.T) {
+	testCases := []struct {
+		description string
+		config      MockServerConfig
+		want        string
+	}{
+		{
+			description: "IPv4 address",
+			config:      MockServerConfig{Host: "127.0.0.1", Port: 1234},
+			want:        "http://127.0.0.1:1234",
+		},
+		{
+			description: "hostname with TLS",
+			config:      MockServerConfig{Host: "localhost", Port: 1234, TLS: true},
+			want:        "https://localhost:1234",
+		},
+		{
+			description: "IPv6 address",
+			config:      MockServerConfig{Host: "::1", Port: 1234},
+			want:        "http://[::1]:1234",
+		},
+		{
+			description: "bracketed IPv6 address",
+			config:      MockServerConfig{Host: "[::1]", Port: 1234},
+			want:        "http://[::1]:1234",
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.want, test.config.BaseURL(), test.description)
+	}
+}
+
+func TestMockServerConfigHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	config := MockServerConfig{
+		Host:      u.Hostname(),
+		Port:      port,
+		TLS:       true,
+		TLSConfig: &tls.Config{RootCAs: pool},
+	}
+
+	res, err := config.HTTPClient().Get(config.BaseURL())
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
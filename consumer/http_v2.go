@@ -1,6 +1,7 @@
 package consumer
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -10,7 +11,7 @@ import (
 )
 
 // V2HTTPMockProvider is the entrypoint for V2 http consumer tests
-// This object is not thread safe
+// This object is not thread safe, use ForTest to run tests in parallel
 type V2HTTPMockProvider struct {
 	*httpMockProvider
 }
@@ -32,17 +33,24 @@ func NewV2Pact(config MockHTTPProviderConfig) (*V2HTTPMockProvider, error) {
 	return provider, err
 }
 
+// ForTest returns a copy of the provider that is isolated to the given test, allowing
+// consumer tests to run concurrently with t.Parallel(). Each copy registers its
+// interactions against its own mock server, and merges them into the shared pact
+// file when ExecuteTest succeeds.
+func (p *V2HTTPMockProvider) ForTest(t *testing.T) *V2HTTPMockProvider {
+	t.Helper()
+
+	return &V2HTTPMockProvider{
+		httpMockProvider: p.forTest(t),
+	}
+}
+
 // AddInteraction to the pact
 func (p *V2HTTPMockProvider) AddInteraction() *V2UnconfiguredInteraction {
 	log.Println("[DEBUG] pact add V2 interaction")
-	interaction := p.mockserver.NewInteraction("")
-
 	i := &V2UnconfiguredInteraction{
-		interaction: &Interaction{
-			specificationVersion: models.V2,
-			interaction:          interaction,
-		},
-		provider: p,
+		interaction: p.newInteraction(models.V2),
+		provider:    p,
 	}
 
 	return i
@@ -75,7 +83,7 @@ type V2RequestBuilder struct {
 // UponReceiving specifies the name of the test case. This becomes the name of
 // the consumer/provider pair in the Pact file. Mandatory.
 func (i *V2UnconfiguredInteraction) UponReceiving(description string) *V2UnconfiguredInteraction {
-	i.interaction.interaction.UponReceiving(description)
+	i.interaction.uponReceiving(description)
 
 	return i
 }
@@ -150,9 +158,8 @@ func (i *V2RequestBuilder) Headers(headers matchers.HeadersMatcher) *V2RequestBu
 
 // JSONBody adds a JSON body to the expected request
 func (i *V2RequestBuilder) JSONBody(body interface{}) *V2RequestBuilder {
-	// TODO: Don't like panic, but not sure if there is a better builder experience?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	if !i.interaction.validBody("request.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -203,7 +210,9 @@ func (i *V2RequestBuilder) Body(contentType string, body []byte) *V2RequestBuild
 
 // BodyMatch uses struct tags to automatically determine matchers from the given struct
 func (i *V2RequestBuilder) BodyMatch(body interface{}) *V2RequestBuilder {
-	i.interaction.interaction.WithJSONRequestBody(matchers.MatchV2(body))
+	if m, ok := i.interaction.matchBody("request.body", body); ok {
+		i.interaction.interaction.WithJSONRequestBody(m)
+	}
 
 	return i
 }
@@ -254,9 +263,8 @@ func (i *V2ResponseBuilder) Headers(headers matchers.HeadersMatcher) *V2Response
 
 // JSONBody adds a JSON body to the expected response
 func (i *V2ResponseBuilder) JSONBody(body interface{}) *V2ResponseBuilder {
-	// TODO: Don't like panic, how to build a better builder here - nil return + log?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	if !i.interaction.validBody("response.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -297,12 +305,21 @@ func (i *V2ResponseBuilder) Body(contentType string, body []byte) *V2ResponseBui
 
 // BodyMatch uses struct tags to automatically determine matchers from the given struct
 func (i *V2ResponseBuilder) BodyMatch(body interface{}) *V2ResponseBuilder {
-	i.interaction.interaction.WithJSONResponseBody(matchers.MatchV2(body))
+	if m, ok := i.interaction.matchBody("response.body", body); ok {
+		i.interaction.interaction.WithJSONResponseBody(m)
+	}
 
 	return i
 }
 
 // ExecuteTest runs the current test case against a Mock Service.
 func (m *V2InteractionWithResponse) ExecuteTest(t *testing.T, integrationTest func(MockServerConfig) error) error {
+	t.Helper()
 	return m.provider.ExecuteTest(t, integrationTest)
 }
+
+// ExecuteTestContext runs the current test case against a Mock Service, bounded by ctx.
+func (m *V2InteractionWithResponse) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(context.Context, MockServerConfig) error) error {
+	t.Helper()
+	return m.provider.ExecuteTestContext(ctx, t, integrationTest)
+}
@@ -0,0 +1,38 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpV2InvalidMatcher(t *testing.T) {
+	p, err := NewV2Pact(MockHTTPProviderConfig{
+		Consumer: "consumer",
+		Provider: "provider",
+		PactDir:  t.TempDir(),
+	})
+	assert.NoError(t, err)
+
+	called := false
+	err = p.AddInteraction().
+		UponReceiving("a request with a V3 matcher").
+		WithRequest("GET", "/").
+		WillRespondWith(200, func(b *V2ResponseBuilder) {
+			b.JSONBody(Map{
+				"name": matchers.Includes("billy"),
+			})
+		}).
+		ExecuteTest(t, func(msc MockServerConfig) error {
+			called = true
+			return nil
+		})
+
+	var interactionErr *InteractionError
+	assert.True(t, errors.As(err, &interactionErr))
+	assert.Equal(t, "a request with a V3 matcher", interactionErr.Description)
+	assert.Equal(t, "response.body", interactionErr.Path)
+	assert.False(t, called)
+}
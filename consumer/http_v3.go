@@ -1,6 +1,7 @@
 package consumer
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -10,7 +11,7 @@ import (
 )
 
 // V3HTTPMockProvider is the entrypoint for V3 http consumer tests
-// This object is not thread safe
+// This object is not thread safe, use ForTest to run tests in parallel
 type V3HTTPMockProvider struct {
 	*httpMockProvider
 }
@@ -32,17 +33,24 @@ func NewV3Pact(config MockHTTPProviderConfig) (*V3HTTPMockProvider, error) {
 	return provider, err
 }
 
+// ForTest returns a copy of the provider that is isolated to the given test, allowing
+// consumer tests to run concurrently with t.Parallel(). Each copy registers its
+// interactions against its own mock server, and merges them into the shared pact
+// file when ExecuteTest succeeds.
+func (p *V3HTTPMockProvider) ForTest(t *testing.T) *V3HTTPMockProvider {
+	t.Helper()
+
+	return &V3HTTPMockProvider{
+		httpMockProvider: p.forTest(t),
+	}
+}
+
 // AddInteraction to the pact
 func (p *V3HTTPMockProvider) AddInteraction() *V3UnconfiguredInteraction {
 	log.Println("[DEBUG] pact add V3 interaction")
-	interaction := p.mockserver.NewInteraction("")
-
 	i := &V3UnconfiguredInteraction{
-		interaction: &Interaction{
-			specificationVersion: models.V3,
-			interaction:          interaction,
-		},
-		provider: p,
+		interaction: p.newInteraction(models.V3),
+		provider:    p,
 	}
 
 	return i
@@ -86,7 +94,7 @@ type V3RequestBuilder struct {
 // UponReceiving specifies the name of the test case. This becomes the name of
 // the consumer/provider pair in the Pact file. Mandatory.
 func (i *V3UnconfiguredInteraction) UponReceiving(description string) *V3UnconfiguredInteraction {
-	i.interaction.interaction.UponReceiving(description)
+	i.interaction.uponReceiving(description)
 
 	return i
 }
@@ -161,9 +169,8 @@ func (i *V3RequestBuilder) Headers(headers matchers.HeadersMatcher) *V3RequestBu
 
 // JSONBody adds a JSON body to the expected request
 func (i *V3RequestBuilder) JSONBody(body interface{}) *V3RequestBuilder {
-	// TODO: Don't like panic, but not sure if there is a better builder experience?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	if !i.interaction.validBody("request.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -212,9 +219,12 @@ func (i *V3RequestBuilder) Body(contentType string, body []byte) *V3RequestBuild
 	return i
 }
 
-// BodyMatch uses struct tags to automatically determine matchers from the given struct
+// BodyMatch uses struct tags to automatically determine matchers from the given struct,
+// as matchers.MatchV3 does
 func (i *V3RequestBuilder) BodyMatch(body interface{}) *V3RequestBuilder {
-	i.interaction.interaction.WithJSONRequestBody(matchers.MatchV2(body))
+	if m, ok := i.interaction.matchBody("request.body", body); ok {
+		i.interaction.interaction.WithJSONRequestBody(m)
+	}
 
 	return i
 }
@@ -265,9 +275,8 @@ func (i *V3ResponseBuilder) Headers(headers matchers.HeadersMatcher) *V3Response
 
 // JSONBody adds a JSON body to the expected response
 func (i *V3ResponseBuilder) JSONBody(body interface{}) *V3ResponseBuilder {
-	// TODO: Don't like panic, how to build a better builder here - nil return + log?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	if !i.interaction.validBody("response.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -306,14 +315,24 @@ func (i *V3ResponseBuilder) Body(contentType string, body []byte) *V3ResponseBui
 	return i
 }
 
-// BodyMatch uses struct tags to automatically determine matchers from the given struct
+// BodyMatch uses struct tags to automatically determine matchers from the given struct,
+// as matchers.MatchV3 does
 func (i *V3ResponseBuilder) BodyMatch(body interface{}) *V3ResponseBuilder {
-	i.interaction.interaction.WithJSONResponseBody(matchers.MatchV2(body))
+	if m, ok := i.interaction.matchBody("response.body", body); ok {
+		i.interaction.interaction.WithJSONResponseBody(m)
+	}
 
 	return i
 }
 
 // ExecuteTest runs the current test case against a Mock Service.
 func (m *V3InteractionWithResponse) ExecuteTest(t *testing.T, integrationTest func(MockServerConfig) error) error {
+	t.Helper()
 	return m.provider.ExecuteTest(t, integrationTest)
 }
+
+// ExecuteTestContext runs the current test case against a Mock Service, bounded by ctx.
+func (m *V3InteractionWithResponse) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(context.Context, MockServerConfig) error) error {
+	t.Helper()
+	return m.provider.ExecuteTestContext(ctx, t, integrationTest)
+}
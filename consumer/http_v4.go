@@ -1,6 +1,8 @@
 package consumer
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"testing"
 
@@ -11,9 +13,13 @@ import (
 )
 
 // V4HTTPMockProvider is the entrypoint for V4 http consumer tests
-// This object is not thread safe
+// This object is not thread safe, use ForTest to run tests in parallel
 type V4HTTPMockProvider struct {
 	*httpMockProvider
+
+	// Builders applied to every interaction, see WithDefaults
+	defaultRequest  V4RequestBuilderFunc
+	defaultResponse V4ResponseBuilderFunc
 }
 
 // NewV4Pact configures a new V4 HTTP Mock Provider for consumer tests
@@ -33,17 +39,40 @@ func NewV4Pact(config MockHTTPProviderConfig) (*V4HTTPMockProvider, error) {
 	return provider, err
 }
 
+// ForTest returns a copy of the provider that is isolated to the given test, allowing
+// consumer tests to run concurrently with t.Parallel(). Each copy registers its
+// interactions against its own mock server, and merges them into the shared pact
+// file when ExecuteTest succeeds.
+func (p *V4HTTPMockProvider) ForTest(t *testing.T) *V4HTTPMockProvider {
+	t.Helper()
+
+	return &V4HTTPMockProvider{
+		httpMockProvider: p.forTest(t),
+		defaultRequest:   p.defaultRequest,
+		defaultResponse:  p.defaultResponse,
+	}
+}
+
+// WithDefaults sets request and response builders that are applied to every interaction
+// subsequently added with AddInteraction, including interactions using plugins, e.g. to
+// add common headers. Either may be nil.
+//
+// Defaults are applied after the interaction's own builders, and skip any header,
+// query parameter or body that the interaction has set itself, so that individual
+// interactions may override them.
+func (p *V4HTTPMockProvider) WithDefaults(request V4RequestBuilderFunc, response V4ResponseBuilderFunc) *V4HTTPMockProvider {
+	p.defaultRequest = request
+	p.defaultResponse = response
+
+	return p
+}
+
 // AddInteraction to the pact
 func (p *V4HTTPMockProvider) AddInteraction() *V4UnconfiguredInteraction {
 	log.Println("[DEBUG] pact add V4 interaction")
-	interaction := p.mockserver.NewInteraction("")
-
 	i := &V4UnconfiguredInteraction{
-		interaction: &Interaction{
-			specificationVersion: models.V4,
-			interaction:          interaction,
-		},
-		provider: p,
+		interaction: p.newInteraction(models.V4),
+		provider:    p,
 	}
 
 	return i
@@ -82,12 +111,15 @@ type V4RequestBuilderFunc func(*V4RequestBuilder)
 type V4RequestBuilder struct {
 	interaction *Interaction
 	provider    *V4HTTPMockProvider
+
+	// defaults is set when applying the provider's default request builder
+	defaults bool
 }
 
 // UponReceiving specifies the name of the test case. This becomes the name of
 // the consumer/provider pair in the Pact file. Mandatory.
 func (i *V4UnconfiguredInteraction) UponReceiving(description string) *V4UnconfiguredInteraction {
-	i.interaction.interaction.UponReceiving(description)
+	i.interaction.uponReceiving(description)
 
 	return i
 }
@@ -104,6 +136,7 @@ func (i *V4UnconfiguredInteraction) AddExternalReference(group, name, value stri
 // WithRequest provides a builder for the expected request
 func (i *V4UnconfiguredInteraction) WithCompleteRequest(request Request) *V4InteractionWithCompleteRequest {
 	i.interaction.WithCompleteRequest(request)
+	applyDefaultRequest(i.interaction, i.provider)
 
 	return &V4InteractionWithCompleteRequest{
 		interaction: i.interaction,
@@ -119,6 +152,7 @@ type V4InteractionWithCompleteRequest struct {
 // WithRequest provides a builder for the expected request
 func (i *V4InteractionWithCompleteRequest) WithCompleteResponse(response Response) *V4InteractionWithResponse {
 	i.interaction.WithCompleteResponse(response)
+	applyDefaultResponse(i.interaction, i.provider)
 
 	return &V4InteractionWithResponse{
 		interaction: i.interaction,
@@ -141,6 +175,7 @@ func (i *V4UnconfiguredInteraction) WithRequestPathMatcher(method Method, path m
 			provider:    i.provider,
 		})
 	}
+	applyDefaultRequest(i.interaction, i.provider)
 
 	return &V4InteractionWithRequest{
 		interaction: i.interaction,
@@ -148,8 +183,34 @@ func (i *V4UnconfiguredInteraction) WithRequestPathMatcher(method Method, path m
 	}
 }
 
+// applyDefaultRequest applies the provider's default request builder, if any
+func applyDefaultRequest(interaction *Interaction, provider *V4HTTPMockProvider) {
+	if provider.defaultRequest != nil {
+		provider.defaultRequest(&V4RequestBuilder{
+			interaction: interaction,
+			provider:    provider,
+			defaults:    true,
+		})
+	}
+}
+
+// applyDefaultResponse applies the provider's default response builder, if any
+func applyDefaultResponse(interaction *Interaction, provider *V4HTTPMockProvider) {
+	if provider.defaultResponse != nil {
+		provider.defaultResponse(&V4ResponseBuilder{
+			interaction: interaction,
+			provider:    provider,
+			defaults:    true,
+		})
+	}
+}
+
 // Query specifies any query string on the expect request
 func (i *V4RequestBuilder) Query(key string, values ...matchers.Matcher) *V4RequestBuilder {
+	if !i.interaction.claim("request.query."+key, i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithQuery(keyValuesToMapStringArrayInterface(key, values...))
 
 	return i
@@ -157,6 +218,10 @@ func (i *V4RequestBuilder) Query(key string, values ...matchers.Matcher) *V4Requ
 
 // Header adds a header to the expected request
 func (i *V4RequestBuilder) Header(key string, values ...matchers.Matcher) *V4RequestBuilder {
+	if !i.interaction.claim(headerField("request", key), i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithRequestHeaders(keyValuesToMapStringArrayInterface(key, values...))
 
 	return i
@@ -164,16 +229,22 @@ func (i *V4RequestBuilder) Header(key string, values ...matchers.Matcher) *V4Req
 
 // Headers sets the headers on the expected request
 func (i *V4RequestBuilder) Headers(headers matchers.HeadersMatcher) *V4RequestBuilder {
-	i.interaction.interaction.WithRequestHeaders(headersMatcherToNativeHeaders(headers))
+	claimed := make(matchers.HeadersMatcher, len(headers))
+	for k, v := range headers {
+		if i.interaction.claim(headerField("request", k), i.defaults) {
+			claimed[k] = v
+		}
+	}
+
+	i.interaction.interaction.WithRequestHeaders(headersMatcherToNativeHeaders(claimed))
 
 	return i
 }
 
 // JSONBody adds a JSON body to the expected request
 func (i *V4RequestBuilder) JSONBody(body interface{}) *V4RequestBuilder {
-	// TODO: Don't like panic, but not sure if there is a better builder experience?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	if !i.interaction.claim("request.body", i.defaults) || !i.interaction.validBody("request.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -194,6 +265,10 @@ func (i *V4RequestBuilder) JSONBody(body interface{}) *V4RequestBuilder {
 
 // BinaryBody adds a binary body to the expected request
 func (i *V4RequestBuilder) BinaryBody(body []byte) *V4RequestBuilder {
+	if !i.interaction.claim("request.body", i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithBinaryRequestBody(body)
 
 	return i
@@ -201,6 +276,10 @@ func (i *V4RequestBuilder) BinaryBody(body []byte) *V4RequestBuilder {
 
 // MultipartBody adds a multipart  body to the expected request
 func (i *V4RequestBuilder) MultipartBody(contentType string, filename string, mimePartName string) *V4RequestBuilder {
+	if !i.interaction.claim("request.body", i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithRequestMultipartFile(contentType, filename, mimePartName)
 
 	return i
@@ -208,6 +287,10 @@ func (i *V4RequestBuilder) MultipartBody(contentType string, filename string, mi
 
 // Body adds general body to the expected request
 func (i *V4RequestBuilder) Body(contentType string, body []byte) *V4RequestBuilder {
+	if !i.interaction.claim("request.body", i.defaults) {
+		return i
+	}
+
 	// Check if someone tried to add an object as a string representation
 	// as per original allowed implementation, e.g.
 	// { "foo": "bar", "baz": like("bat") }
@@ -222,9 +305,16 @@ func (i *V4RequestBuilder) Body(contentType string, body []byte) *V4RequestBuild
 	return i
 }
 
-// BodyMatch uses struct tags to automatically determine matchers from the given struct
+// BodyMatch uses struct tags to automatically determine matchers from the given struct,
+// as matchers.MatchV4 does
 func (i *V4RequestBuilder) BodyMatch(body interface{}) *V4RequestBuilder {
-	i.interaction.interaction.WithJSONRequestBody(matchers.MatchV2(body))
+	if !i.interaction.claim("request.body", i.defaults) {
+		return i
+	}
+
+	if m, ok := i.interaction.matchBody("request.body", body); ok {
+		i.interaction.interaction.WithJSONRequestBody(m)
+	}
 
 	return i
 }
@@ -240,6 +330,7 @@ func (i *V4InteractionWithRequest) WillRespondWith(status int, builders ...V4Res
 			provider:    i.provider,
 		})
 	}
+	applyDefaultResponse(i.interaction, i.provider)
 
 	return &V4InteractionWithResponse{
 		interaction: i.interaction,
@@ -252,6 +343,9 @@ type V4ResponseBuilderFunc func(*V4ResponseBuilder)
 type V4ResponseBuilder struct {
 	interaction *Interaction
 	provider    *V4HTTPMockProvider
+
+	// defaults is set when applying the provider's default response builder
+	defaults bool
 }
 
 type V4InteractionWithResponse struct {
@@ -261,6 +355,10 @@ type V4InteractionWithResponse struct {
 
 // Header adds a header to the expected response
 func (i *V4ResponseBuilder) Header(key string, values ...matchers.Matcher) *V4ResponseBuilder {
+	if !i.interaction.claim(headerField("response", key), i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithResponseHeaders(keyValuesToMapStringArrayInterface(key, values...))
 
 	return i
@@ -268,16 +366,22 @@ func (i *V4ResponseBuilder) Header(key string, values ...matchers.Matcher) *V4Re
 
 // Headers sets the headers on the expected response
 func (i *V4ResponseBuilder) Headers(headers matchers.HeadersMatcher) *V4ResponseBuilder {
-	i.interaction.interaction.WithResponseHeaders(headersMatcherToNativeHeaders(headers))
+	claimed := make(matchers.HeadersMatcher, len(headers))
+	for k, v := range headers {
+		if i.interaction.claim(headerField("response", k), i.defaults) {
+			claimed[k] = v
+		}
+	}
+
+	i.interaction.interaction.WithResponseHeaders(headersMatcherToNativeHeaders(claimed))
 
 	return i
 }
 
 // JSONBody adds a JSON body to the expected response
 func (i *V4ResponseBuilder) JSONBody(body interface{}) *V4ResponseBuilder {
-	// TODO: Don't like panic, how to build a better builder here - nil return + log?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	if !i.interaction.claim("response.body", i.defaults) || !i.interaction.validBody("response.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -297,6 +401,10 @@ func (i *V4ResponseBuilder) JSONBody(body interface{}) *V4ResponseBuilder {
 
 // BinaryBody adds a binary body to the expected response
 func (i *V4ResponseBuilder) BinaryBody(body []byte) *V4ResponseBuilder {
+	if !i.interaction.claim("response.body", i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithBinaryResponseBody(body)
 
 	return i
@@ -304,6 +412,10 @@ func (i *V4ResponseBuilder) BinaryBody(body []byte) *V4ResponseBuilder {
 
 // MultipartBody adds a multipart  body to the expected response
 func (i *V4ResponseBuilder) MultipartBody(contentType string, filename string, mimePartName string) *V4ResponseBuilder {
+	if !i.interaction.claim("response.body", i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithResponseMultipartFile(contentType, filename, mimePartName)
 
 	return i
@@ -311,23 +423,41 @@ func (i *V4ResponseBuilder) MultipartBody(contentType string, filename string, m
 
 // Body adds general body to the expected request
 func (i *V4ResponseBuilder) Body(contentType string, body []byte) *V4ResponseBuilder {
+	if !i.interaction.claim("response.body", i.defaults) {
+		return i
+	}
+
 	i.interaction.interaction.WithResponseBody(contentType, body)
 
 	return i
 }
 
-// BodyMatch uses struct tags to automatically determine matchers from the given struct
+// BodyMatch uses struct tags to automatically determine matchers from the given struct,
+// as matchers.MatchV4 does
 func (i *V4ResponseBuilder) BodyMatch(body interface{}) *V4ResponseBuilder {
-	i.interaction.interaction.WithJSONResponseBody(matchers.MatchV2(body))
+	if !i.interaction.claim("response.body", i.defaults) {
+		return i
+	}
+
+	if m, ok := i.interaction.matchBody("response.body", body); ok {
+		i.interaction.interaction.WithJSONResponseBody(m)
+	}
 
 	return i
 }
 
 // ExecuteTest runs the current test case against a Mock Service.
 func (m *V4InteractionWithResponse) ExecuteTest(t *testing.T, integrationTest func(MockServerConfig) error) error {
+	t.Helper()
 	return m.provider.ExecuteTest(t, integrationTest)
 }
 
+// ExecuteTestContext runs the current test case against a Mock Service, bounded by ctx.
+func (m *V4InteractionWithResponse) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(context.Context, MockServerConfig) error) error {
+	t.Helper()
+	return m.provider.ExecuteTestContext(ctx, t, integrationTest)
+}
+
 ////////////
 // Plugin //
 ////////////
@@ -343,11 +473,10 @@ type V4InteractionWithPlugin struct {
 }
 
 // UsingPlugin specifies the current interaction relies on one or more plugins for operation
-// If the plugin is not correctly installed, this method will terminate the test immediately with a non-zero status
+// If the plugin is not correctly installed, ExecuteTest will return an error
 func (i *V4UnconfiguredInteraction) UsingPlugin(config PluginConfig) *V4InteractionWithPlugin {
-	res := i.provider.mockserver.UsingPlugin(config.Plugin, config.Version)
-	if res != nil {
-		log.Fatal("pact setup failed:", res)
+	if err := i.provider.mockserver.UsingPlugin(config.Plugin, config.Version); err != nil {
+		i.interaction.configErrors.Add("plugin", fmt.Errorf("unable to use plugin %s %s: %w", config.Plugin, config.Version, err))
 	}
 
 	return &V4InteractionWithPlugin{
@@ -357,11 +486,10 @@ func (i *V4UnconfiguredInteraction) UsingPlugin(config PluginConfig) *V4Interact
 }
 
 // UsingPlugin specifies the current interaction relies on one or more plugins for operation
-// If the plugin is not correctly installed, this method will terminate the test immediately with a non-zero status
+// If the plugin is not correctly installed, ExecuteTest will return an error
 func (i *V4InteractionWithPlugin) UsingPlugin(config PluginConfig) *V4InteractionWithPlugin {
-	res := i.provider.mockserver.UsingPlugin(config.Plugin, config.Version)
-	if res != nil {
-		log.Fatal("pact setup failed:", res)
+	if err := i.provider.mockserver.UsingPlugin(config.Plugin, config.Version); err != nil {
+		i.interaction.configErrors.Add("plugin", fmt.Errorf("unable to use plugin %s %s: %w", config.Plugin, config.Version, err))
 	}
 
 	return i
@@ -387,6 +515,7 @@ func (i *V4InteractionWithPlugin) WithRequest(method Method, path string, builde
 			interaction: i.interaction,
 		})
 	}
+	applyDefaultRequest(i.interaction, i.provider)
 
 	return &V4InteractionWithPluginRequest{
 		interaction: i.interaction,
@@ -403,6 +532,7 @@ func (i *V4InteractionWithPlugin) WithRequestPathMatcher(method Method, path mat
 			interaction: i.interaction,
 		})
 	}
+	applyDefaultRequest(i.interaction, i.provider)
 
 	return &V4InteractionWithPluginRequest{
 		interaction: i.interaction,
@@ -420,6 +550,7 @@ func (i *V4InteractionWithPluginRequest) WillRespondWith(status int, builders ..
 			provider:    i.provider,
 		})
 	}
+	applyDefaultResponse(i.interaction, i.provider)
 
 	return &V4InteractionWithPluginResponse{
 		interaction: i.interaction,
@@ -441,11 +572,19 @@ type V4InteractionWithPluginResponse struct {
 
 // ExecuteTest runs the current test case against a Mock Service.
 func (m *V4InteractionWithPluginResponse) ExecuteTest(t *testing.T, integrationTest func(MockServerConfig) error) error {
+	t.Helper()
 	return m.provider.ExecuteTest(t, integrationTest)
 }
 
+// ExecuteTestContext runs the current test case against a Mock Service, bounded by ctx.
+func (m *V4InteractionWithPluginResponse) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(context.Context, MockServerConfig) error) error {
+	t.Helper()
+	return m.provider.ExecuteTestContext(ctx, t, integrationTest)
+}
+
 // Query specifies any query string on the expect request
 func (i *V4InteractionWithPluginRequestBuilder) Query(key string, values ...matchers.Matcher) *V4InteractionWithPluginRequestBuilder {
+	i.interaction.claim("request.query."+key, false)
 	i.interaction.interaction.WithQuery(keyValuesToMapStringArrayInterface(key, values...))
 
 	return i
@@ -453,6 +592,7 @@ func (i *V4InteractionWithPluginRequestBuilder) Query(key string, values ...matc
 
 // Header adds a header to the expected request
 func (i *V4InteractionWithPluginRequestBuilder) Header(key string, values ...matchers.Matcher) *V4InteractionWithPluginRequestBuilder {
+	i.interaction.claim(headerField("request", key), false)
 	i.interaction.interaction.WithRequestHeaders(keyValuesToMapStringArrayInterface(key, values...))
 
 	return i
@@ -460,6 +600,9 @@ func (i *V4InteractionWithPluginRequestBuilder) Header(key string, values ...mat
 
 // Headers sets the headers on the expected request
 func (i *V4InteractionWithPluginRequestBuilder) Headers(headers matchers.HeadersMatcher) *V4InteractionWithPluginRequestBuilder {
+	for k := range headers {
+		i.interaction.claim(headerField("request", k), false)
+	}
 	i.interaction.interaction.WithRequestHeaders(headersMatcherToNativeHeaders(headers))
 
 	return i
@@ -467,10 +610,11 @@ func (i *V4InteractionWithPluginRequestBuilder) Headers(headers matchers.Headers
 
 // PluginContents configures a plugin. This may be called once per plugin registered.
 func (i *V4InteractionWithPluginRequestBuilder) PluginContents(contentType string, contents string) *V4InteractionWithPluginRequestBuilder {
+	i.interaction.claim("request.body", false)
 	err := i.interaction.interaction.WithPluginInteractionContents(native.INTERACTION_PART_REQUEST, contentType, contents)
 	if err != nil {
 		log.Println("[ERROR] failed to get plugin content for interaction:", err)
-		panic(err)
+		i.interaction.configErrors.Add("request.contents", err)
 	}
 
 	return i
@@ -478,9 +622,9 @@ func (i *V4InteractionWithPluginRequestBuilder) PluginContents(contentType strin
 
 // JSONBody adds a JSON body to the expected request
 func (i *V4InteractionWithPluginRequestBuilder) JSONBody(body interface{}) *V4InteractionWithPluginRequestBuilder {
-	// TODO: Don't like panic, but not sure if there is a better builder experience?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	i.interaction.claim("request.body", false)
+	if !i.interaction.validBody("request.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -501,6 +645,7 @@ func (i *V4InteractionWithPluginRequestBuilder) JSONBody(body interface{}) *V4In
 
 // BinaryBody adds a binary body to the expected request
 func (i *V4InteractionWithPluginRequestBuilder) BinaryBody(body []byte) *V4InteractionWithPluginRequestBuilder {
+	i.interaction.claim("request.body", false)
 	i.interaction.interaction.WithBinaryRequestBody(body)
 
 	return i
@@ -508,6 +653,7 @@ func (i *V4InteractionWithPluginRequestBuilder) BinaryBody(body []byte) *V4Inter
 
 // MultipartBody adds a multipart  body to the expected request
 func (i *V4InteractionWithPluginRequestBuilder) MultipartBody(contentType string, filename string, mimePartName string) *V4InteractionWithPluginRequestBuilder {
+	i.interaction.claim("request.body", false)
 	i.interaction.interaction.WithRequestMultipartFile(contentType, filename, mimePartName)
 
 	return i
@@ -515,6 +661,7 @@ func (i *V4InteractionWithPluginRequestBuilder) MultipartBody(contentType string
 
 // Body adds general body to the expected request
 func (i *V4InteractionWithPluginRequestBuilder) Body(contentType string, body []byte) *V4InteractionWithPluginRequestBuilder {
+	i.interaction.claim("request.body", false)
 	// Check if someone tried to add an object as a string representation
 	// as per original allowed implementation, e.g.
 	// { "foo": "bar", "baz": like("bat") }
@@ -529,15 +676,20 @@ func (i *V4InteractionWithPluginRequestBuilder) Body(contentType string, body []
 	return i
 }
 
-// BodyMatch uses struct tags to automatically determine matchers from the given struct
+// BodyMatch uses struct tags to automatically determine matchers from the given struct,
+// as matchers.MatchV4 does
 func (i *V4InteractionWithPluginRequestBuilder) BodyMatch(body interface{}) *V4InteractionWithPluginRequestBuilder {
-	i.interaction.interaction.WithJSONRequestBody(matchers.MatchV2(body))
+	i.interaction.claim("request.body", false)
+	if m, ok := i.interaction.matchBody("request.body", body); ok {
+		i.interaction.interaction.WithJSONRequestBody(m)
+	}
 
 	return i
 }
 
 // Header adds a header to the expected response
 func (i *V4InteractionWithPluginResponseBuilder) Header(key string, values ...matchers.Matcher) *V4InteractionWithPluginResponseBuilder {
+	i.interaction.claim(headerField("response", key), false)
 	i.interaction.interaction.WithResponseHeaders(keyValuesToMapStringArrayInterface(key, values...))
 
 	return i
@@ -545,6 +697,9 @@ func (i *V4InteractionWithPluginResponseBuilder) Header(key string, values ...ma
 
 // Headers sets the headers on the expected response
 func (i *V4InteractionWithPluginResponseBuilder) Headers(headers matchers.HeadersMatcher) *V4InteractionWithPluginResponseBuilder {
+	for k := range headers {
+		i.interaction.claim(headerField("response", k), false)
+	}
 	i.interaction.interaction.WithResponseHeaders(headersMatcherToNativeHeaders(headers))
 
 	return i
@@ -552,10 +707,11 @@ func (i *V4InteractionWithPluginResponseBuilder) Headers(headers matchers.Header
 
 // PluginContents configures a plugin. This may be called once per plugin registered.
 func (i *V4InteractionWithPluginResponseBuilder) PluginContents(contentType string, contents string) *V4InteractionWithPluginResponseBuilder {
+	i.interaction.claim("response.body", false)
 	err := i.interaction.interaction.WithPluginInteractionContents(native.INTERACTION_PART_RESPONSE, contentType, contents)
 	if err != nil {
 		log.Println("[ERROR] failed to get plugin content for interaction:", err)
-		panic(err)
+		i.interaction.configErrors.Add("response.contents", err)
 	}
 
 	return i
@@ -563,9 +719,9 @@ func (i *V4InteractionWithPluginResponseBuilder) PluginContents(contentType stri
 
 // JSONBody adds a JSON body to the expected response
 func (i *V4InteractionWithPluginResponseBuilder) JSONBody(body interface{}) *V4InteractionWithPluginResponseBuilder {
-	// TODO: Don't like panic, how to build a better builder here - nil return + log?
-	if err := validateMatchers(i.interaction.specificationVersion, body); err != nil {
-		panic(err)
+	i.interaction.claim("response.body", false)
+	if !i.interaction.validBody("response.body", body) {
+		return i
 	}
 
 	if s, ok := body.(string); ok {
@@ -585,6 +741,7 @@ func (i *V4InteractionWithPluginResponseBuilder) JSONBody(body interface{}) *V4I
 
 // BinaryBody adds a binary body to the expected response
 func (i *V4InteractionWithPluginResponseBuilder) BinaryBody(body []byte) *V4InteractionWithPluginResponseBuilder {
+	i.interaction.claim("response.body", false)
 	i.interaction.interaction.WithBinaryResponseBody(body)
 
 	return i
@@ -592,6 +749,7 @@ func (i *V4InteractionWithPluginResponseBuilder) BinaryBody(body []byte) *V4Inte
 
 // MultipartBody adds a multipart  body to the expected response
 func (i *V4InteractionWithPluginResponseBuilder) MultipartBody(contentType string, filename string, mimePartName string) *V4InteractionWithPluginResponseBuilder {
+	i.interaction.claim("response.body", false)
 	i.interaction.interaction.WithResponseMultipartFile(contentType, filename, mimePartName)
 
 	return i
@@ -599,14 +757,19 @@ func (i *V4InteractionWithPluginResponseBuilder) MultipartBody(contentType strin
 
 // Body adds general body to the expected request
 func (i *V4InteractionWithPluginResponseBuilder) Body(contentType string, body []byte) *V4InteractionWithPluginResponseBuilder {
+	i.interaction.claim("response.body", false)
 	i.interaction.interaction.WithResponseBody(contentType, body)
 
 	return i
 }
 
-// BodyMatch uses struct tags to automatically determine matchers from the given struct
+// BodyMatch uses struct tags to automatically determine matchers from the given struct,
+// as matchers.MatchV4 does
 func (i *V4InteractionWithPluginResponseBuilder) BodyMatch(body interface{}) *V4InteractionWithPluginResponseBuilder {
-	i.interaction.interaction.WithJSONResponseBody(matchers.MatchV2(body))
+	i.interaction.claim("response.body", false)
+	if m, ok := i.interaction.matchBody("response.body", body); ok {
+		i.interaction.interaction.WithJSONResponseBody(m)
+	}
 
 	return i
 }
@@ -1,13 +1,18 @@
 package consumer
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/pact-foundation/pact-go/v2/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -105,6 +110,196 @@ func TestV4HTTPAddExternalReference(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestV4HTTPParallel(t *testing.T) {
+	dir := t.TempDir()
+	p, err := NewV4Pact(MockHTTPProviderConfig{
+		Consumer: "consumer",
+		Provider: "provider",
+		PactDir:  dir,
+	})
+	assert.NoError(t, err)
+
+	var want []string
+	// The group completes once all of its parallel tests have
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			path := fmt.Sprintf("/users/%d", i)
+			description := fmt.Sprintf("a request for %s", path)
+			want = append(want, description)
+
+			t.Run(path, func(t *testing.T) {
+				t.Parallel()
+
+				err := p.ForTest(t).
+					AddInteraction().
+					UponReceiving(description).
+					WithRequest("GET", path).
+					WillRespondWith(200).
+					ExecuteTest(t, func(msc MockServerConfig) error {
+						_, err := http.Get(fmt.Sprintf("http://%s:%d%s", msc.Host, msc.Port, path))
+						return err
+					})
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	pact, err := models.Load(filepath.Join(dir, "consumer-provider.json"))
+	assert.NoError(t, err)
+
+	var got []string
+	for _, i := range pact.Interactions {
+		got = append(got, i.Details().Description)
+	}
+	assert.ElementsMatch(t, want, got)
+}
+
+func TestV4HTTPTLS(t *testing.T) {
+	p, err := NewV4Pact(MockHTTPProviderConfig{
+		Consumer: "consumer",
+		Provider: "provider",
+		PactDir:  t.TempDir(),
+		TLS:      true,
+	})
+	assert.NoError(t, err)
+
+	err = p.AddInteraction().
+		UponReceiving("a request over TLS").
+		WithRequest("GET", "/").
+		WillRespondWith(200).
+		ExecuteTest(t, func(msc MockServerConfig) error {
+			assert.True(t, msc.TLS)
+			assert.True(t, strings.HasPrefix(msc.BaseURL(), "https://"))
+
+			res, err := msc.HTTPClient().Get(msc.BaseURL() + "/")
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, 200, res.StatusCode)
+
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+func TestV4HTTPWithDefaults(t *testing.T) {
+	p, err := NewV4Pact(MockHTTPProviderConfig{
+		Consumer: "consumer",
+		Provider: "provider",
+		PactDir:  t.TempDir(),
+	})
+	assert.NoError(t, err)
+
+	p.WithDefaults(func(b *V4RequestBuilder) {
+		b.Header("Authorization", Like("Bearer 1234"))
+		b.Header("Accept", S("application/json"))
+	}, func(b *V4ResponseBuilder) {
+		b.Header("Content-Type", S("application/json"))
+	})
+
+	err = p.AddInteraction().
+		UponReceiving("a request with default headers").
+		WithRequest("GET", "/", func(b *V4RequestBuilder) {
+			b.Header("Accept", S("text/plain"))
+		}).
+		WillRespondWith(200).
+		ExecuteTest(t, func(msc MockServerConfig) error {
+			req, _ := http.NewRequest("GET", fmt.Sprintf("http://%s:%d/", msc.Host, msc.Port), nil)
+			req.Header.Set("Authorization", "Bearer 5678")
+			req.Header.Set("Accept", "text/plain")
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+func TestV4HTTPWithDefaultsUsingPlugin(t *testing.T) {
+	p, err := NewV4Pact(MockHTTPProviderConfig{
+		Consumer: "consumer",
+		Provider: "provider",
+		PactDir:  t.TempDir(),
+	})
+	assert.NoError(t, err)
+
+	var request, response bool
+	p.WithDefaults(func(b *V4RequestBuilder) {
+		request = true
+		b.Header("Authorization", Like("Bearer 1234"))
+	}, func(b *V4ResponseBuilder) {
+		response = true
+		b.Header("Content-Type", S("application/json"))
+	})
+
+	i := p.AddInteraction().
+		UponReceiving("a plugin request with default headers").
+		UsingPlugin(PluginConfig{
+			Plugin:  "not-installed",
+			Version: "0.0.1",
+		}).
+		WithRequest("GET", "/", func(b *V4InteractionWithPluginRequestBuilder) {
+			b.Header("Authorization", S("Bearer 5678"))
+		}).
+		WillRespondWith(200, func(b *V4InteractionWithPluginResponseBuilder) {
+			b.Body("application/octet-stream", []byte{1, 2, 3})
+		})
+
+	assert.True(t, request)
+	assert.True(t, response)
+	assert.False(t, i.interaction.claim(headerField("request", "Authorization"), true))
+	assert.False(t, i.interaction.claim("response.body", true))
+}
+
+func TestV4HTTPExecuteTestContext(t *testing.T) {
+	p, err := NewV4Pact(MockHTTPProviderConfig{
+		Consumer: "consumer",
+		Provider: "provider",
+		PactDir:  t.TempDir(),
+	})
+	assert.NoError(t, err)
+
+	t.Run("completes within the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := p.AddInteraction().
+			UponReceiving("a request within the deadline").
+			WithRequest("GET", "/").
+			WillRespondWith(200).
+			ExecuteTestContext(ctx, t, func(ctx context.Context, msc MockServerConfig) error {
+				req, _ := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("http://%s:%d/", msc.Host, msc.Port), nil)
+				_, err := http.DefaultClient.Do(req)
+				return err
+			})
+		assert.NoError(t, err)
+	})
+
+	t.Run("returns a timeout when the deadline passes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		hung := make(chan struct{})
+		defer close(hung)
+
+		err := p.AddInteraction().
+			UponReceiving("a request that never completes").
+			WithRequest("GET", "/").
+			WillRespondWith(200).
+			ExecuteTestContext(ctx, t, func(ctx context.Context, msc MockServerConfig) error {
+				<-hung
+				return nil
+			})
+
+		var timeoutErr *TimeoutError
+		assert.True(t, errors.As(err, &timeoutErr))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
 var Like = matchers.Like
 var EachLike = matchers.EachLike
 var Term = matchers.Term
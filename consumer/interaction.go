@@ -3,8 +3,10 @@ package consumer
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/pact-foundation/pact-go/v2/internal/dsl"
 	mockserver "github.com/pact-foundation/pact-go/v2/internal/native"
 	"github.com/pact-foundation/pact-go/v2/matchers"
 	"github.com/pact-foundation/pact-go/v2/models"
@@ -15,6 +17,71 @@ type Interaction struct {
 	// Reference to the native rust handle
 	interaction          *mockserver.Interaction
 	specificationVersion models.SpecificationVersion
+
+	// Configuration errors, returned by ExecuteTest
+	configErrors dsl.Errors
+
+	// Fields (e.g. "request.body") set by the interaction itself, which defaults may not override
+	fields map[string]bool
+}
+
+// claim reports whether a builder may set the given field. Fields set by the
+// interaction are recorded, and may not subsequently be set by defaults.
+func (i *Interaction) claim(field string, isDefault bool) bool {
+	if isDefault {
+		return !i.fields[field]
+	}
+
+	if i.fields == nil {
+		i.fields = make(map[string]bool)
+	}
+	i.fields[field] = true
+
+	return true
+}
+
+// headerField is the field used to claim a header, header names are case insensitive
+func headerField(part string, name string) string {
+	return part + ".header." + strings.ToLower(name)
+}
+
+// uponReceiving sets the description of the interaction
+func (i *Interaction) uponReceiving(description string) {
+	i.configErrors.Description = description
+	i.interaction.UponReceiving(description)
+}
+
+// validBody reports whether the body only contains matchers supported by the
+// specification version, recording an error against the field if not
+func (i *Interaction) validBody(path string, body interface{}) bool {
+	if err := validateMatchers(i.specificationVersion, body); err != nil {
+		i.configErrors.Add(path, err)
+		return false
+	}
+
+	return true
+}
+
+// matchBody uses struct tags to determine matchers from the given struct, with the
+// matchers of the specification version (see matchers.MatchV2E, MatchV3E and
+// MatchV4E), recording an error against the field if they can't be determined (e.g.
+// an invalid tag)
+func (i *Interaction) matchBody(path string, body interface{}) (matchers.Matcher, bool) {
+	match := matchers.MatchV2E
+	switch i.specificationVersion {
+	case models.V3:
+		match = matchers.MatchV3E
+	case models.V4:
+		match = matchers.MatchV4E
+	}
+
+	m, err := match(body)
+	if err != nil {
+		i.configErrors.Add(path, err)
+		return nil, false
+	}
+
+	return m, true
 }
 
 // WithCompleteRequest specifies the details of the HTTP request that will be used to
@@ -24,14 +91,21 @@ func (i *Interaction) WithCompleteRequest(request Request) *Interaction {
 	i.interaction.WithRequest(string(request.Method), request.Path)
 
 	if request.Body != nil {
+		i.claim("request.body", false)
 		i.interaction.WithJSONRequestBody(request.Body)
 	}
 
 	if request.Headers != nil {
+		for k := range request.Headers {
+			i.claim(headerField("request", k), false)
+		}
 		i.interaction.WithRequestHeaders(headersMapMatcherToNativeHeaders(request.Headers))
 	}
 
 	if request.Query != nil {
+		for k := range request.Query {
+			i.claim("request.query."+k, false)
+		}
 		i.interaction.WithQuery(headersMapMatcherToNativeHeaders(request.Query))
 	}
 
@@ -41,10 +115,14 @@ func (i *Interaction) WithCompleteRequest(request Request) *Interaction {
 // WithCompleteResponse specifies the details of the HTTP response required by the consumer
 func (i *Interaction) WithCompleteResponse(response Response) *Interaction {
 	if response.Body != nil {
+		i.claim("response.body", false)
 		i.interaction.WithJSONResponseBody(response.Body)
 	}
 
 	if response.Headers != nil {
+		for k := range response.Headers {
+			i.claim(headerField("response", k), false)
+		}
 		i.interaction.WithResponseHeaders(headersMapMatcherToNativeHeaders(response.Headers))
 	}
 
@@ -70,7 +148,8 @@ func validateMatchers(version models.SpecificationVersion, obj interface{}) erro
 		return nil
 	}
 
-	invalidMatchers := hasMatcherGreaterThanSpec(version, maybeMatchers)
+	invalidMatchers := hasMatcherGreaterThanSpec(version, "$", maybeMatchers)
+	sort.Strings(invalidMatchers)
 
 	if len(invalidMatchers) > 0 {
 		return fmt.Errorf("the current pact file with specification version %s has attempted to use matchers from a higher spec version: %s", version, strings.Join(invalidMatchers, ", "))
@@ -79,17 +158,24 @@ func validateMatchers(version models.SpecificationVersion, obj interface{}) erro
 	return nil
 }
 
-func hasMatcherGreaterThanSpec(version models.SpecificationVersion, obj map[string]interface{}) []string {
+// hasMatcherGreaterThanSpec returns the type and location of each matcher in obj
+// that requires a higher specification version
+func hasMatcherGreaterThanSpec(version models.SpecificationVersion, path string, obj map[string]interface{}) []string {
 	results := make([]string, 0)
 
 	for k, v := range obj {
 		if k == "pact:specification" && v.(string) > string(version) {
-			results = append(results, obj["pact:matcher:type"].(string))
+			results = append(results, fmt.Sprintf("%s (%s)", obj["pact:matcher:type"], path))
 		}
 
 		m, ok := v.(map[string]interface{})
 		if ok {
-			results = append(results, hasMatcherGreaterThanSpec(version, m)...)
+			// The value of a matcher is at the same location as the matcher itself
+			child := path + "." + k
+			if _, isMatcher := obj["pact:matcher:type"]; isMatcher && k == "value" {
+				child = path
+			}
+			results = append(results, hasMatcherGreaterThanSpec(version, child, m)...)
 		}
 	}
 
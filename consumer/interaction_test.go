@@ -66,4 +66,70 @@ func TestInteraction(t *testing.T) {
 			}
 		}
 	})
+	t.Run("validateMatchers reports the location of invalid matchers", func(t *testing.T) {
+		err := validateMatchers(models.V2, map[string]interface{}{
+			"items": matchers.Like(map[string]interface{}{
+				"name": matchers.Includes("foo"),
+			}),
+		})
+
+		assert.ErrorContains(t, err, "include ($.items.name)")
+	})
+
+	t.Run("records invalid bodies against the interaction", func(t *testing.T) {
+		i := &Interaction{specificationVersion: models.V2}
+		i.configErrors.Description = "a request"
+
+		ok := i.validBody("request.body", map[string]interface{}{"name": matchers.Includes("foo")})
+		assert.False(t, ok)
+
+		var interactionErr *InteractionError
+		err := i.configErrors.Err()
+		assert.True(t, errors.As(err, &interactionErr))
+		assert.Equal(t, "a request", interactionErr.Description)
+		assert.Equal(t, "request.body", interactionErr.Path)
+	})
+
+	t.Run("records struct tag errors against the interaction", func(t *testing.T) {
+		i := &Interaction{specificationVersion: models.V2}
+
+		_, ok := i.matchBody("response.body", struct {
+			ID int `json:"id" pact:"example=abc"`
+		}{})
+		assert.False(t, ok)
+		assert.ErrorContains(t, i.configErrors.Err(), "response.body")
+	})
+
+	t.Run("matches struct tags of the specification version", func(t *testing.T) {
+		type dto struct {
+			ID   int               `json:"id" pact:"example=10"`
+			Tags map[string]string `json:"tags"`
+		}
+		i := &Interaction{specificationVersion: models.V4}
+
+		m, ok := i.matchBody("response.body", dto{})
+		assert.True(t, ok)
+		assert.Equal(t, matchers.MatchV4(dto{}), m)
+
+		i = &Interaction{specificationVersion: models.V3}
+		i.configErrors.Description = "a request"
+		_, ok = i.matchBody("request.body", struct {
+			Name string `json:"name" pact:"notempty"`
+		}{})
+		assert.False(t, ok)
+
+		var interactionErr *InteractionError
+		assert.True(t, errors.As(i.configErrors.Err(), &interactionErr))
+		assert.Equal(t, "request.body", interactionErr.Path)
+	})
+	t.Run("defaults may only set fields not set by the interaction", func(t *testing.T) {
+		i := &Interaction{}
+
+		assert.True(t, i.claim(headerField("request", "Authorization"), false))
+		assert.False(t, i.claim(headerField("request", "authorization"), true))
+		assert.True(t, i.claim(headerField("request", "Accept"), true))
+		assert.True(t, i.claim(headerField("response", "Authorization"), true))
+		assert.True(t, i.claim("request.body", false))
+		assert.False(t, i.claim("request.body", true))
+	})
 }
@@ -0,0 +1,37 @@
+package consumer
+
+import (
+	"log"
+
+	"github.com/pact-foundation/pact-go/v2/lint"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// lintPact returns a check of a pact that logs its findings, and returns a *LintError
+// if the check fails
+func lintPact(config *lint.Config) func(pact []byte) error {
+	return func(pact []byte) error {
+		p, err := models.ParsePactFile(pact)
+		if err != nil {
+			return err
+		}
+
+		report, err := lint.Check(p, config)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range report.Findings {
+			switch f.Severity {
+			case lint.Error:
+				log.Println("[ERROR]", f)
+			case lint.Warning:
+				log.Println("[WARN]", f)
+			default:
+				log.Println("[INFO]", f)
+			}
+		}
+
+		return report.Err()
+	}
+}
@@ -0,0 +1,28 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintPact(t *testing.T) {
+	pact := []byte(`{
+		"consumer": {"name": "web"},
+		"provider": {"name": "users"},
+		"interactions": [{"description": "a request to delete a user", "request": {"method": "DELETE", "path": "/users/1"}, "response": {"status": 204}}],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`)
+
+	assert.NoError(t, lintPact(&lint.Config{})(pact))
+
+	err := lintPact(&lint.Config{FailOn: lint.Warning})(pact)
+	var lintErr *LintError
+	assert.True(t, errors.As(err, &lintErr))
+	assert.Len(t, lintErr.Failures, 1)
+	assert.Equal(t, "mutating-without-state", lintErr.Failures[0].Rule)
+
+	assert.Error(t, lintPact(&lint.Config{})([]byte("not a pact")))
+}
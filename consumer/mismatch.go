@@ -0,0 +1,147 @@
+package consumer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/native"
+)
+
+// MismatchedRequest is a request received (or expected) by the mock server that
+// did not match the registered interactions
+type MismatchedRequest = native.MismatchedRequest
+
+// MismatchDetail describes an individual failed assertion within a MismatchedRequest
+type MismatchDetail = native.MismatchDetail
+
+// Request level mismatch types, as reported in MismatchedRequest.Type
+const (
+	// MismatchTypeMissingRequest indicates an expected request was never received
+	MismatchTypeMissingRequest = "missing-request"
+
+	// MismatchTypeRequestNotFound indicates a request was received that matched no interaction
+	MismatchTypeRequestNotFound = "request-not-found"
+
+	// MismatchTypeRequestMismatch indicates a request partially matched an interaction
+	MismatchTypeRequestMismatch = "request-mismatch"
+)
+
+// Detail level mismatch types, as reported in MismatchDetail.Type
+const (
+	MismatchTypeMethod   = "MethodMismatch"
+	MismatchTypePath     = "PathMismatch"
+	MismatchTypeStatus   = "StatusMismatch"
+	MismatchTypeQuery    = "QueryMismatch"
+	MismatchTypeHeader   = "HeaderMismatch"
+	MismatchTypeBodyType = "BodyTypeMismatch"
+	MismatchTypeBody     = "BodyMismatch"
+	MismatchTypeMetadata = "MetadataMismatch"
+)
+
+// MismatchError is returned by ExecuteTest when the mock server did not receive
+// the requests described by the registered interactions.
+//
+// Use errors.As to inspect the individual mismatches:
+//
+//	var mismatchErr *consumer.MismatchError
+//	if errors.As(err, &mismatchErr) {
+//		headers := mismatchErr.Details(consumer.MismatchTypeHeader)
+//	}
+type MismatchError struct {
+	// TestName is the name of the test that produced the mismatches
+	TestName string
+
+	// Mismatches is the full set of mismatches reported by the mock server
+	Mismatches []MismatchedRequest
+
+	// interactions of the test, to attribute the mismatches to in Diff
+	interactions []native.InteractionSummary
+}
+
+// Error implements the error interface
+func (e *MismatchError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "pact validation failed for %s: %d mismatched request(s)", e.TestName, len(e.Mismatches))
+
+	for _, m := range e.Mismatches {
+		request := fmt.Sprintf("%s %s", m.Method, m.Path)
+
+		switch m.Type {
+		case MismatchTypeMissingRequest:
+			fmt.Fprintf(&b, "\n\texpected: %s (Expected request that was not received)", request)
+		case MismatchTypeRequestNotFound:
+			fmt.Fprintf(&b, "\n\tactual: %s (Unexpected request was received)", request)
+		default:
+			fmt.Fprintf(&b, "\n\tmismatch: %s", request)
+		}
+
+		for _, d := range m.Mismatches {
+			fmt.Fprintf(&b, "\n\t\t%s", describeMismatchDetail(d))
+		}
+	}
+
+	return b.String()
+}
+
+// Details returns the mismatch details of the given types (e.g. MismatchTypeHeader)
+// across all mismatched requests. All details are returned if no types are given.
+func (e *MismatchError) Details(types ...string) []MismatchDetail {
+	details := make([]MismatchDetail, 0)
+
+	for _, m := range e.Mismatches {
+		for _, d := range m.Mismatches {
+			if len(types) == 0 || slices.Contains(types, d.Type) {
+				details = append(details, d)
+			}
+		}
+	}
+
+	return details
+}
+
+// Requests returns the mismatched requests of the given types (e.g. MismatchTypeMissingRequest).
+// All requests are returned if no types are given.
+func (e *MismatchError) Requests(types ...string) []MismatchedRequest {
+	requests := make([]MismatchedRequest, 0)
+
+	for _, m := range e.Mismatches {
+		if len(types) == 0 || slices.Contains(types, m.Type) {
+			requests = append(requests, m)
+		}
+	}
+
+	return requests
+}
+
+// describeMismatchDetail formats a single mismatch on one line, with the location
+// of the mismatch (header, query parameter, body path etc.) first
+func describeMismatchDetail(d MismatchDetail) string {
+	location := d.Type
+	switch d.Type {
+	case MismatchTypeHeader:
+		location = fmt.Sprintf("header %q", d.Key)
+	case MismatchTypeQuery:
+		location = fmt.Sprintf("query parameter %q", d.Parameter)
+	case MismatchTypeMetadata:
+		location = fmt.Sprintf("metadata %q", d.Key)
+	case MismatchTypeBody:
+		location = fmt.Sprintf("body %s", d.Path)
+	case MismatchTypeBodyType:
+		location = "body content type"
+	case MismatchTypeMethod:
+		location = "method"
+	case MismatchTypePath:
+		location = "path"
+	case MismatchTypeStatus:
+		location = "status"
+	}
+
+	description := d.Mismatch
+	if description == "" {
+		description = fmt.Sprintf("expected %q but received %q", d.Expected, d.Actual)
+	}
+
+	return fmt.Sprintf("%s: %s", location, description)
+}
@@ -0,0 +1,417 @@
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/native"
+)
+
+// Number of unchanged lines to show either side of a change in a body diff
+const diffContextLines = 3
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// Diff renders the mismatches as a human readable report, grouped by the interaction
+// (or, if it can't be told, the request) they relate to, with a JSON path annotated
+// unified diff for each body mismatch.
+//
+// Output is colourised unless the NO_COLOR environment variable is set
+// (see https://no-color.org).
+func (e *MismatchError) Diff() string {
+	return renderMismatches(e.Mismatches, e.interactions, colourEnabled())
+}
+
+// colourEnabled reports whether ANSI colours should be used in the output
+func colourEnabled() bool {
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// diffStyle applies (or omits) ANSI styles to rendered output
+type diffStyle struct {
+	colour bool
+}
+
+func (s diffStyle) paint(code string, text string) string {
+	if !s.colour || text == "" {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+// renderMismatches formats the mismatches, grouping them by the interaction they
+// relate to
+func renderMismatches(mismatches []MismatchedRequest, interactions []native.InteractionSummary, colour bool) string {
+	style := diffStyle{colour: colour}
+	var b strings.Builder
+
+	for i, g := range groupMismatches(mismatches, interactions) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(style.paint(ansiBold, g.header))
+		b.WriteString("\n")
+
+		for _, m := range g.mismatches {
+			switch m.Type {
+			case MismatchTypeMissingRequest:
+				fmt.Fprintf(&b, "  %s\n", style.paint(ansiRed, "expected request was not received"))
+			case MismatchTypeRequestNotFound:
+				fmt.Fprintf(&b, "  %s\n", style.paint(ansiRed, "unexpected request was received"))
+			}
+
+			for _, d := range m.Mismatches {
+				renderMismatchDetail(&b, style, d)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// mismatchGroup is the mismatches of an interaction, or of a request that can't be
+// attributed to a single interaction
+type mismatchGroup struct {
+	header     string
+	mismatches []MismatchedRequest
+}
+
+// groupMismatches groups the mismatches by the interaction with the method and path
+// of their request. The mock server reports a missing request for each interaction,
+// so missing requests are attributed to different interactions where possible.
+func groupMismatches(mismatches []MismatchedRequest, interactions []native.InteractionSummary) []*mismatchGroup {
+	var groups []*mismatchGroup
+	byKey := make(map[string]*mismatchGroup)
+	missing := make(map[int]bool)
+
+	for _, m := range mismatches {
+		request := fmt.Sprintf("%s %s", m.Method, m.Path)
+		if m.Query != "" {
+			request += "?" + m.Query
+		}
+
+		var candidates []int
+		if m.Type != MismatchTypeRequestNotFound {
+			candidates = candidateInteractions(m, interactions)
+		}
+		if m.Type == MismatchTypeMissingRequest {
+			for _, c := range candidates {
+				if !missing[c] {
+					candidates = []int{c}
+					break
+				}
+			}
+			if len(candidates) == 1 {
+				missing[candidates[0]] = true
+			}
+		}
+
+		key := request
+		header := request
+		if len(candidates) > 0 {
+			descriptions := make([]string, len(candidates))
+			for n, c := range candidates {
+				descriptions[n] = describeInteraction(interactions[c])
+			}
+			key = fmt.Sprint(candidates)
+			header = request + ": " + strings.Join(descriptions, " or ")
+		}
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &mismatchGroup{header: header}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.mismatches = append(g.mismatches, m)
+	}
+
+	return groups
+}
+
+// candidateInteractions returns the indexes of the interactions whose request has
+// the method and path of the mismatched request
+func candidateInteractions(m MismatchedRequest, interactions []native.InteractionSummary) []int {
+	var candidates []int
+	for n, i := range interactions {
+		if strings.EqualFold(i.Method, m.Method) && pathMatches(i.Path, m.Path) {
+			candidates = append(candidates, n)
+		}
+	}
+
+	return candidates
+}
+
+// pathMatches reports whether the path matches the path of an interaction, which is
+// either the path itself or the JSON form of a matcher
+func pathMatches(expected string, path string) bool {
+	var matcher struct {
+		Regex string      `json:"regex"`
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(expected), &matcher); err != nil {
+		return expected == path
+	}
+	if matcher.Regex != "" {
+		re, err := regexp.Compile(matcher.Regex)
+		return err == nil && re.MatchString(path)
+	}
+
+	return fmt.Sprint(matcher.Value) == path
+}
+
+// describeInteraction names an interaction, e.g. "a request for a user" given "a user exists"
+func describeInteraction(i native.InteractionSummary) string {
+	description := fmt.Sprintf("%q", i.Description)
+	if len(i.ProviderStates) > 0 {
+		states := make([]string, len(i.ProviderStates))
+		for n, state := range i.ProviderStates {
+			states[n] = fmt.Sprintf("%q", state)
+		}
+		description += " given " + strings.Join(states, " and ")
+	}
+
+	return description
+}
+
+// renderMismatchDetail writes a single mismatch, followed by the expected and actual
+// values, or a diff of them for body mismatches
+func renderMismatchDetail(b *strings.Builder, style diffStyle, d MismatchDetail) {
+	fmt.Fprintf(b, "  %s\n", describeMismatchDetail(d))
+
+	if d.Expected == "" && d.Actual == "" {
+		return
+	}
+
+	if d.Type == MismatchTypeBody {
+		for _, line := range diffBodies(d.Path, d.Expected, d.Actual, style) {
+			fmt.Fprintf(b, "    %s\n", line)
+		}
+		return
+	}
+
+	fmt.Fprintf(b, "    %s\n", style.paint(ansiRed, fmt.Sprintf("- expected: %s", d.Expected)))
+	fmt.Fprintf(b, "    %s\n", style.paint(ansiGreen, fmt.Sprintf("+ actual:   %s", d.Actual)))
+}
+
+// jsonLine is a single line of a pretty printed JSON document, along with the
+// JSON path of the value it belongs to
+type jsonLine struct {
+	text string
+	path string
+}
+
+// diffBodies produces a unified diff of the expected and actual values found at the
+// given path. Values that are not valid JSON are compared line by line.
+func diffBodies(path string, expected string, actual string, style diffStyle) []string {
+	if path == "" {
+		path = "$"
+	}
+
+	a := bodyLines(path, expected)
+	b := bodyLines(path, actual)
+
+	var out []string
+	out = append(out, style.paint(ansiRed, "--- expected"))
+	out = append(out, style.paint(ansiGreen, "+++ actual"))
+
+	for _, h := range diffHunks(diffLines(a, b)) {
+		out = append(out, style.paint(ansiCyan, fmt.Sprintf("@@ %s @@", h.path)))
+
+		for _, op := range h.ops {
+			switch op.kind {
+			case ' ':
+				out = append(out, "  "+op.line.text)
+			case '-':
+				out = append(out, style.paint(ansiRed, "- "+op.line.text)+"  "+style.paint(ansiDim, op.line.path))
+			case '+':
+				out = append(out, style.paint(ansiGreen, "+ "+op.line.text)+"  "+style.paint(ansiDim, op.line.path))
+			}
+		}
+	}
+
+	return out
+}
+
+// bodyLines pretty prints a body fragment, annotating each line with its JSON path
+func bodyLines(path string, body string) []jsonLine {
+	if body == "" {
+		return nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		lines := make([]jsonLine, 0)
+		for _, l := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+			lines = append(lines, jsonLine{text: l, path: path})
+		}
+		return lines
+	}
+
+	return appendJSONLines(nil, "", "", v, path, "")
+}
+
+var simpleKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// childPath builds the JSON path of an object member
+func childPath(path string, key string) string {
+	if simpleKey.MatchString(key) {
+		return path + "." + key
+	}
+
+	return fmt.Sprintf("%s['%s']", path, key)
+}
+
+func appendJSONLines(lines []jsonLine, indent string, prefix string, v interface{}, path string, suffix string) []jsonLine {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return append(lines, jsonLine{text: indent + prefix + "{}" + suffix, path: path})
+		}
+
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		lines = append(lines, jsonLine{text: indent + prefix + "{", path: path})
+		for i, k := range keys {
+			comma := ","
+			if i == len(keys)-1 {
+				comma = ""
+			}
+			key, _ := json.Marshal(k)
+			lines = appendJSONLines(lines, indent+"  ", string(key)+": ", value[k], childPath(path, k), comma)
+		}
+
+		return append(lines, jsonLine{text: indent + "}" + suffix, path: path})
+	case []interface{}:
+		if len(value) == 0 {
+			return append(lines, jsonLine{text: indent + prefix + "[]" + suffix, path: path})
+		}
+
+		lines = append(lines, jsonLine{text: indent + prefix + "[", path: path})
+		for i, item := range value {
+			comma := ","
+			if i == len(value)-1 {
+				comma = ""
+			}
+			lines = appendJSONLines(lines, indent+"  ", "", item, fmt.Sprintf("%s[%d]", path, i), comma)
+		}
+
+		return append(lines, jsonLine{text: indent + "]" + suffix, path: path})
+	default:
+		leaf, _ := json.Marshal(value)
+
+		return append(lines, jsonLine{text: indent + prefix + string(leaf) + suffix, path: path})
+	}
+}
+
+// diffOp is a single line of a diff: ' ' (unchanged), '-' (expected only) or '+' (actual only)
+type diffOp struct {
+	kind byte
+	line jsonLine
+}
+
+// diffLines computes a line based diff using the longest common subsequence
+func diffLines(a []jsonLine, b []jsonLine) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i].text == b[j].text {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i].text == b[j].text:
+			ops = append(ops, diffOp{kind: ' ', line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', line: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: '-', line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{kind: '+', line: b[j]})
+	}
+
+	return ops
+}
+
+// diffHunk is a contiguous region of a diff, labelled with the JSON path of its first change
+type diffHunk struct {
+	path string
+	ops  []diffOp
+}
+
+// diffHunks groups the changes of a diff into hunks, with surrounding context lines
+func diffHunks(ops []diffOp) []diffHunk {
+	var hunks []diffHunk
+	start, end := -1, -1
+
+	flush := func() {
+		if start < 0 {
+			return
+		}
+		h := diffHunk{ops: ops[start:end]}
+		for _, op := range h.ops {
+			if op.kind != ' ' {
+				h.path = op.line.path
+				break
+			}
+		}
+		hunks = append(hunks, h)
+		start, end = -1, -1
+	}
+
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		from := max(0, i-diffContextLines)
+		to := min(len(ops), i+diffContextLines+1)
+
+		if start >= 0 && from > end {
+			flush()
+		}
+		if start < 0 {
+			start = from
+		}
+		end = max(end, to)
+	}
+	flush()
+
+	return hunks
+}
@@ -0,0 +1,124 @@
+package consumer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMismatches(t *testing.T) {
+	get := MismatchedRequest{
+		Type: MismatchTypeRequestMismatch,
+		Mismatches: []MismatchDetail{
+			{Type: MismatchTypeHeader, Key: "Authorization", Expected: "Bearer 1234", Actual: "", Mismatch: "Expected header 'Authorization' but was missing"},
+			{
+				Type:     MismatchTypeBody,
+				Path:     "$",
+				Expected: `{"name":"Billy","tags":["a","b"],"id":1}`,
+				Actual:   `{"name":"Bob","tags":["a","b"],"id":1}`,
+				Mismatch: "Expected 'Billy' to be equal to 'Bob'",
+			},
+		},
+	}
+	get.Method = "GET"
+	get.Path = "/users"
+
+	missing := MismatchedRequest{Type: MismatchTypeMissingRequest}
+	missing.Method = "POST"
+	missing.Path = "/users"
+
+	sameRequest := MismatchedRequest{
+		Type: MismatchTypeRequestMismatch,
+		Mismatches: []MismatchDetail{
+			{Type: MismatchTypeQuery, Parameter: "page", Expected: "1", Actual: "2"},
+		},
+	}
+	sameRequest.Method = "GET"
+	sameRequest.Path = "/users"
+
+	t.Run("without colour", func(t *testing.T) {
+		out := renderMismatches([]MismatchedRequest{get, missing, sameRequest}, nil, false)
+
+		assert.NotContains(t, out, "\x1b[")
+		assert.Equal(t, 1, strings.Count(out, "GET /users"), "mismatches for the same request should be grouped")
+		assert.Contains(t, out, "POST /users\n  expected request was not received")
+		assert.Contains(t, out, "- expected: Bearer 1234")
+		assert.Contains(t, out, "--- expected")
+		assert.Contains(t, out, "+++ actual")
+		assert.Contains(t, out, "@@ $.name @@")
+		assert.Contains(t, out, `-   "name": "Billy",  $.name`)
+		assert.Contains(t, out, `+   "name": "Bob",  $.name`)
+		assert.Contains(t, out, `query parameter "page"`)
+	})
+
+	t.Run("with colour", func(t *testing.T) {
+		out := renderMismatches([]MismatchedRequest{get}, nil, true)
+
+		assert.Contains(t, out, ansiRed+`-   "name": "Billy",`+ansiReset)
+		assert.Contains(t, out, ansiGreen+`+   "name": "Bob",`+ansiReset)
+	})
+
+	t.Run("grouped by interaction", func(t *testing.T) {
+		interactions := []native.InteractionSummary{
+			{Description: "a request for users", ProviderStates: []string{"users exist"}, Method: "GET", Path: "/users"},
+			{Description: "a request for no users", ProviderStates: []string{"no users exist"}, Method: "GET", Path: "/users"},
+			{Description: "a request to create a user", Method: "POST", Path: `{"pact:matcher:type":"regex","regex":"^/users$","value":"/users"}`},
+		}
+		missingGet := MismatchedRequest{Type: MismatchTypeMissingRequest}
+		missingGet.Method = "GET"
+		missingGet.Path = "/users"
+
+		out := renderMismatches([]MismatchedRequest{missingGet, missingGet, missing}, interactions, false)
+
+		assert.Contains(t, out, `GET /users: "a request for users" given "users exist"`+"\n  expected request was not received")
+		assert.Contains(t, out, `GET /users: "a request for no users" given "no users exist"`+"\n  expected request was not received")
+		assert.Contains(t, out, `POST /users: "a request to create a user"`)
+
+		out = renderMismatches([]MismatchedRequest{get}, interactions, false)
+		assert.Contains(t, out, `GET /users: "a request for users" given "users exist" or "a request for no users" given "no users exist"`)
+	})
+
+	t.Run("NO_COLOR disables colour", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		err := &MismatchError{Mismatches: []MismatchedRequest{get}}
+
+		assert.NotContains(t, err.Diff(), "\x1b[")
+	})
+}
+
+func TestDiffBodies(t *testing.T) {
+	style := diffStyle{}
+
+	t.Run("nested array paths", func(t *testing.T) {
+		out := diffBodies("$.items", `[{"id":1},{"id":2}]`, `[{"id":1},{"id":3}]`, style)
+
+		assert.Contains(t, out, "@@ $.items[1].id @@")
+		assert.Contains(t, out, `-     "id": 2  $.items[1].id`)
+		assert.Contains(t, out, `+     "id": 3  $.items[1].id`)
+	})
+
+	t.Run("keys that need quoting", func(t *testing.T) {
+		out := diffBodies("", `{"first-name":"a"}`, `{"first-name":"b"}`, style)
+
+		assert.Contains(t, out, "@@ $['first-name'] @@")
+	})
+
+	t.Run("plain text bodies", func(t *testing.T) {
+		out := diffBodies("$", "hello\nworld", "hello\nthere", style)
+
+		assert.Contains(t, out, "  hello")
+		assert.Contains(t, out, "- world  $")
+		assert.Contains(t, out, "+ there  $")
+	})
+
+	t.Run("distant changes produce separate hunks", func(t *testing.T) {
+		expected := `{"a":1,"b":2,"c":3,"d":4,"e":5,"f":6,"g":7,"h":8,"i":9,"j":10}`
+		actual := `{"a":0,"b":2,"c":3,"d":4,"e":5,"f":6,"g":7,"h":8,"i":9,"j":0}`
+		out := diffBodies("$", expected, actual, style)
+
+		assert.Contains(t, out, "@@ $.a @@")
+		assert.Contains(t, out, "@@ $.j @@")
+	})
+}
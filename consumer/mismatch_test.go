@@ -0,0 +1,64 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMismatchError(t *testing.T) {
+	mismatchErr := &MismatchError{
+		TestName: "TestSomething",
+		Mismatches: []MismatchedRequest{
+			{
+				Type: MismatchTypeRequestMismatch,
+				Mismatches: []MismatchDetail{
+					{Type: MismatchTypeHeader, Key: "Authorization", Mismatch: "Expected header 'Authorization' but was missing"},
+					{Type: MismatchTypeQuery, Parameter: "page", Expected: "1", Actual: "2"},
+					{Type: MismatchTypeBody, Path: "$.name", Mismatch: "Expected 'Billy' to be equal to 'Bob'"},
+				},
+			},
+			{
+				Type: MismatchTypeMissingRequest,
+			},
+		},
+	}
+	mismatchErr.Mismatches[0].Method = "GET"
+	mismatchErr.Mismatches[0].Path = "/users"
+	mismatchErr.Mismatches[1].Method = "POST"
+	mismatchErr.Mismatches[1].Path = "/users"
+
+	t.Run("can be inspected with errors.As", func(t *testing.T) {
+		var err error = fmt.Errorf("wrapped: %w", mismatchErr)
+
+		var target *MismatchError
+		assert.True(t, errors.As(err, &target))
+		assert.Len(t, target.Mismatches, 2)
+	})
+
+	t.Run("Details filters by type", func(t *testing.T) {
+		assert.Len(t, mismatchErr.Details(), 3)
+		assert.Len(t, mismatchErr.Details(MismatchTypeHeader), 1)
+		assert.Len(t, mismatchErr.Details(MismatchTypeHeader, MismatchTypeQuery), 2)
+		assert.Empty(t, mismatchErr.Details(MismatchTypeStatus))
+	})
+
+	t.Run("Requests filters by type", func(t *testing.T) {
+		assert.Len(t, mismatchErr.Requests(), 2)
+		assert.Len(t, mismatchErr.Requests(MismatchTypeMissingRequest), 1)
+		assert.Empty(t, mismatchErr.Requests(MismatchTypeRequestNotFound))
+	})
+
+	t.Run("Error describes every mismatch", func(t *testing.T) {
+		msg := mismatchErr.Error()
+
+		assert.Contains(t, msg, "TestSomething")
+		assert.Contains(t, msg, "mismatch: GET /users")
+		assert.Contains(t, msg, `header "Authorization": Expected header 'Authorization' but was missing`)
+		assert.Contains(t, msg, `query parameter "page": expected "1" but received "2"`)
+		assert.Contains(t, msg, "body $.name: Expected 'Billy' to be equal to 'Bob'")
+		assert.Contains(t, msg, "expected: POST /users (Expected request that was not received)")
+	})
+}
@@ -0,0 +1,104 @@
+package consumer
+
+import (
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/pact-foundation/pact-go/v2/openapi"
+)
+
+// OpenAPIOperation is an operation of an OpenAPI document, described as the request
+// and response builders of a V4 interaction. Matchers are derived from the types,
+// formats and patterns of the documented schemas, and only required fields are included.
+//
+// Use Interaction to configure an interaction with it, or use its builders directly
+// to refine the expectations, e.g.
+//
+//	op, err := consumer.FromOpenAPI("openapi.yaml", "getUser")
+//
+//	mockProvider.
+//		AddInteraction().
+//		Given("User billy exists").
+//		UponReceiving(op.Description).
+//		WithRequestPathMatcher(op.Method, op.Path, op.Request).
+//		WillRespondWith(op.Status, op.Response)
+type OpenAPIOperation struct {
+	OperationID string
+	Description string
+	Method      Method
+
+	// Path matches the path of the operation, including any path parameters
+	Path matchers.Matcher
+
+	// Status is the status code of the documented response
+	Status int
+
+	// Request adds the required query parameters, headers and JSON body of the request
+	Request V4RequestBuilderFunc
+
+	// Response adds the required headers and JSON body of the response
+	Response V4ResponseBuilderFunc
+}
+
+// FromOpenAPI reads the operation with the given ID from the OpenAPI document at the path spec,
+// using its first successful (2xx) response
+func FromOpenAPI(spec string, operationID string) (*OpenAPIOperation, error) {
+	return FromOpenAPIResponse(spec, operationID, 0)
+}
+
+// FromOpenAPIResponse reads the operation with the given ID from the OpenAPI document at the
+// path spec, using the response documented for the status code
+func FromOpenAPIResponse(spec string, operationID string, status int) (*OpenAPIOperation, error) {
+	doc, err := openapi.Load(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := doc.Interaction(operationID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return newOpenAPIOperation(i), nil
+}
+
+func newOpenAPIOperation(i *openapi.Interaction) *OpenAPIOperation {
+	path := i.PathMatcher
+	if path == nil {
+		path = matchers.String(i.Path)
+	}
+
+	return &OpenAPIOperation{
+		OperationID: i.OperationID,
+		Description: i.Description,
+		Method:      Method(i.Method),
+		Path:        path,
+		Status:      i.Status,
+		Request: func(b *V4RequestBuilder) {
+			for name, values := range i.Query {
+				b.Query(name, values...)
+			}
+			if len(i.RequestHeaders) > 0 {
+				b.Headers(i.RequestHeaders)
+			}
+			if i.RequestBody != nil {
+				b.JSONBody(i.RequestBody)
+			}
+		},
+		Response: func(b *V4ResponseBuilder) {
+			if len(i.ResponseHeaders) > 0 {
+				b.Headers(i.ResponseHeaders)
+			}
+			if i.ResponseBody != nil {
+				b.JSONBody(i.ResponseBody)
+			}
+		},
+	}
+}
+
+// Interaction configures the request and response of the interaction with the operation,
+// using the description of the operation
+func (o *OpenAPIOperation) Interaction(i *V4UnconfiguredInteraction) *V4InteractionWithResponse {
+	return i.
+		UponReceiving(o.Description).
+		WithRequestPathMatcher(o.Method, o.Path, o.Request).
+		WillRespondWith(o.Status, o.Response)
+}
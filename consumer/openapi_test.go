@@ -0,0 +1,78 @@
+package consumer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: users
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: a user
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+                    example: 10
+        "404":
+          description: not found
+  /health:
+    get:
+      operationId: health
+      summary: a health check
+      responses:
+        "204":
+          description: healthy
+`
+
+func TestFromOpenAPI(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "openapi.yaml")
+	assert.NoError(t, os.WriteFile(spec, []byte(testOpenAPISpec), 0600))
+
+	op, err := FromOpenAPI(spec, "getUser")
+	assert.NoError(t, err)
+	assert.Equal(t, "a GET request to /users/{id}", op.Description)
+	assert.Equal(t, Method("GET"), op.Method)
+	assert.Equal(t, matchers.Regex("/users/1", `^/users/\d+$`), op.Path)
+	assert.Equal(t, 200, op.Status)
+	assert.NotNil(t, op.Request)
+	assert.NotNil(t, op.Response)
+
+	op, err = FromOpenAPIResponse(spec, "getUser", 404)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, op.Status)
+
+	op, err = FromOpenAPI(spec, "health")
+	assert.NoError(t, err)
+	assert.Equal(t, "a health check", op.Description)
+	assert.Equal(t, matchers.String("/health"), op.Path)
+	assert.Equal(t, 204, op.Status)
+
+	_, err = FromOpenAPI(spec, "unknown")
+	assert.Error(t, err)
+
+	_, err = FromOpenAPI(filepath.Join(t.TempDir(), "missing.yaml"), "getUser")
+	assert.Error(t, err)
+}
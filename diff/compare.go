@@ -0,0 +1,325 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// compareValues compares named values, i.e. headers, query parameters or metadata.
+// Values that requests now require, or that responses no longer have, are reported,
+// as are the rules of values in both that now allow fewer values.
+func compareValues(prefix string, request bool, ignoreCase bool, before []string, after []string, beforeRules map[string]*models.MatchingRule, afterRules map[string]*models.MatchingRule) []Change {
+	normalise := func(name string) string {
+		if ignoreCase {
+			return strings.ToLower(name)
+		}
+		return name
+	}
+	names := func(values []string) map[string]string {
+		m := make(map[string]string, len(values))
+		for _, v := range values {
+			m[normalise(v)] = v
+		}
+		return m
+	}
+	rule := func(rules map[string]*models.MatchingRule, name string) *models.MatchingRule {
+		for k, r := range rules {
+			if normalise(k) == normalise(name) {
+				return r
+			}
+		}
+		return nil
+	}
+
+	a, b := names(before), names(after)
+	var changes []Change
+
+	for _, k := range sortedKeys(b) {
+		name := b[k]
+		if _, ok := a[k]; !ok {
+			if request {
+				changes = append(changes, Change{Kind: RequestFieldRequired, Location: prefix + "." + name, Message: "the value is now required"})
+			}
+			continue
+		}
+		changes = append(changes, compareRule(prefix+"."+name, rule(beforeRules, a[k]), rule(afterRules, name))...)
+	}
+
+	if !request {
+		for _, k := range sortedKeys(a) {
+			if _, ok := b[k]; !ok {
+				changes = append(changes, Change{Kind: ResponseFieldRemoved, Location: prefix + "." + a[k], Message: "the value was removed"})
+			}
+		}
+	}
+
+	return changes
+}
+
+// compareBody compares the fields of two bodies. Fields are compared by their path,
+// with array indexes replaced by [*], so that arrays of any length can be compared.
+func compareBody(prefix string, request bool, before *models.Body, after *models.Body, beforeRules models.MatchingRules, afterRules models.MatchingRules) []Change {
+	a, b := fields(content(before)), fields(content(after))
+	var changes []Change
+
+	// Changes to a field are not reported again for the fields it contains
+	reported := make(map[string]bool)
+	isReported := func(path string) bool {
+		for p := range reported {
+			if len(path) > len(p) && strings.HasPrefix(path, p) && (path[len(p)] == '.' || path[len(p)] == '[') {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, path := range sortedKeys(b) {
+		if isReported(path) {
+			continue
+		}
+		location := prefix + "." + path
+
+		beforeSegments, ok := a[path]
+		if !ok {
+			if request {
+				changes = append(changes, Change{Kind: RequestFieldRequired, Location: location, Message: fieldMessage(path, "is now required")})
+				reported[path] = true
+			}
+			continue
+		}
+
+		c := compareRule(location, beforeRules.Applying(models.CategoryBody, matchingrules.FormatPath(beforeSegments)), afterRules.Applying(models.CategoryBody, matchingrules.FormatPath(b[path])))
+		if len(c) > 0 {
+			changes = append(changes, c...)
+			reported[path] = true
+		}
+	}
+
+	if !request {
+		for _, path := range sortedKeys(a) {
+			if _, ok := b[path]; ok || isReported(path) {
+				continue
+			}
+			changes = append(changes, Change{Kind: ResponseFieldRemoved, Location: prefix + "." + path, Message: fieldMessage(path, "was removed")})
+			reported[path] = true
+		}
+	}
+
+	return changes
+}
+
+func fieldMessage(path string, message string) string {
+	if path == "$" {
+		return "the body " + message
+	}
+
+	return "the field " + message
+}
+
+// content returns the document of a body. JSON embedded in a string is decoded.
+func content(b *models.Body) interface{} {
+	if b == nil {
+		return nil
+	}
+
+	if s, ok := b.Content.(string); ok && b.Encoded == "json" {
+		var doc interface{}
+		d := json.NewDecoder(strings.NewReader(s))
+		d.UseNumber()
+		if err := d.Decode(&doc); err == nil {
+			return doc
+		}
+	}
+
+	return b.Content
+}
+
+// fields returns the paths of the values of a document, with array indexes replaced
+// by [*] (e.g. $.items[*].id), and the segments of the first value at each path
+func fields(doc interface{}) map[string][]string {
+	result := make(map[string][]string)
+	if doc == nil {
+		return result
+	}
+
+	var walk func(node interface{}, general []string, segments []string)
+	walk = func(node interface{}, general []string, segments []string) {
+		path := matchingrules.FormatPath(general)
+		if _, ok := result[path]; ok && len(general) > 0 {
+			return
+		}
+		result[path] = segments
+
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for k, v := range n {
+				walk(v, append(append([]string{}, general...), k), append(append([]string{}, segments...), k))
+			}
+		case []interface{}:
+			for i, v := range n {
+				walk(v, append(append([]string{}, general...), matchingrules.AnyIndex), append(append([]string{}, segments...), matchingrules.Index(i)))
+			}
+		}
+	}
+	walk(doc, []string{}, []string{})
+
+	return result
+}
+
+// compareRule reports if the rule of a value now allows fewer values. A nil rule
+// matches the value by equality.
+func compareRule(location string, before *models.MatchingRule, after *models.MatchingRule) []Change {
+	if !tightened(before, after) {
+		return nil
+	}
+
+	return []Change{{
+		Kind:     MatcherTightened,
+		Location: location,
+		Message:  fmt.Sprintf("the value was matched by %s, but is now matched by %s", describe(before), describe(after)),
+	}}
+}
+
+func tightened(before *models.MatchingRule, after *models.MatchingRule) bool {
+	switch {
+	case before == nil || len(before.Matchers) == 0:
+		// Nothing is tighter than equality
+		return false
+	case after == nil || len(after.Matchers) == 0:
+		return true
+	case describe(before) == describe(after):
+		return false
+	case len(before.Matchers) == 1 && len(after.Matchers) == 1:
+		return tightenedMatcher(before.Matchers[0], after.Matchers[0])
+	case after.Combine == "OR":
+		// Fewer alternatives are tighter
+		return !contains(after.Matchers, before.Matchers)
+	default:
+		// More matchers that must all match are tighter
+		return before.Combine == "OR" || !contains(before.Matchers, after.Matchers)
+	}
+}
+
+// tightenedMatcher reports whether the after matcher allows fewer values than the
+// before matcher. Matchers that cannot be compared (e.g. two regular expressions)
+// are assumed to be tighter.
+func tightenedMatcher(before map[string]interface{}, after map[string]interface{}) bool {
+	a, b := matchType(before), matchType(after)
+
+	switch {
+	case b == "type" && a == "type":
+		minA, _ := number(before["min"])
+		minB, _ := number(after["min"])
+		maxA, okA := number(before["max"])
+		maxB, okB := number(after["max"])
+		return minB > minA || (okB && (!okA || maxB < maxA))
+	case b == "type":
+		return false
+	case a == "type":
+		return true
+	case a == "number" && (b == "integer" || b == "decimal"):
+		return true
+	case b == "number" && (a == "integer" || a == "decimal"):
+		return false
+	}
+
+	return describeMatcher(before) != describeMatcher(after)
+}
+
+// matchType returns the type of a matcher. V2 matchers may only give a regex.
+func matchType(m map[string]interface{}) string {
+	if t, ok := m["match"].(string); ok {
+		return t
+	}
+	if _, ok := m["regex"]; ok {
+		return "regex"
+	}
+
+	return "type"
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+func contains(list []map[string]interface{}, items []map[string]interface{}) bool {
+	for _, item := range items {
+		found := false
+		for _, m := range list {
+			if describeMatcher(m) == describeMatcher(item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describe describes a rule, e.g. "type (min 1)" or "regex '^\d+$' OR null"
+func describe(rule *models.MatchingRule) string {
+	if rule == nil || len(rule.Matchers) == 0 {
+		return "equality to the example"
+	}
+
+	descriptions := make([]string, len(rule.Matchers))
+	for i, m := range rule.Matchers {
+		descriptions[i] = describeMatcher(m)
+	}
+	combine := rule.Combine
+	if combine == "" {
+		combine = "AND"
+	}
+
+	return strings.Join(descriptions, " "+combine+" ")
+}
+
+func describeMatcher(m map[string]interface{}) string {
+	t := matchType(m)
+	var options []string
+	for _, k := range sortedKeys(m) {
+		if k == "match" {
+			continue
+		}
+		switch v := m[k].(type) {
+		case string:
+			options = append(options, fmt.Sprintf("%s '%s'", k, v))
+		default:
+			data, _ := json.Marshal(v)
+			options = append(options, fmt.Sprintf("%s %s", k, data))
+		}
+	}
+	if len(options) == 0 {
+		return t
+	}
+
+	return fmt.Sprintf("%s (%s)", t, strings.Join(options, ", "))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,251 @@
+// Package diff compares two versions of a pact, and reports the changes to the
+// contract that they describe.
+//
+// Interactions are matched by their type, description and provider state(s). The
+// changes to matched interactions are classified from the point of view of anyone
+// relying on the contract as it was: a field that requests now require, a matcher
+// that now allows fewer values, a field that responses no longer have, or a
+// different status code could break them, and so are breaking changes. Added and
+// removed interactions are reported, but are not breaking.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// Kind is the kind of a change to a pact
+type Kind string
+
+const (
+	// InteractionAdded is an interaction that the previous pact did not have
+	InteractionAdded Kind = "interaction-added"
+
+	// InteractionRemoved is an interaction that the current pact no longer has
+	InteractionRemoved Kind = "interaction-removed"
+
+	// RequestFieldRequired is a body field, header, query parameter or metadata entry
+	// that requests (or request messages) now require
+	RequestFieldRequired Kind = "request-field-required"
+
+	// MatcherTightened is a value whose matching rule now allows fewer values
+	MatcherTightened Kind = "matcher-tightened"
+
+	// ResponseFieldRemoved is a body field, header or metadata entry that responses
+	// (or messages from the provider) no longer have
+	ResponseFieldRemoved Kind = "response-field-removed"
+
+	// StatusChanged is a response status code that has changed
+	StatusChanged Kind = "status-changed"
+)
+
+// Breaking reports whether changes of the kind could break anyone relying on the
+// previous version of the pact
+func (k Kind) Breaking() bool {
+	switch k {
+	case RequestFieldRequired, MatcherTightened, ResponseFieldRemoved, StatusChanged:
+		return true
+	}
+
+	return false
+}
+
+// Change is a change to an interaction of a pact
+type Change struct {
+	Kind     Kind `json:"kind"`
+	Breaking bool `json:"breaking"`
+
+	// Interaction is the description of the interaction
+	Interaction    string   `json:"interaction"`
+	ProviderStates []string `json:"providerStates,omitempty"`
+
+	// Location is the part of the interaction that changed, e.g. response.status,
+	// request.query.page, response.header.Location or response.body.$.user.name
+	Location string `json:"location,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (c Change) String() string {
+	severity := "INFO"
+	if c.Breaking {
+		severity = "BREAKING"
+	}
+
+	interaction := c.Interaction
+	if len(c.ProviderStates) > 0 {
+		interaction = fmt.Sprintf("%s (given %s)", interaction, strings.Join(c.ProviderStates, ", "))
+	}
+	if c.Location == "" {
+		return fmt.Sprintf("%s %s: %s", severity, interaction, c.Message)
+	}
+
+	return fmt.Sprintf("%s %s: %s: %s", severity, interaction, c.Location, c.Message)
+}
+
+// Report is the result of comparing two versions of a pact
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns the breaking changes
+func (r *Report) Breaking() []Change {
+	breaking := make([]Change, 0)
+	for _, c := range r.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+
+	return breaking
+}
+
+// CompareFiles compares the pact files at the paths previous and current
+func CompareFiles(previous string, current string) (*Report, error) {
+	a, err := models.Load(previous)
+	if err != nil {
+		return nil, err
+	}
+	b, err := models.Load(current)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compare(a, b), nil
+}
+
+// Compare compares two versions of a pact, which may be of different specification
+// versions. Changes are reported in the order of the interactions of the current
+// pact, followed by the interactions that were removed.
+func Compare(previous *models.PactFile, current *models.PactFile) *Report {
+	report := &Report{Changes: make([]Change, 0)}
+
+	unmatched := make(map[string][]models.Interaction)
+	for _, i := range previous.Interactions {
+		k := key(i)
+		unmatched[k] = append(unmatched[k], i)
+	}
+
+	for _, i := range current.Interactions {
+		k := key(i)
+		if len(unmatched[k]) == 0 {
+			report.add(i, Change{Kind: InteractionAdded, Message: "the interaction was added"})
+			continue
+		}
+		before := unmatched[k][0]
+		unmatched[k] = unmatched[k][1:]
+
+		for _, c := range compareInteraction(before, i) {
+			report.add(i, c)
+		}
+	}
+
+	for _, i := range previous.Interactions {
+		k := key(i)
+		if len(unmatched[k]) > 0 && unmatched[k][0] == i {
+			unmatched[k] = unmatched[k][1:]
+			report.add(i, Change{Kind: InteractionRemoved, Message: "the interaction was removed"})
+		}
+	}
+
+	return report
+}
+
+func (r *Report) add(i models.Interaction, c Change) {
+	c.Breaking = c.Kind.Breaking()
+	c.Interaction = i.Details().Description
+	c.ProviderStates = states(i)
+	r.Changes = append(r.Changes, c)
+}
+
+// key identifies an interaction by its type, description and provider state(s)
+func key(i models.Interaction) string {
+	return strings.Join(append([]string{i.Type(), i.Details().Description}, states(i)...), "\x00")
+}
+
+func states(i models.Interaction) []string {
+	var names []string
+	for _, s := range i.Details().ProviderStates {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
+
+func compareInteraction(before models.Interaction, after models.Interaction) []Change {
+	switch a := after.(type) {
+	case *models.HTTPInteraction:
+		b := before.(*models.HTTPInteraction)
+		return compareHTTP(b, a)
+	case *models.AsynchronousMessage:
+		b := before.(*models.AsynchronousMessage)
+		return compareMessage("message", false, b.Message, a.Message)
+	case *models.SynchronousMessage:
+		b := before.(*models.SynchronousMessage)
+		changes := compareMessage("request", true, b.Request, a.Request)
+		for n := range b.Response {
+			prefix := fmt.Sprintf("response[%d]", n)
+			if n >= len(a.Response) {
+				changes = append(changes, Change{Kind: ResponseFieldRemoved, Location: prefix, Message: "the response message was removed"})
+				continue
+			}
+			changes = append(changes, compareMessage(prefix, false, b.Response[n], a.Response[n])...)
+		}
+		return changes
+	}
+
+	// The content of interactions of plugins is not known
+	return nil
+}
+
+func compareHTTP(before *models.HTTPInteraction, after *models.HTTPInteraction) []Change {
+	var changes []Change
+
+	// Request
+	changes = append(changes, compareRule("request.path", before.Request.MatchingRules[models.CategoryPath][""], after.Request.MatchingRules[models.CategoryPath][""])...)
+	changes = append(changes, compareValues("request.query", true, false, keysOf(before.Request.Query), keysOf(after.Request.Query),
+		before.Request.MatchingRules[models.CategoryQuery], after.Request.MatchingRules[models.CategoryQuery])...)
+	changes = append(changes, compareValues("request.header", true, true, keysOf(before.Request.Headers), keysOf(after.Request.Headers),
+		before.Request.MatchingRules[models.CategoryHeader], after.Request.MatchingRules[models.CategoryHeader])...)
+	changes = append(changes, compareBody("request.body", true, before.Request.Body, after.Request.Body,
+		before.Request.MatchingRules, after.Request.MatchingRules)...)
+
+	// Response
+	if before.Response.Status != after.Response.Status {
+		changes = append(changes, Change{
+			Kind:     StatusChanged,
+			Location: "response.status",
+			Message:  fmt.Sprintf("the status changed from %d to %d", before.Response.Status, after.Response.Status),
+		})
+	}
+	changes = append(changes, compareValues("response.header", false, true, keysOf(before.Response.Headers), keysOf(after.Response.Headers),
+		before.Response.MatchingRules[models.CategoryHeader], after.Response.MatchingRules[models.CategoryHeader])...)
+	changes = append(changes, compareBody("response.body", false, before.Response.Body, after.Response.Body,
+		before.Response.MatchingRules, after.Response.MatchingRules)...)
+
+	return changes
+}
+
+// compareMessage compares the contents and metadata of a message. Request messages
+// are sent by the consumer, and other messages by the provider.
+func compareMessage(prefix string, request bool, before models.Message, after models.Message) []Change {
+	var changes []Change
+
+	changes = append(changes, compareValues(prefix+".metadata", request, false, keysOf(before.Metadata), keysOf(after.Metadata),
+		before.MatchingRules[models.CategoryMetadata], after.MatchingRules[models.CategoryMetadata])...)
+	changes = append(changes, compareBody(prefix+".contents", request, before.Contents, after.Contents,
+		before.MatchingRules, after.MatchingRules)...)
+
+	return changes
+}
+
+func keysOf[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
@@ -0,0 +1,205 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// v3Pact returns a V3 pact with a single interaction
+func v3Pact(t *testing.T, request string, response string) *models.PactFile {
+	p, err := models.ParsePactFile([]byte(fmt.Sprintf(`{
+		"consumer": {"name": "web"},
+		"provider": {"name": "users"},
+		"interactions": [{"description": "a request for a user", "providerStates": [{"name": "a user"}], "request": %s, "response": %s}],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`, request, response)))
+	assert.NoError(t, err)
+
+	return p
+}
+
+func TestCompare(t *testing.T) {
+	const request = `{"method": "GET", "path": "/users/1"}`
+	const response = `{"status": 200, "body": {"id": 1}}`
+
+	tests := []struct {
+		name     string
+		before   [2]string
+		after    [2]string
+		want     []Change
+		breaking int
+	}{
+		{
+			name:   "no changes",
+			before: [2]string{request, response},
+			after:  [2]string{request, response},
+		},
+		{
+			name:   "status changed",
+			before: [2]string{request, response},
+			after:  [2]string{request, `{"status": 201, "body": {"id": 1}}`},
+			want: []Change{
+				{Kind: StatusChanged, Location: "response.status", Message: "the status changed from 200 to 201"},
+			},
+		},
+		{
+			name:   "request fields required",
+			before: [2]string{`{"method": "POST", "path": "/users", "headers": {"Accept": "application/json"}, "body": {"name": "billy"}}`, response},
+			after: [2]string{
+				`{"method": "POST", "path": "/users", "query": {"dry-run": ["true"]}, "headers": {"accept": "application/json", "X-Request-Id": "1"},
+				  "body": {"name": "billy", "address": {"street": "Main St", "city": "Melbourne"}, "tags": []}}`,
+				response,
+			},
+			want: []Change{
+				{Kind: RequestFieldRequired, Location: "request.query.dry-run", Message: "the value is now required"},
+				{Kind: RequestFieldRequired, Location: "request.header.X-Request-Id", Message: "the value is now required"},
+				{Kind: RequestFieldRequired, Location: "request.body.$.address", Message: "the field is now required"},
+				{Kind: RequestFieldRequired, Location: "request.body.$.tags", Message: "the field is now required"},
+			},
+		},
+		{
+			name:   "response fields removed",
+			before: [2]string{request, `{"status": 200, "headers": {"ETag": "1"}, "body": {"id": 1, "address": {"city": "Melbourne"}, "roles": [{"name": "admin"}]}}`},
+			after:  [2]string{request, `{"status": 200, "body": {"id": 1, "roles": [{}], "extra": true}}`},
+			want: []Change{
+				{Kind: ResponseFieldRemoved, Location: "response.header.ETag", Message: "the value was removed"},
+				{Kind: ResponseFieldRemoved, Location: "response.body.$.address", Message: "the field was removed"},
+				{Kind: ResponseFieldRemoved, Location: "response.body.$.roles[*].name", Message: "the field was removed"},
+			},
+		},
+		{
+			name: "matchers tightened",
+			before: [2]string{request, `{"status": 200, "body": {"id": 1, "name": "billy", "items": [1], "email": "a@b", "age": 1, "user": {"a": 1}, "note": null}, "matchingRules": {"body": {
+				"$.id": {"matchers": [{"match": "type"}]},
+				"$.name": {"matchers": [{"match": "type"}]},
+				"$.items": {"matchers": [{"match": "type", "min": 1}]},
+				"$.email": {"matchers": [{"match": "regex", "regex": ".+"}]},
+				"$.age": {"matchers": [{"match": "number"}]},
+				"$.user": {"matchers": [{"match": "type"}]},
+				"$.note": {"combine": "OR", "matchers": [{"match": "type"}, {"match": "null"}]}
+			}}}`},
+			after: [2]string{request, `{"status": 200, "body": {"id": 1, "name": "billy", "items": [1], "email": "a@b", "age": 1, "user": {"a": 1}, "note": null}, "matchingRules": {"body": {
+				"$.id": {"matchers": [{"match": "integer"}]},
+				"$.name": {"matchers": [{"match": "type"}]},
+				"$.items": {"matchers": [{"match": "type", "min": 2}]},
+				"$.email": {"matchers": [{"match": "regex", "regex": ".+@.+"}]},
+				"$.age": {"matchers": [{"match": "integer"}]},
+				"$.note": {"combine": "OR", "matchers": [{"match": "type"}]}
+			}}}`},
+			want: []Change{
+				{Kind: MatcherTightened, Location: "response.body.$.age", Message: "the value was matched by number, but is now matched by integer"},
+				{Kind: MatcherTightened, Location: "response.body.$.email", Message: "the value was matched by regex (regex '.+'), but is now matched by regex (regex '.+@.+')"},
+				{Kind: MatcherTightened, Location: "response.body.$.id", Message: "the value was matched by type, but is now matched by integer"},
+				{Kind: MatcherTightened, Location: "response.body.$.items", Message: "the value was matched by type (min 1), but is now matched by type (min 2)"},
+				{Kind: MatcherTightened, Location: "response.body.$.note", Message: "the value was matched by type OR null, but is now matched by type"},
+				{Kind: MatcherTightened, Location: "response.body.$.user", Message: "the value was matched by type, but is now matched by equality to the example"},
+			},
+		},
+		{
+			name: "matchers loosened",
+			before: [2]string{`{"method": "GET", "path": "/users/1", "headers": {"Accept": "application/json"}, "matchingRules": {"header": {"Accept": {"matchers": [{"match": "regex", "regex": "json"}]}}}}`,
+				`{"status": 200, "body": {"id": 1, "name": "billy"}, "matchingRules": {"body": {"$.id": {"matchers": [{"match": "integer"}]}}}}`},
+			after: [2]string{`{"method": "GET", "path": "/users/1", "headers": {"Accept": "application/json"}, "matchingRules": {"header": {"Accept": {"matchers": [{"match": "type"}]}}}}`,
+				`{"status": 200, "body": {"id": 1, "name": "billy"}, "matchingRules": {"body": {"$": {"matchers": [{"match": "type"}]}}}}`},
+		},
+		{
+			name:   "request matchers tightened",
+			before: [2]string{`{"method": "GET", "path": "/users/1", "query": {"page": ["1"]}, "matchingRules": {"path": {"matchers": [{"match": "type"}]}, "query": {"page": {"matchers": [{"match": "type"}]}}}}`, response},
+			after:  [2]string{`{"method": "GET", "path": "/users/1", "query": {"page": ["1"]}, "matchingRules": {"path": {"matchers": [{"match": "regex", "regex": "/users/\\d+"}]}}}`, response},
+			want: []Change{
+				{Kind: MatcherTightened, Location: "request.path", Message: `the value was matched by type, but is now matched by regex (regex '/users/\d+')`},
+				{Kind: MatcherTightened, Location: "request.query.page", Message: "the value was matched by type, but is now matched by equality to the example"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(v3Pact(t, tt.before[0], tt.before[1]), v3Pact(t, tt.after[0], tt.after[1]))
+
+			for i := range tt.want {
+				tt.want[i].Breaking = true
+				tt.want[i].Interaction = "a request for a user"
+				tt.want[i].ProviderStates = []string{"a user"}
+			}
+			if tt.want == nil {
+				tt.want = []Change{}
+			}
+			assert.Equal(t, tt.want, report.Changes)
+			assert.Equal(t, tt.want, report.Breaking())
+		})
+	}
+}
+
+func TestCompareInteractions(t *testing.T) {
+	before, err := models.ParsePactFile([]byte(`{
+		"interactions": [
+			{"description": "a request for a user", "providerState": "a user", "request": {"method": "GET", "path": "/users/1"}, "response": {"status": 200}},
+			{"description": "a request for users", "request": {"method": "GET", "path": "/users"}, "response": {"status": 200}}
+		],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}
+	}`))
+	assert.NoError(t, err)
+
+	after, err := models.ParsePactFile([]byte(`{
+		"interactions": [
+			{"type": "Synchronous/HTTP", "description": "a request for a user", "providerStates": [{"name": "a user"}],
+			 "request": {"method": "GET", "path": "/users/1"}, "response": {"status": 200}},
+			{"type": "Synchronous/HTTP", "description": "a request for users", "providerStates": [{"name": "users"}],
+			 "request": {"method": "GET", "path": "/users"}, "response": {"status": 200}}
+		],
+		"metadata": {"pactSpecification": {"version": "4.0"}}
+	}`))
+	assert.NoError(t, err)
+
+	report := Compare(before, after)
+	assert.Equal(t, []Change{
+		{Kind: InteractionAdded, Interaction: "a request for users", ProviderStates: []string{"users"}, Message: "the interaction was added"},
+		{Kind: InteractionRemoved, Interaction: "a request for users", Message: "the interaction was removed"},
+	}, report.Changes)
+	assert.Empty(t, report.Breaking())
+}
+
+func TestCompareMessages(t *testing.T) {
+	before, err := models.ParsePactFile([]byte(`{
+		"messages": [{"description": "a user event", "contents": {"id": 1, "name": "billy"}, "metadata": {"topic": "users"}}],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`))
+	assert.NoError(t, err)
+
+	after, err := models.ParsePactFile([]byte(`{
+		"interactions": [
+			{"type": "Asynchronous/Messages", "description": "a user event", "contents": {"content": {"id": 1}}},
+			{"type": "Synchronous/Messages", "description": "a call", "request": {"contents": {"content": "AQ=="}}, "response": []}
+		],
+		"metadata": {"pactSpecification": {"version": "4.0"}}
+	}`))
+	assert.NoError(t, err)
+
+	report := Compare(before, after)
+	assert.Equal(t, []Change{
+		{Kind: ResponseFieldRemoved, Breaking: true, Interaction: "a user event", Location: "message.metadata.topic", Message: "the value was removed"},
+		{Kind: ResponseFieldRemoved, Breaking: true, Interaction: "a user event", Location: "message.contents.$.name", Message: "the field was removed"},
+		{Kind: InteractionAdded, Interaction: "a call", Message: "the interaction was added"},
+	}, report.Changes)
+}
+
+func TestCompareFiles(t *testing.T) {
+	dir := t.TempDir()
+	pact := `{"interactions": [{"description": "a request", "request": {"method": "GET", "path": "/"}, "response": {"status": %d}}]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "before.json"), []byte(fmt.Sprintf(pact, 200)), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "after.json"), []byte(fmt.Sprintf(pact, 404)), 0644))
+
+	report, err := CompareFiles(filepath.Join(dir, "before.json"), filepath.Join(dir, "after.json"))
+	assert.NoError(t, err)
+	assert.Len(t, report.Breaking(), 1)
+	assert.Equal(t, "BREAKING a request: response.status: the status changed from 200 to 404", report.Changes[0].String())
+
+	_, err = CompareFiles(filepath.Join(dir, "missing.json"), filepath.Join(dir, "after.json"))
+	assert.Error(t, err)
+}
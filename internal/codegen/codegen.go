@@ -0,0 +1,190 @@
+// Package codegen generates Go source using the consumer DSL, from bodies that
+// may contain matchers (e.g. recorded traffic, or schemas).
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+)
+
+// Expr returns a Go expression constructing v, with its matchers expressed using the
+// matchers package (which must be imported as "matchers")
+func Expr(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return "", err
+	}
+
+	return expr(node)
+}
+
+// MatcherExpr is as per Expr, but plain strings are wrapped with matchers.S, so that
+// the expression is always a matchers.Matcher (e.g. for header and query values)
+func MatcherExpr(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return "matchers.S(" + Quote(s) + ")", nil
+	}
+
+	return Expr(v)
+}
+
+// Quote returns s as a Go string literal, preferring a raw string for readability
+func Quote(s string) string {
+	if strings.ContainsAny(s, "\\\"") && !strings.ContainsAny(s, "`\r\n") && strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+
+	return strconv.Quote(s)
+}
+
+// Format formats Go source, as per gofmt
+func Format(src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated source: %w", err)
+	}
+
+	return formatted, nil
+}
+
+func expr(node interface{}) (string, error) {
+	if obj, t, ok := matchingrules.IsMatcher(node); ok {
+		return matcherExpr(obj, t)
+	}
+
+	switch n := node.(type) {
+	case nil:
+		return "nil", nil
+	case bool:
+		return strconv.FormatBool(n), nil
+	case json.Number:
+		return n.String(), nil
+	case string:
+		return Quote(n), nil
+	case []interface{}:
+		items := make([]string, len(n))
+		for i, v := range n {
+			item, err := expr(v)
+			if err != nil {
+				return "", err
+			}
+			items[i] = item + ",\n"
+		}
+		return "[]interface{}{\n" + strings.Join(items, "") + "}", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		b.WriteString("matchers.StructMatcher{\n")
+		for _, k := range keys {
+			value, err := expr(n[k])
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(k), value)
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v (%T)", node, node)
+	}
+}
+
+func matcherExpr(obj map[string]interface{}, t string) (string, error) {
+	value := obj["value"]
+	str := func(k string) string {
+		s, _ := obj[k].(string)
+		return Quote(s)
+	}
+
+	switch t {
+	case "type":
+		if generator, _ := obj["pact:generator:type"].(string); generator == "ProviderState" {
+			return fmt.Sprintf("matchers.FromProviderState(%s, %s)", str("expression"), str("value")), nil
+		}
+
+		list, isList := value.([]interface{})
+		_, hasMin := obj["min"]
+		_, hasMax := obj["max"]
+		if !isList || (!hasMin && !hasMax) {
+			content, err := expr(value)
+			if err != nil {
+				return "", err
+			}
+			return "matchers.Like(" + content + ")", nil
+		}
+
+		var element interface{}
+		if len(list) > 0 {
+			element = list[0]
+		}
+		content, err := expr(element)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case hasMin && hasMax:
+			return fmt.Sprintf("matchers.ArrayMinMaxLike(%s, %v, %v)", content, obj["min"], obj["max"]), nil
+		case hasMax:
+			return fmt.Sprintf("matchers.ArrayMaxLike(%s, %v)", content, obj["max"]), nil
+		default:
+			return fmt.Sprintf("matchers.EachLike(%s, %v)", content, obj["min"]), nil
+		}
+	case "regex":
+		return fmt.Sprintf("matchers.Regex(%s, %s)", str("value"), str("regex")), nil
+	case "integer":
+		return fmt.Sprintf("matchers.Integer(%v)", value), nil
+	case "decimal":
+		return fmt.Sprintf("matchers.Decimal(%v)", value), nil
+	case "null":
+		return "matchers.Null{}", nil
+	case "include":
+		return fmt.Sprintf("matchers.Includes(%s)", str("value")), nil
+	case "equality":
+		content, err := expr(value)
+		if err != nil {
+			return "", err
+		}
+		return "matchers.Equality(" + content + ")", nil
+	case "values":
+		content, err := expr(value)
+		if err != nil {
+			return "", err
+		}
+		return "matchers.EachKeyLike(\"key\", " + content + ")", nil
+	case "arrayContains":
+		content, err := expr(obj["variants"])
+		if err != nil {
+			return "", err
+		}
+		return "matchers.ArrayContaining(" + content + ")", nil
+	case "timestamp", "date", "time":
+		constructor := map[string]string{
+			"timestamp": "DateTimeGenerated",
+			"date":      "DateGenerated",
+			"time":      "TimeGenerated",
+		}[t]
+		return fmt.Sprintf("matchers.%s(%s, %s)", constructor, str("value"), str("format")), nil
+	default:
+		return "", fmt.Errorf("unsupported matcher type %q", t)
+	}
+}
@@ -0,0 +1,116 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{"string", "billy", `"billy"`},
+		{"number", 12.5, `12.5`},
+		{"null", nil, `nil`},
+		{"like", matchers.Like(27), `matchers.Like(27)`},
+		{"regex", matchers.Regex("/users/1", `^/users/\d+$`), "matchers.Regex(\"/users/1\", `^/users/\\d+$`)"},
+		{"integer", matchers.Integer(12), `matchers.Integer(12)`},
+		{"decimal", matchers.Decimal(1.5), `matchers.Decimal(1.5)`},
+		{"each like", matchers.EachLike("a", 2), `matchers.EachLike("a", 2)`},
+		{"min max like", matchers.ArrayMinMaxLike(1, 2, 3), `matchers.ArrayMinMaxLike(1, 2, 3)`},
+		{"null matcher", matchers.Null{}, `matchers.Null{}`},
+		{"includes", matchers.Includes("foo"), `matchers.Includes("foo")`},
+		{"datetime", matchers.DateTimeGenerated("2020-01-01T08:00:45", "yyyy-MM-dd'T'HH:mm:ss"), `matchers.DateTimeGenerated("2020-01-01T08:00:45", "yyyy-MM-dd'T'HH:mm:ss")`},
+		{"provider state", matchers.FromProviderState("${name}", "billy"), `matchers.FromProviderState("${name}", "billy")`},
+		{"object", matchers.StructMatcher{"b": matchers.Like("x"), "a": []interface{}{true}}, "matchers.StructMatcher{\n\"a\": []interface{}{\ntrue,\n},\n\"b\": matchers.Like(\"x\"),\n}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expr(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatcherExpr(t *testing.T) {
+	got, err := MatcherExpr("application/json")
+	assert.NoError(t, err)
+	assert.Equal(t, `matchers.S("application/json")`, got)
+}
+
+func TestFormat(t *testing.T) {
+	expr, err := Expr(matchers.StructMatcher{"id": matchers.Integer(1)})
+	assert.NoError(t, err)
+
+	src, err := Format([]byte("package x\n\nvar body = " + expr + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "package x\n\nvar body = matchers.StructMatcher{\n\t\"id\": matchers.Integer(1),\n}\n", string(src))
+
+	_, err = Format([]byte("package x\n\nvar ("))
+	assert.Error(t, err)
+}
+
+func TestTestFileSource(t *testing.T) {
+	file := TestFile{
+		Generator: "a test",
+		Package:   "client",
+		Consumer:  "web",
+		Provider:  "users",
+		Interactions: []Interaction{
+			{
+				Description:    "a request for a user",
+				Method:         "GET",
+				Path:           "/users/1",
+				PathMatcher:    matchers.Regex("/users/1", `^/users/\d+$`),
+				Query:          matchers.QueryMatcher{"fields": {matchers.S("name")}},
+				RequestHeaders: matchers.HeadersMatcher{"Accept": {matchers.S("application/json")}},
+				Status:         200,
+				ResponseBody: &Body{
+					ContentType: "application/json",
+					JSON:        matchers.StructMatcher{"id": matchers.Integer(1)},
+				},
+			},
+			{
+				Description: "a request to create a user",
+				Method:      "POST",
+				Path:        "/users",
+				RequestBody: &Body{
+					ContentType: "application/json",
+					JSON:        matchers.StructMatcher{"name": matchers.Like("billy")},
+				},
+				Status: 201,
+			},
+		},
+	}
+
+	src, err := file.Source()
+	assert.NoError(t, err)
+
+	for _, expected := range []string{
+		"// Code generated by a test.",
+		"package client",
+		"func TestWebUsersPact(t *testing.T) {",
+		"WithRequestPathMatcher(\"GET\", matchers.Regex(\"/users/1\", `^/users/\\d+$`), func(b *consumer.V4RequestBuilder) {",
+		`b.Header("Accept", matchers.S("application/json"))`,
+		`b.Query("fields", matchers.S("name"))`,
+		`http.NewRequest("GET", config.BaseURL()+"/users/1?fields=name", strings.NewReader(""))`,
+		`req.Header.Add("Accept", "application/json")`,
+		`WithRequest("POST", "/users", func(b *consumer.V4RequestBuilder) {`,
+		`b.JSONBody(matchers.StructMatcher{`,
+		"http.NewRequest(\"POST\", config.BaseURL()+\"/users\", strings.NewReader(`{\"name\":\"billy\"}`))",
+		`assert.Equal(t, 201, res.StatusCode)`,
+	} {
+		assert.Contains(t, string(src), expected)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "MyConsumer", Identifier("my-consumer"))
+	assert.Equal(t, "UserService2", Identifier("2 user service2"))
+}
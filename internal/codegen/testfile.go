@@ -0,0 +1,333 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/matchers"
+)
+
+var testFileTemplate = template.Must(template.New("testfile").Parse(`// Code generated by {{.Generator}}. Refine the matchers, add provider states and
+// replace the requests with calls through your API client before committing.
+
+package {{.Package}}
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/consumer"
+	{{- if .UsesMatchers}}
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	{{- end}}
+	"github.com/stretchr/testify/assert"
+)
+
+func Test{{.Name}}(t *testing.T) {
+	mockProvider, err := consumer.NewV4Pact(consumer.MockHTTPProviderConfig{
+		Consumer: {{.Consumer}},
+		Provider: {{.Provider}},
+	})
+	assert.NoError(t, err)
+{{range .Interactions}}
+	t.Run({{.Description}}, func(t *testing.T) {
+		err := mockProvider.
+			AddInteraction().
+			UponReceiving({{.Description}}).
+			{{.WithRequest}}, func(b *consumer.V4RequestBuilder) {
+				{{- range .RequestBuilder}}
+				b.{{.}}
+				{{- end}}
+			}).
+			WillRespondWith({{.Status}}, func(b *consumer.V4ResponseBuilder) {
+				{{- range .ResponseBuilder}}
+				b.{{.}}
+				{{- end}}
+			}).
+			ExecuteTest(t, func(config consumer.MockServerConfig) error {
+				req, err := http.NewRequest({{.Method}}, config.BaseURL()+{{.URL}}, strings.NewReader({{.Body}}))
+				if err != nil {
+					return err
+				}
+				{{range .Headers}}req.Header.Add({{index . 0}}, {{index . 1}})
+				{{end}}
+				res, err := config.HTTPClient().Do(req)
+				if err != nil {
+					return err
+				}
+				defer res.Body.Close()
+
+				assert.Equal(t, {{.Status}}, res.StatusCode)
+
+				return nil
+			})
+		assert.NoError(t, err)
+	})
+{{end}}}
+`))
+
+// TestFile is a Go test file, containing a consumer test for each interaction using
+// the consumer.V4HTTPMockProvider DSL
+type TestFile struct {
+	// Generator names the tool that generated the file, e.g. "pact-go record"
+	Generator string
+
+	Package  string
+	Consumer string
+	Provider string
+
+	Interactions []Interaction
+}
+
+// Interaction is an HTTP interaction described with matchers
+type Interaction struct {
+	Description string
+	Method      string
+	Path        string
+
+	// PathMatcher matches the path, or nil to match it exactly
+	PathMatcher matchers.Matcher
+
+	Query          matchers.QueryMatcher
+	RequestHeaders matchers.HeadersMatcher
+	RequestBody    *Body
+
+	Status          int
+	ResponseHeaders matchers.HeadersMatcher
+	ResponseBody    *Body
+}
+
+// Body is a request or response body
+type Body struct {
+	ContentType string
+
+	// JSON is the JSON body, which may contain matchers. If nil, Raw is used.
+	JSON interface{}
+
+	// Raw is the body as it was sent, e.g. by a recorded client. If given, the
+	// generated test sends it as is, rather than the example of JSON.
+	Raw []byte
+}
+
+// example returns the body sent by the generated test
+func (b *Body) example() (string, error) {
+	if b == nil {
+		return "", nil
+	}
+	if b.JSON == nil || len(b.Raw) > 0 {
+		return string(b.Raw), nil
+	}
+
+	result, err := matchingrules.Split(b.JSON)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(result.Example)
+
+	return string(data), err
+}
+
+type testFileData struct {
+	Generator    string
+	Package      string
+	Name         string
+	Consumer     string
+	Provider     string
+	Interactions []testInteraction
+	UsesMatchers bool
+}
+
+type testInteraction struct {
+	Description     string
+	Method          string
+	WithRequest     string
+	RequestBuilder  []string
+	Status          int
+	ResponseBuilder []string
+	URL             string
+	Body            string
+	Headers         [][2]string
+}
+
+// Source returns the formatted source of the test file
+func (f TestFile) Source() ([]byte, error) {
+	data := testFileData{
+		Generator: f.Generator,
+		Package:   f.Package,
+		Name:      Identifier(f.Consumer) + Identifier(f.Provider) + "Pact",
+		Consumer:  Quote(f.Consumer),
+		Provider:  Quote(f.Provider),
+	}
+
+	for _, i := range f.Interactions {
+		ti, err := newTestInteraction(i)
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate source for %q: %w", i.Description, err)
+		}
+		data.Interactions = append(data.Interactions, ti)
+		data.UsesMatchers = data.UsesMatchers || ti.usesMatchers()
+	}
+
+	var buf bytes.Buffer
+	if err := testFileTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return Format(buf.Bytes())
+}
+
+func newTestInteraction(i Interaction) (testInteraction, error) {
+	ti := testInteraction{
+		Description: Quote(i.Description),
+		Method:      Quote(i.Method),
+		Status:      i.Status,
+	}
+
+	if i.PathMatcher != nil {
+		path, err := Expr(i.PathMatcher)
+		if err != nil {
+			return ti, err
+		}
+		ti.WithRequest = fmt.Sprintf("WithRequestPathMatcher(%s, %s", ti.Method, path)
+	} else {
+		ti.WithRequest = fmt.Sprintf("WithRequest(%s, %s", ti.Method, Quote(i.Path))
+	}
+
+	var err error
+	if ti.RequestBuilder, err = builder(i.RequestHeaders, i.Query, i.RequestBody); err != nil {
+		return ti, err
+	}
+	if ti.ResponseBuilder, err = builder(i.ResponseHeaders, nil, i.ResponseBody); err != nil {
+		return ti, err
+	}
+
+	// The request is sent with the example values of the matchers
+	query := url.Values{}
+	for k, values := range i.Query {
+		for _, v := range values {
+			query.Add(k, fmt.Sprint(v.GetValue()))
+		}
+	}
+	ti.URL = i.Path
+	if len(query) > 0 {
+		ti.URL += "?" + query.Encode()
+	}
+	ti.URL = Quote(ti.URL)
+
+	for _, k := range sortedKeys(i.RequestHeaders) {
+		for _, v := range i.RequestHeaders[k] {
+			ti.Headers = append(ti.Headers, [2]string{Quote(k), Quote(fmt.Sprint(v.GetValue()))})
+		}
+	}
+
+	body, err := i.RequestBody.example()
+	if err != nil {
+		return ti, err
+	}
+	ti.Body = Quote(body)
+
+	return ti, nil
+}
+
+// usesMatchers reports whether the generated interaction refers to the matchers package
+func (ti testInteraction) usesMatchers() bool {
+	calls := append([]string{ti.WithRequest}, ti.RequestBuilder...)
+	calls = append(calls, ti.ResponseBuilder...)
+
+	return strings.Contains(strings.Join(calls, "\n"), "matchers.")
+}
+
+// builder returns the calls on a request or response builder that configure it
+func builder(headers matchers.HeadersMatcher, query matchers.QueryMatcher, body *Body) ([]string, error) {
+	calls := make([]string, 0)
+
+	for _, k := range sortedKeys(headers) {
+		values, err := matcherExprs(headers[k])
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, fmt.Sprintf("Header(%s, %s)", Quote(k), values))
+	}
+
+	for _, k := range sortedKeys(query) {
+		values, err := matcherExprs(query[k])
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, fmt.Sprintf("Query(%s, %s)", Quote(k), values))
+	}
+
+	switch {
+	case body == nil:
+	case body.JSON != nil:
+		content, err := Expr(body.JSON)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, "JSONBody("+content+")")
+	default:
+		calls = append(calls, fmt.Sprintf("Body(%s, []byte(%s))", Quote(body.ContentType), Quote(string(body.Raw))))
+	}
+
+	return calls, nil
+}
+
+func matcherExprs(values []matchers.Matcher) (string, error) {
+	exprs := make([]string, len(values))
+	for i, v := range values {
+		var err error
+		switch s := v.(type) {
+		case matchers.S:
+			exprs[i], err = MatcherExpr(string(s))
+		case matchers.String:
+			exprs[i], err = MatcherExpr(string(s))
+		default:
+			exprs[i], err = Expr(v)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(exprs, ", "), nil
+}
+
+// Identifier converts a name such as "my-consumer" into an exported Go identifier, e.g. MyConsumer
+func Identifier(name string) string {
+	var b strings.Builder
+	upper := true
+
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || (unicode.IsDigit(r) && b.Len() > 0):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+
+	return b.String()
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
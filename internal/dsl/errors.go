@@ -0,0 +1,62 @@
+// Package dsl contains support shared by the consumer and message DSLs.
+package dsl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InteractionError is a problem with the configuration of an interaction (e.g. a
+// matcher not supported by the specification version), detected as it was built
+type InteractionError struct {
+	// Description of the interaction
+	Description string
+
+	// Path of the field that is invalid, e.g. "response.body"
+	Path string
+
+	// Err is the underlying error
+	Err error
+}
+
+func (e *InteractionError) Error() string {
+	return fmt.Sprintf("invalid interaction %q: %s: %v", e.Description, e.Path, e.Err)
+}
+
+func (e *InteractionError) Unwrap() error {
+	return e.Err
+}
+
+// Errors accumulates the configuration errors of a single interaction, so that
+// they may be returned when the test is executed rather than panicking
+type Errors struct {
+	// Description of the interaction, which may be set after errors are added
+	Description string
+
+	fields []fieldError
+}
+
+type fieldError struct {
+	path string
+	err  error
+}
+
+// Add records an error against the given field
+func (e *Errors) Add(path string, err error) {
+	e.fields = append(e.fields, fieldError{path: path, err: err})
+}
+
+// Err returns the recorded errors as *InteractionError values joined together, or
+// nil if there are none
+func (e *Errors) Err() error {
+	errs := make([]error, len(e.fields))
+	for i, f := range e.fields {
+		errs[i] = &InteractionError{
+			Description: e.Description,
+			Path:        f.path,
+			Err:         f.err,
+		}
+	}
+
+	return errors.Join(errs...)
+}
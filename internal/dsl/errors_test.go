@@ -0,0 +1,32 @@
+package dsl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("returns nil without errors", func(t *testing.T) {
+		var e Errors
+
+		assert.NoError(t, e.Err())
+	})
+
+	t.Run("reports the description and path of each error", func(t *testing.T) {
+		var e Errors
+		e.Add("request.body", errors.New("bad matcher"))
+		e.Add("plugin", errors.New("not installed"))
+		e.Description = "a request"
+
+		err := e.Err()
+		assert.EqualError(t, err, "invalid interaction \"a request\": request.body: bad matcher\n"+
+			"invalid interaction \"a request\": plugin: not installed")
+
+		var interactionErr *InteractionError
+		assert.True(t, errors.As(err, &interactionErr))
+		assert.Equal(t, "a request", interactionErr.Description)
+		assert.Equal(t, "request.body", interactionErr.Path)
+	})
+}
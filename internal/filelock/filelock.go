@@ -0,0 +1,64 @@
+// Package filelock provides advisory, exclusive file locks that are honoured
+// across processes (e.g. the separate test binaries run by `go test ./...`).
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Lock is an exclusive lock held on a file
+type Lock struct {
+	file  *os.File
+	local *sync.Mutex
+}
+
+// locks serialises access within the current process, as not all platforms
+// guarantee that advisory locks exclude other goroutines of the same process
+var locks sync.Map
+
+// Acquire blocks until an exclusive lock on the given path is obtained. The file
+// (and its parent directory) is created if it does not exist.
+func Acquire(path string) (*Lock, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mu, _ := locks.LoadOrStore(abs, &sync.Mutex{})
+	local := mu.(*sync.Mutex)
+	local.Lock()
+
+	if err := os.MkdirAll(filepath.Dir(abs), 0755); err != nil {
+		local.Unlock()
+		return nil, err
+	}
+
+	f, err := os.OpenFile(abs, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		local.Unlock()
+		return nil, err
+	}
+
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		local.Unlock()
+		return nil, fmt.Errorf("unable to lock %s: %w", abs, err)
+	}
+
+	return &Lock{file: f, local: local}, nil
+}
+
+// Release releases the lock
+func (l *Lock) Release() error {
+	defer l.local.Unlock()
+
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,78 @@
+package filelock
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const holdDuration = 300 * time.Millisecond
+
+// TestHelperProcess holds a lock on behalf of TestAcquire, to test exclusion across processes
+func TestHelperProcess(t *testing.T) {
+	path := os.Getenv("FILELOCK_HELPER_PATH")
+	if path == "" {
+		return
+	}
+
+	lock, err := Acquire(path)
+	if err != nil {
+		os.Exit(1)
+	}
+	os.Stdout.WriteString("locked\n")
+	time.Sleep(holdDuration)
+	_ = lock.Release()
+	os.Exit(0)
+}
+
+func TestAcquire(t *testing.T) {
+	t.Run("excludes other goroutines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "goroutine.lock")
+
+		lock, err := Acquire(path)
+		assert.NoError(t, err)
+
+		acquired := make(chan struct{})
+		go func() {
+			second, err := Acquire(path)
+			assert.NoError(t, err)
+			close(acquired)
+			_ = second.Release()
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatal("lock acquired while held")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		assert.NoError(t, lock.Release())
+		<-acquired
+	})
+
+	t.Run("excludes other processes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "process.lock")
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = append(os.Environ(), "FILELOCK_HELPER_PATH="+path)
+		stdout, err := cmd.StdoutPipe()
+		assert.NoError(t, err)
+		assert.NoError(t, cmd.Start())
+
+		line, err := bufio.NewReader(stdout).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Equal(t, "locked\n", line)
+
+		start := time.Now()
+		lock, err := Acquire(path)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), holdDuration/2)
+		assert.NoError(t, lock.Release())
+		assert.NoError(t, cmd.Wait())
+	})
+}
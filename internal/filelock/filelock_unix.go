@@ -0,0 +1,29 @@
+//go:build unix
+
+package filelock
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// POSIX record locks are used, rather than flock, as they are available on every
+// Unix platform (flock is missing on e.g. Solaris and AIX). They are held per
+// process, which is why Acquire also excludes the goroutines of the process.
+func lockFile(f *os.File) error {
+	lk := unix.Flock_t{Type: unix.F_WRLCK, Whence: io.SeekStart}
+	for {
+		err := unix.FcntlFlock(f.Fd(), unix.F_SETLKW, &lk)
+		if err != unix.EINTR {
+			return err
+		}
+	}
+}
+
+func unlockFile(f *os.File) error {
+	lk := unix.Flock_t{Type: unix.F_UNLCK, Whence: io.SeekStart}
+
+	return unix.FcntlFlock(f.Fd(), unix.F_SETLK, &lk)
+}
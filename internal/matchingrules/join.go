@@ -0,0 +1,223 @@
+package matchingrules
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Join is the inverse of Split. It combines the example document with the matching
+// rules and generators that apply to it, keyed by path, into the JSON form of the
+// matchers they describe. Rules that apply to no value of the example are ignored.
+func Join(example interface{}, rules map[string]*Rule, generators map[string]map[string]interface{}) (interface{}, error) {
+	node, err := normalise(example)
+	if err != nil {
+		return nil, err
+	}
+	j := &joiner{
+		rules:      rules,
+		generators: generators,
+	}
+
+	return j.join(node, []string{})
+}
+
+type joiner struct {
+	rules      map[string]*Rule
+	generators map[string]map[string]interface{}
+}
+
+func (j *joiner) join(node interface{}, segments []string) (interface{}, error) {
+	rule := Lookup(j.rules, segments)
+	if rule != nil {
+		for _, m := range rule.Matchers {
+			if m["match"] == "arrayContains" {
+				return j.arrayContains(node, m, FormatPath(segments))
+			}
+		}
+	}
+
+	var value interface{}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		joined := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			child, err := j.join(v, append(append([]string{}, segments...), k))
+			if err != nil {
+				return nil, err
+			}
+			joined[k] = child
+		}
+		value = joined
+	case []interface{}:
+		joined := make([]interface{}, len(n))
+		for i, v := range n {
+			child, err := j.join(v, append(append([]string{}, segments...), Index(i)))
+			if err != nil {
+				return nil, err
+			}
+			joined[i] = child
+		}
+		value = joined
+	default:
+		value = node
+	}
+
+	if rule == nil || len(rule.Matchers) == 0 {
+		return value, nil
+	}
+
+	matchers := make([]interface{}, len(rule.Matchers))
+	for i, m := range rule.Matchers {
+		t, ok := m["match"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid matcher %v at %s", m, FormatPath(segments))
+		}
+		matcher := map[string]interface{}{
+			matcherTypeKey: t,
+		}
+		for k, v := range m {
+			if k != "match" {
+				matcher[k] = v
+			}
+		}
+		matchers[i] = matcher
+	}
+
+	var result map[string]interface{}
+	if len(matchers) == 1 {
+		result = matchers[0].(map[string]interface{})
+	} else {
+		result = map[string]interface{}{
+			matcherTypeKey:    matchers,
+			matcherCombineKey: rule.Combine,
+		}
+	}
+	// The include matcher gives the included string as its value
+	if _, ok := result["value"]; !ok || result[matcherTypeKey] != "include" {
+		result["value"] = value
+	}
+
+	if generator := Lookup(j.generators, segments); generator != nil {
+		for k, v := range generator {
+			if k == "type" {
+				result[generatorTypeKey] = v
+			} else {
+				result[k] = v
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// variant is a variant of an arrayContains matcher, in the pact file layout
+type variant struct {
+	Index      int                               `json:"index"`
+	Rules      map[string]*Rule                  `json:"rules"`
+	Generators map[string]map[string]interface{} `json:"generators"`
+}
+
+func (j *joiner) arrayContains(node interface{}, matcher map[string]interface{}, path string) (interface{}, error) {
+	list, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the arrayContains matcher at %s requires an array", path)
+	}
+
+	data, err := json.Marshal(matcher["variants"])
+	if err != nil {
+		return nil, err
+	}
+	var variants []variant
+	if err := json.Unmarshal(data, &variants); err != nil {
+		return nil, fmt.Errorf("invalid arrayContains variants at %s: %w", path, err)
+	}
+
+	joined := make([]interface{}, len(variants))
+	for i, v := range variants {
+		if v.Index < 0 || v.Index >= len(list) {
+			return nil, fmt.Errorf("the arrayContains variant %d at %s has no example", v.Index, path)
+		}
+		joined[i], err = Join(list[v.Index], v.Rules, v.Generators)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		matcherTypeKey: "arrayContains",
+		"variants":     joined,
+	}, nil
+}
+
+// ParseRule reads a matching rule in the V3/V4 pact file layout, e.g.
+// {"combine": "AND", "matchers": [{"match": "type"}]}
+func ParseRule(entry map[string]interface{}) (*Rule, error) {
+	r := &Rule{Combine: "AND"}
+	if combine, ok := entry["combine"].(string); ok {
+		r.Combine = combine
+	}
+
+	list, ok := entry["matchers"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing matchers")
+	}
+	for _, l := range list {
+		matcher, ok := l.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid matcher %v", l)
+		}
+		r.Matchers = append(r.Matchers, matcher)
+	}
+
+	return r, nil
+}
+
+// BodyRules reads the matching rules of a body. They may be given as the V2 matching
+// rules of a request or response (keyed by paths such as $.body.name, where rules
+// for other parts are ignored), the V3/V4 matching rules (grouped by category) or
+// only their body category.
+func BodyRules(rules map[string]interface{}) (map[string]*Rule, error) {
+	if body, ok := rules["body"].(map[string]interface{}); ok {
+		rules = body
+	}
+
+	result := make(map[string]*Rule)
+	for path, r := range rules {
+		entry, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid matching rule for %s", path)
+		}
+
+		if _, ok := entry["matchers"]; ok {
+			rule, err := ParseRule(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid matching rule for %s: %w", path, err)
+			}
+			result[path] = rule
+			continue
+		}
+
+		// A V2 rule, e.g. "$.body.name": {"match": "type"}
+		segments, err := ParsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(segments) == 0 || segments[0] != "body" {
+			continue
+		}
+		matcher := make(map[string]interface{}, len(entry))
+		for k, v := range entry {
+			matcher[k] = v
+		}
+		if _, ok := matcher["match"]; !ok {
+			if _, ok := matcher["regex"]; ok {
+				matcher["match"] = "regex"
+			} else {
+				matcher["match"] = "type"
+			}
+		}
+		result[FormatPath(segments[1:])] = &Rule{Combine: "AND", Matchers: []map[string]interface{}{matcher}}
+	}
+
+	return result, nil
+}
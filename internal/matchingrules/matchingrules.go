@@ -0,0 +1,302 @@
+// Package matchingrules converts bodies described with matchers (in the JSON form
+// produced by the matchers package, e.g. {"pact:matcher:type": "regex", ...}) into
+// the example document, matching rules and generators of a V4 pact file.
+package matchingrules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Keys of the JSON form of a matcher
+const (
+	matcherTypeKey    = "pact:matcher:type"
+	matcherCombineKey = "pact:matcher:combine"
+	generatorTypeKey  = "pact:generator:type"
+)
+
+// generatorAttributes are the attributes of each generator type, copied from the
+// JSON form of the matcher it is given with
+var generatorAttributes = map[string][]string{
+	"RandomInt":         {"min", "max"},
+	"RandomDecimal":     {"digits"},
+	"RandomHexadecimal": {"digits"},
+	"RandomString":      {"size"},
+	"Regex":             {"regex"},
+	"Uuid":              {"format"},
+	"ProviderState":     {"expression"},
+}
+
+// Rule is the matching rule of a single path, in the V4 pact file layout
+type Rule struct {
+	Combine  string                   `json:"combine"`
+	Matchers []map[string]interface{} `json:"matchers"`
+}
+
+// Result is a body split into its example and the rules applied to it
+type Result struct {
+	// Example is the example document, with all matchers replaced by their values
+	Example interface{}
+
+	// Rules are the matching rules, keyed by path (e.g. $.items[*].id)
+	Rules map[string]*Rule
+
+	// Generators are the generators, keyed by path
+	Generators map[string]map[string]interface{}
+}
+
+// Split separates v (which may contain matchers) into the example document, and
+// the matching rules and generators keyed by their path from the root ("$")
+func Split(v interface{}) (*Result, error) {
+	node, err := normalise(v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Rules:      make(map[string]*Rule),
+		Generators: make(map[string]map[string]interface{}),
+	}
+	result.Example, err = result.walk(node, "$")
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// normalise converts v into its generic JSON representation, preserving numbers
+func normalise(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// IsMatcher reports whether the generic JSON node is the JSON form of a matcher,
+// returning its type
+func IsMatcher(node interface{}) (map[string]interface{}, string, bool) {
+	obj, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, "", false
+	}
+	t, ok := obj[matcherTypeKey].(string)
+
+	return obj, t, ok
+}
+
+func (r *Result) walk(node interface{}, path string) (interface{}, error) {
+	if obj, t, ok := IsMatcher(node); ok {
+		return r.walkMatcher(obj, t, path)
+	}
+	if obj, ok := node.(map[string]interface{}); ok {
+		if types, ok := obj[matcherTypeKey].([]interface{}); ok {
+			return r.walkCombined(obj, types, path)
+		}
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		example := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			value, err := r.walk(v, Path(path, k))
+			if err != nil {
+				return nil, err
+			}
+			example[k] = value
+		}
+		return example, nil
+	case []interface{}:
+		example := make([]interface{}, len(n))
+		for i, v := range n {
+			value, err := r.walk(v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			example[i] = value
+		}
+		return example, nil
+	default:
+		return node, nil
+	}
+}
+
+func (r *Result) walkMatcher(obj map[string]interface{}, t string, path string) (interface{}, error) {
+	rule := map[string]interface{}{
+		"match": t,
+	}
+	value := obj["value"]
+
+	switch t {
+	case "type":
+		for _, k := range []string{"min", "max"} {
+			if limit, ok := obj[k]; ok {
+				rule[k] = limit
+			}
+		}
+	case "regex":
+		rule["regex"] = obj["regex"]
+	case "include":
+		rule["value"] = value
+	case "timestamp", "date", "time":
+		if format, ok := obj["format"]; ok && format != "" {
+			rule["format"] = format
+		}
+	case "arrayContains":
+		variants := make([]interface{}, 0)
+		examples := make([]interface{}, 0)
+		list, _ := obj["variants"].([]interface{})
+		for i, v := range list {
+			variant, err := Split(v)
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, map[string]interface{}{
+				"index":      i,
+				"rules":      variant.Rules,
+				"generators": variant.Generators,
+			})
+			examples = append(examples, variant.Example)
+		}
+		rule["variants"] = variants
+		r.add(path, rule)
+
+		return examples, nil
+	case "null":
+		value = nil
+	case "integer", "decimal", "number", "boolean", "equality", "values", "notEmpty", "semver", "contentType":
+	default:
+		return nil, fmt.Errorf("unsupported matcher type %q at %s", t, path)
+	}
+	r.add(path, rule)
+	r.addGenerator(obj, path)
+
+	// An array matcher applies its element rules to every element
+	if list, ok := value.([]interface{}); ok && t == "type" && (obj["min"] != nil || obj["max"] != nil) {
+		examples := make([]interface{}, len(list))
+		for i, v := range list {
+			example, err := r.walk(v, path+"[*]")
+			if err != nil {
+				return nil, err
+			}
+			examples[i] = example
+		}
+		return examples, nil
+	}
+
+	// The values of an object matched by eachKeyLike share the rules of the template
+	if t == "values" {
+		if template, ok := value.(map[string]interface{}); ok {
+			example := make(map[string]interface{}, len(template))
+			for k, v := range template {
+				value, err := r.walk(v, path+".*")
+				if err != nil {
+					return nil, err
+				}
+				example[k] = value
+			}
+			return example, nil
+		}
+	}
+
+	return r.walk(value, path)
+}
+
+// walkCombined walks a matcher that combines several matchers, e.g. a nullable
+// value given as {"pact:matcher:type": [{...}, {"pact:matcher:type": "null"}],
+// "pact:matcher:combine": "OR"}. The example is taken from the first matcher.
+func (r *Result) walkCombined(obj map[string]interface{}, types []interface{}, path string) (interface{}, error) {
+	var example interface{}
+	for i, t := range types {
+		matcher, matcherType, ok := IsMatcher(t)
+		if !ok {
+			return nil, fmt.Errorf("invalid matcher %v at %s", t, path)
+		}
+
+		m := make(map[string]interface{}, len(matcher)+1)
+		for k, v := range matcher {
+			m[k] = v
+		}
+		m["value"] = obj["value"]
+
+		value, err := r.walkMatcher(m, matcherType, path)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			example = value
+		}
+	}
+
+	if combine, ok := obj[matcherCombineKey].(string); ok && r.Rules[path] != nil {
+		r.Rules[path].Combine = combine
+	}
+	r.addGenerator(obj, path)
+
+	return example, nil
+}
+
+// addGenerator adds the generator of the matcher, if it has one
+func (r *Result) addGenerator(obj map[string]interface{}, path string) {
+	generator, ok := obj[generatorTypeKey].(string)
+	if !ok {
+		return
+	}
+
+	g := map[string]interface{}{
+		"type": generator,
+	}
+	attributes, ok := generatorAttributes[generator]
+	if !ok {
+		attributes = []string{"format", "expression"}
+	}
+	for _, k := range attributes {
+		if v, ok := obj[k]; ok && v != "" {
+			g[k] = v
+		}
+	}
+	r.Generators[path] = g
+}
+
+func (r *Result) add(path string, matcher map[string]interface{}) {
+	rule, ok := r.Rules[path]
+	if !ok {
+		rule = &Rule{
+			Combine: "AND",
+		}
+		r.Rules[path] = rule
+	}
+
+	// Elements of an array matcher share the same path, so may repeat a rule
+	for _, m := range rule.Matchers {
+		if reflect.DeepEqual(m, matcher) {
+			return
+		}
+	}
+	rule.Matchers = append(rule.Matchers, matcher)
+}
+
+var identifier = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Path appends the key to the path, e.g. $.id or $['first name']
+func Path(path string, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "['" + strings.ReplaceAll(key, "'", `\'`) + "']"
+}
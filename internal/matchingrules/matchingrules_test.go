@@ -0,0 +1,186 @@
+package matchingrules_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	body := matchers.StructMatcher{
+		"id":    matchers.Integer(12),
+		"name":  "billy",
+		"email": matchers.Regex("billy@example.com", `^.+@.+$`),
+		"items": matchers.EachLike(matchers.StructMatcher{
+			"sku": matchers.Like("abc"),
+		}, 2),
+		"created":    matchers.DateTimeGenerated("2020-01-01T08:00:45", "yyyy-MM-dd'T'HH:mm:ss"),
+		"first name": matchers.Like("b"),
+	}
+
+	result, err := matchingrules.Split(body)
+	assert.NoError(t, err)
+
+	example, _ := json.Marshal(result.Example)
+	assert.JSONEq(t, `{
+		"id": 12,
+		"name": "billy",
+		"email": "billy@example.com",
+		"items": [{"sku": "abc"}, {"sku": "abc"}],
+		"created": "2020-01-01T08:00:45",
+		"first name": "b"
+	}`, string(example))
+
+	rules, _ := json.Marshal(result.Rules)
+	assert.JSONEq(t, `{
+		"$.id": {"combine": "AND", "matchers": [{"match": "integer"}]},
+		"$.email": {"combine": "AND", "matchers": [{"match": "regex", "regex": "^.+@.+$"}]},
+		"$.items": {"combine": "AND", "matchers": [{"match": "type", "min": 2}]},
+		"$.items[*].sku": {"combine": "AND", "matchers": [{"match": "type"}]},
+		"$.created": {"combine": "AND", "matchers": [{"match": "timestamp", "format": "yyyy-MM-dd'T'HH:mm:ss"}]},
+		"$['first name']": {"combine": "AND", "matchers": [{"match": "type"}]}
+	}`, string(rules))
+
+	generators, _ := json.Marshal(result.Generators)
+	assert.JSONEq(t, `{"$.created": {"type": "DateTime", "format": "yyyy-MM-dd'T'HH:mm:ss"}}`, string(generators))
+}
+
+func TestSplitRootMatcher(t *testing.T) {
+	result, err := matchingrules.Split(matchers.Regex("/users/1", `^/users/\d+$`))
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/1", result.Example)
+	assert.Equal(t, []map[string]interface{}{{"match": "regex", "regex": `^/users/\d+$`}}, result.Rules["$"].Matchers)
+}
+
+func TestSplitUnsupportedMatcher(t *testing.T) {
+	_, err := matchingrules.Split(map[string]interface{}{
+		"a": map[string]interface{}{"pact:matcher:type": "unknown"},
+	})
+	assert.ErrorContains(t, err, `unsupported matcher type "unknown" at $.a`)
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "$.id", matchingrules.Path("$", "id"))
+	assert.Equal(t, "$['a.b']", matchingrules.Path("$", "a.b"))
+	assert.Equal(t, `$['it\'s']`, matchingrules.Path("$", "it's"))
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"$", []string{}},
+		{"$.id", []string{"id"}},
+		{"$.items[*].name", []string{"items", matchingrules.AnyIndex, "name"}},
+		{"$['first name'][0]", []string{"first name", "[0]"}},
+		{`$['it\'s']`, []string{"it's"}},
+		{"$.*", []string{matchingrules.AnyKey}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := matchingrules.ParsePath(tt.path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.path, matchingrules.FormatPath(got))
+		})
+	}
+
+	for _, invalid := range []string{"id", "$.", "$[x]", "$['a", "$a"} {
+		_, err := matchingrules.ParsePath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	rules := map[string]*matchingrules.Rule{
+		"$.items[*].id": {Combine: "AND", Matchers: []map[string]interface{}{{"match": "integer"}}},
+		"$.items[0].id": {Combine: "AND", Matchers: []map[string]interface{}{{"match": "type"}}},
+		"$.*":           {Combine: "AND", Matchers: []map[string]interface{}{{"match": "type"}}},
+	}
+
+	assert.Equal(t, rules["$.items[*].id"], matchingrules.Lookup(rules, []string{"items", matchingrules.Index(1), "id"}))
+	assert.Equal(t, rules["$.items[0].id"], matchingrules.Lookup(rules, []string{"items", matchingrules.Index(0), "id"}))
+	assert.Equal(t, rules["$.*"], matchingrules.Lookup(rules, []string{"name"}))
+	assert.Nil(t, matchingrules.Lookup(rules, []string{"items", matchingrules.Index(0), "name"}))
+}
+
+func TestSplitTaggedStruct(t *testing.T) {
+	type dto struct {
+		Age      int      `json:"age" pact:"generator=random,min=18,max=99"`
+		Birthday *string  `json:"birthday" pact:"format=date,nullable"`
+		Roles    []string `json:"roles" pact:"nullable,max=3"`
+	}
+
+	result, err := matchingrules.Split(matchers.MatchV4(dto{}))
+	assert.NoError(t, err)
+
+	example, _ := json.Marshal(result.Example)
+	assert.JSONEq(t, `{"age": 1, "birthday": "2000-02-01", "roles": ["string"]}`, string(example))
+
+	rules, _ := json.Marshal(result.Rules)
+	assert.JSONEq(t, `{
+		"$.age": {"combine": "AND", "matchers": [{"match": "integer"}]},
+		"$.birthday": {"combine": "OR", "matchers": [{"match": "date", "format": "yyyy-MM-dd"}, {"match": "null"}]},
+		"$.roles": {"combine": "OR", "matchers": [{"match": "type", "min": 1, "max": 3}, {"match": "null"}]},
+		"$.roles[*]": {"combine": "AND", "matchers": [{"match": "type"}]}
+	}`, string(rules))
+
+	generators, _ := json.Marshal(result.Generators)
+	assert.JSONEq(t, `{"$.age": {"type": "RandomInt", "min": 18, "max": 99}}`, string(generators))
+}
+
+func TestJoin(t *testing.T) {
+	body := matchers.StructMatcher{
+		"id":      matchers.Integer(12),
+		"name":    "billy",
+		"items":   matchers.EachLike(matchers.StructMatcher{"sku": matchers.Like("abc")}, 2),
+		"created": matchers.DateTimeGenerated("2020-01-01T08:00:45", "yyyy-MM-dd'T'HH:mm:ss"),
+		"tags":    matchers.ArrayContaining([]interface{}{matchers.Like("a"), matchers.Integer(1)}),
+		"user": matchers.MatchV4(struct {
+			Age *int `json:"age" pact:"nullable"`
+		}{}),
+	}
+
+	split, err := matchingrules.Split(body)
+	assert.NoError(t, err)
+
+	joined, err := matchingrules.Join(split.Example, split.Rules, split.Generators)
+	assert.NoError(t, err)
+
+	again, err := matchingrules.Split(joined)
+	assert.NoError(t, err)
+	assert.Equal(t, split.Example, again.Example)
+	assert.Equal(t, split.Rules, again.Rules)
+	assert.Equal(t, split.Generators, again.Generators)
+}
+
+func TestBodyRules(t *testing.T) {
+	var rules map[string]interface{}
+	_ = json.Unmarshal([]byte(`{
+		"$.body.id": {"match": "type"},
+		"$.body.items[*].sku": {"regex": "^[a-z]+$"},
+		"$.headers.Accept": {"match": "type"}
+	}`), &rules)
+
+	got, err := matchingrules.BodyRules(rules)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*matchingrules.Rule{
+		"$.id":           {Combine: "AND", Matchers: []map[string]interface{}{{"match": "type"}}},
+		"$.items[*].sku": {Combine: "AND", Matchers: []map[string]interface{}{{"match": "regex", "regex": "^[a-z]+$"}}},
+	}, got)
+
+	_ = json.Unmarshal([]byte(`{"body": {"$.id": {"matchers": [{"match": "integer"}]}}, "header": {}}`), &rules)
+	got, err = matchingrules.BodyRules(rules)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*matchingrules.Rule{
+		"$.id": {Combine: "AND", Matchers: []map[string]interface{}{{"match": "integer"}}},
+	}, got)
+
+	_, err = matchingrules.BodyRules(map[string]interface{}{"$.id": map[string]interface{}{"matchers": "type"}})
+	assert.EqualError(t, err, "invalid matching rule for $.id: missing matchers")
+}
@@ -0,0 +1,155 @@
+package matchingrules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Segments of a parsed path that match any key or any index
+const (
+	AnyKey   = "*"
+	AnyIndex = "[*]"
+)
+
+// Index returns the path segment of an array index, e.g. [0]
+func Index(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// ParsePath splits a matching rule path such as $.items[*].name or $['first name']
+// into its segments. Keys are returned as is, indices as [n], and wildcards as AnyKey
+// or AnyIndex. The leading $ is not included.
+func ParsePath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid path %q, expected it to start with $", path)
+	}
+
+	segments := make([]string, 0)
+	rest := path[1:]
+
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("invalid path %q, expected a key after '.'", path)
+			}
+			segments = append(segments, key)
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['"):
+			key, n, err := quotedKey(rest[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", path, err)
+			}
+			segments = append(segments, key)
+			rest = rest[2+n:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q, missing ']'", path)
+			}
+			index := rest[1:end]
+			if index == "*" {
+				segments = append(segments, AnyIndex)
+			} else if _, err := strconv.Atoi(index); err == nil {
+				segments = append(segments, "["+index+"]")
+			} else {
+				return nil, fmt.Errorf("invalid path %q, invalid index %q", path, index)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %q at %q", path, rest)
+		}
+	}
+
+	return segments, nil
+}
+
+// quotedKey reads a key ending with '], returning it and the number of bytes read
+func quotedKey(s string) (string, int, error) {
+	var key strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			key.WriteByte(s[i])
+		case strings.HasPrefix(s[i:], "']"):
+			return key.String(), i + 2, nil
+		default:
+			key.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("missing ']")
+}
+
+// FormatPath joins path segments into a matching rule path, e.g. $.items[*].name
+func FormatPath(segments []string) string {
+	path := "$"
+	for _, s := range segments {
+		if s == AnyKey || isIndex(s) {
+			if s == AnyKey {
+				path += ".*"
+			} else {
+				path += s
+			}
+			continue
+		}
+		path = Path(path, s)
+	}
+
+	return path
+}
+
+func isIndex(segment string) bool {
+	return strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]")
+}
+
+// MatchPath reports whether the pattern (which may contain wildcards) matches the
+// segments of a path in a document, and returns its weight. Exact segments weigh more
+// than wildcards, so that the most specific rule for a path can be chosen.
+func MatchPath(pattern []string, segments []string) (bool, int) {
+	if len(pattern) != len(segments) {
+		return false, 0
+	}
+
+	weight := 1
+	for i, p := range pattern {
+		switch {
+		case p == segments[i]:
+			weight *= 2
+		case p == AnyKey && !isIndex(segments[i]):
+		case p == AnyIndex && isIndex(segments[i]):
+		default:
+			return false, 0
+		}
+	}
+
+	return true, weight
+}
+
+// Lookup returns the most specific rule (or generator) that applies to the path in a
+// document, or the zero value if there is none
+func Lookup[T any](rules map[string]T, segments []string) T {
+	var found T
+	var foundPath string
+	best := 0
+
+	for path, rule := range rules {
+		pattern, err := ParsePath(path)
+		if err != nil {
+			continue
+		}
+		ok, weight := MatchPath(pattern, segments)
+		if ok && (weight > best || (weight == best && path < foundPath)) {
+			found, foundPath, best = rule, path, weight
+		}
+	}
+
+	return found
+}
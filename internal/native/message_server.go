@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"log"
 	"unsafe"
+
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
 )
 
 type MessagePact struct {
@@ -537,58 +539,62 @@ func (m *MessageServer) MockServerMatched(port int) bool {
 	return bool(res)
 }
 
-// WritePactFile writes the Pact to file.
-func (m *MessageServer) WritePactFile(dir string, overwrite bool) error {
+// WritePactFile writes the Pact to file, combining it with any existing pact file
+// following the mode.
+func (m *MessageServer) WritePactFile(dir string, mode pactfile.Mode) error {
 	log.Println("[DEBUG] writing pact file for message pact at dir:", dir)
-	cDir := C.CString(dir)
-	defer free(cDir)
-
-	overwritePact := overwrite
-
-	res := int(C.pactffi_write_message_pact_file(m.messagePact.handle, cDir, C.bool(overwritePact)))
 
-	/// | Error | Description |
-	/// |-------|-------------|
-	/// | 1 | The pact file was not able to be written |
-	/// | 2 | The message pact for the given handle was not found |
-	switch res {
-	case 0:
-		return nil
-	case 1:
-		return ErrUnableToWritePactFile
-	case 2:
-		return ErrHandleNotFound
-	default:
-		return fmt.Errorf("an unknown error ocurred when writing to pact file")
-	}
+	return writePactFiles(dir, mode, nil, func(scratch string) error {
+		cDir := C.CString(scratch)
+		defer free(cDir)
+
+		res := int(C.pactffi_write_message_pact_file(m.messagePact.handle, cDir, C.bool(true)))
+
+		/// | Error | Description |
+		/// |-------|-------------|
+		/// | 1 | The pact file was not able to be written |
+		/// | 2 | The message pact for the given handle was not found |
+		switch res {
+		case 0:
+			return nil
+		case 1:
+			return ErrUnableToWritePactFile
+		case 2:
+			return ErrHandleNotFound
+		default:
+			return fmt.Errorf("an unknown error ocurred when writing to pact file")
+		}
+	})
 }
 
-// WritePactFile writes the Pact to file.
-func (m *MessageServer) WritePactFileForServer(port int, dir string, overwrite bool) error {
+// WritePactFileForServer writes the Pact for the mock server running on the given
+// port to file, combining it with any existing pact file following the mode.
+func (m *MessageServer) WritePactFileForServer(port int, dir string, mode pactfile.Mode) error {
 	log.Println("[DEBUG] writing pact file for message pact at dir:", dir)
-	cDir := C.CString(dir)
-	defer free(cDir)
 
-	overwritePact := overwrite
-
-	res := int(C.pactffi_write_pact_file(C.int(port), cDir, C.bool(overwritePact)))
-
-	/// | Error | Description |
-	/// |-------|-------------|
-	/// | 1 | The pact file was not able to be written |
-	/// | 2 | The message pact for the given handle was not found |
-	switch res {
-	case 0:
-		return nil
-	case 1:
-		return ErrMockServerPanic
-	case 2:
-		return ErrUnableToWritePactFile
-	case 3:
-		return ErrHandleNotFound
-	default:
-		return fmt.Errorf("an unknown error ocurred when writing to pact file")
-	}
+	return writePactFiles(dir, mode, nil, func(scratch string) error {
+		cDir := C.CString(scratch)
+		defer free(cDir)
+
+		res := int(C.pactffi_write_pact_file(C.int(port), cDir, C.bool(true)))
+
+		/// | Error | Description |
+		/// |-------|-------------|
+		/// | 1 | The pact file was not able to be written |
+		/// | 2 | The message pact for the given handle was not found |
+		switch res {
+		case 0:
+			return nil
+		case 1:
+			return ErrMockServerPanic
+		case 2:
+			return ErrUnableToWritePactFile
+		case 3:
+			return ErrHandleNotFound
+		default:
+			return fmt.Errorf("an unknown error ocurred when writing to pact file")
+		}
+	})
 }
 
 // WithReference records an external reference (e.g. a ticket or pull request)
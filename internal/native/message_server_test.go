@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
 	"github.com/pact-foundation/pact-go/v2/log"
 	"google.golang.org/protobuf/proto"
 
@@ -44,7 +45,7 @@ func TestHandleBasedMessageTestsWithString(t *testing.T) {
 
 	// This is where you would invoke the real function with the message
 
-	err = s.WritePactFile(tmpPactFolder, false)
+	err = s.WritePactFile(tmpPactFolder, pactfile.MergeMode)
 	assert.NoError(t, err)
 }
 
@@ -78,7 +79,7 @@ func TestHandleBasedMessageTestsWithJSON(t *testing.T) {
 
 	// This is where you would invoke the real function with the message
 
-	err = s.WritePactFile(tmpPactFolder, false)
+	err = s.WritePactFile(tmpPactFolder, pactfile.MergeMode)
 	assert.NoError(t, err)
 }
 
@@ -123,7 +124,7 @@ func TestHandleBasedMessageTestsWithBinary(t *testing.T) {
 
 	// This is where you would invoke the real function with the message...
 
-	err = s.WritePactFile(tmpPactFolder, false)
+	err = s.WritePactFile(tmpPactFolder, pactfile.MergeMode)
 	assert.NoError(t, err)
 }
 
@@ -426,7 +427,7 @@ func TestGrpcPluginInteraction(t *testing.T) {
 		t.Log(mismatches)
 	}
 
-	err = m.WritePactFileForServer(port, tmpPactFolder, true)
+	err = m.WritePactFileForServer(port, tmpPactFolder, pactfile.OverwriteMode)
 	assert.NoError(t, err)
 }
 
@@ -500,6 +501,6 @@ func TestGrpcPluginInteraction_ErrorResponse(t *testing.T) {
 		t.Log(mismatches)
 	}
 
-	err = m.WritePactFileForServer(port, tmpPactFolder, true)
+	err = m.WritePactFileForServer(port, tmpPactFolder, pactfile.OverwriteMode)
 	assert.NoError(t, err)
 }
@@ -1,5 +1,10 @@
 package native
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // Request is the sub-struct of Mismatch
 type Request struct {
 	Method  string            `json:"method"`
@@ -33,6 +38,58 @@ type MismatchDetail struct {
 	Key      string
 	Mismatch string
 	Type     string
+
+	// Path is the JSON path of a body or metadata mismatch, e.g. "$.items[0].id"
+	Path string
+
+	// Parameter is the name of the query parameter for a query mismatch
+	Parameter string
+}
+
+// UnmarshalJSON decodes a mismatch detail from the FFI. The expected and actual
+// values are not always strings (e.g. StatusMismatch uses numbers and BodyMismatch
+// may use JSON documents), so they are normalised into their string form.
+func (m *MismatchDetail) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Actual    json.RawMessage `json:"actual"`
+		Expected  json.RawMessage `json:"expected"`
+		Key       string          `json:"key"`
+		Mismatch  string          `json:"mismatch"`
+		Type      string          `json:"type"`
+		Path      string          `json:"path"`
+		Parameter string          `json:"parameter"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = MismatchDetail{
+		Actual:    rawMismatchValue(raw.Actual),
+		Expected:  rawMismatchValue(raw.Expected),
+		Key:       raw.Key,
+		Mismatch:  raw.Mismatch,
+		Type:      raw.Type,
+		Path:      raw.Path,
+		Parameter: raw.Parameter,
+	}
+
+	return nil
+}
+
+// rawMismatchValue converts an arbitrary JSON value into a string, unquoting
+// plain strings and leaving other documents in their JSON form
+func rawMismatchValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return strings.TrimSpace(string(raw))
 }
 
 // MismatchedRequest contains details of any request mismatches during pact verification
@@ -0,0 +1,43 @@
+package native
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMismatchDetail_UnmarshalJSON(t *testing.T) {
+	raw := `[
+		{
+			"method": "GET",
+			"path": "/foobar",
+			"type": "request-mismatch",
+			"mismatches": [
+				{"type": "HeaderMismatch", "key": "Authorization", "expected": "Bearer 1234", "actual": "", "mismatch": "Expected header 'Authorization' but was missing"},
+				{"type": "QueryMismatch", "parameter": "page", "expected": "1", "actual": "2", "mismatch": "Expected '1' but received '2' for query parameter 'page'"},
+				{"type": "BodyMismatch", "path": "$.id", "expected": {"id": 1}, "actual": null, "mismatch": "Expected id to be present"},
+				{"type": "StatusMismatch", "expected": 200, "actual": 404},
+				{"type": "MethodMismatch", "expected": "GET", "actual": "POST"}
+			]
+		}
+	]`
+
+	var res []MismatchedRequest
+	err := json.Unmarshal([]byte(raw), &res)
+
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "GET", res[0].Method)
+	assert.Equal(t, "/foobar", res[0].Path)
+	assert.Len(t, res[0].Mismatches, 5)
+
+	assert.Equal(t, "Authorization", res[0].Mismatches[0].Key)
+	assert.Equal(t, "page", res[0].Mismatches[1].Parameter)
+	assert.Equal(t, "$.id", res[0].Mismatches[2].Path)
+	assert.Equal(t, `{"id": 1}`, res[0].Mismatches[2].Expected)
+	assert.Equal(t, "", res[0].Mismatches[2].Actual)
+	assert.Equal(t, "200", res[0].Mismatches[3].Expected)
+	assert.Equal(t, "404", res[0].Mismatches[3].Actual)
+	assert.Equal(t, "POST", res[0].Mismatches[4].Actual)
+}
@@ -15,7 +15,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"unsafe"
+
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
 )
 
 type interactionPart int
@@ -68,7 +71,18 @@ type Pact struct {
 
 // Interaction is a Go representation of the InteractionHandle struct
 type Interaction struct {
-	handle C.InteractionHandle
+	handle  C.InteractionHandle
+	summary InteractionSummary
+}
+
+// InteractionSummary identifies an HTTP interaction, e.g. in reports of mismatches
+type InteractionSummary struct {
+	Description    string
+	ProviderStates []string
+	Method         string
+
+	// Path is the path of the request, or the JSON form of its matcher
+	Path string
 }
 
 // Version returns the current semver FFI interface version
@@ -79,15 +93,24 @@ func Version() string {
 }
 
 var loggingInitialised string
+var loggingMu sync.Mutex
 
-// Init initialises the library
+// Init initialises the library. It is safe to call from concurrently running tests,
+// only the first call configures the native logger.
 func Init(logLevel string) {
 	log.Println("[DEBUG] initialising native interface")
 	logLevel = strings.ToUpper(logLevel)
 
+	loggingMu.Lock()
+	defer loggingMu.Unlock()
+
 	if loggingInitialised != "" {
-		log.Printf("log level ('%s') cannot be set to '%s' after initialisation\n", loggingInitialised, logLevel)
+		if loggingInitialised != logLevel {
+			log.Printf("[WARN] log level ('%s') cannot be set to '%s' after initialisation\n", loggingInitialised, logLevel)
+		}
 	} else {
+		loggingInitialised = logLevel
+
 		l, ok := logLevelStringToInt[logLevel]
 		if !ok {
 			l = LOG_LEVEL_INFO
@@ -115,6 +138,9 @@ type MockServer struct {
 	pact         *Pact
 	messagePact  *MessagePact
 	interactions []*Interaction
+
+	// checkPact is given each pact before it is written, see WithPactCheck
+	checkPact func(pact []byte) error
 }
 
 // NewHTTPPact creates a new HTTP mock server for a given consumer/provider
@@ -179,38 +205,42 @@ func (m *MockServer) CleanupMockServer(port int) bool {
 	return bool(res)
 }
 
-// WritePactFile writes the Pact to file.
-// TODO: expose overwrite
-func (m *MockServer) WritePactFile(port int, dir string) error {
-	log.Println("[DEBUG] writing pact file for mock server on port:", port, ", dir:", dir)
-	cDir := C.CString(dir)
-	defer free(cDir)
-
-	// overwritePact := 0
-	// if overwrite {
-	// 	overwritePact = 1
-	// }
+// FreePact releases the native pact handle, and all interactions registered against it.
+// The MockServer must not be used after it has been freed.
+func (m *MockServer) FreePact() {
+	res := C.pactffi_free_pact_handle(m.pact.handle)
+	log.Println("[DEBUG] pactffi_free_pact_handle res", res)
+}
 
-	// res := int(C.pactffi_write_pact_file(C.int(port), cDir, C.int(overwritePact)))
-	res := int(C.pactffi_write_pact_file(C.int(port), cDir, C.bool(false)))
+// WritePactFile writes the Pact to file, combining it with any existing pact file
+// following the mode.
+func (m *MockServer) WritePactFile(port int, dir string, mode pactfile.Mode) error {
+	log.Println("[DEBUG] writing pact file for mock server on port:", port, ", dir:", dir)
 
-	// | Error | Description |
-	// |-------|-------------|
-	// | 1 | A general panic was caught |
-	// | 2 | The pact file was not able to be written |
-	// | 3 | A mock server with the provided port was not found |
-	switch res {
-	case 0:
-		return nil
-	case 1:
-		return ErrMockServerPanic
-	case 2:
-		return ErrUnableToWritePactFile
-	case 3:
-		return ErrMockServerNotfound
-	default:
-		return fmt.Errorf("an unknown error ocurred when writing to pact file")
-	}
+	return writePactFiles(dir, mode, m.checkPact, func(scratch string) error {
+		cDir := C.CString(scratch)
+		defer free(cDir)
+
+		res := int(C.pactffi_write_pact_file(C.int(port), cDir, C.bool(true)))
+
+		// | Error | Description |
+		// |-------|-------------|
+		// | 1 | A general panic was caught |
+		// | 2 | The pact file was not able to be written |
+		// | 3 | A mock server with the provided port was not found |
+		switch res {
+		case 0:
+			return nil
+		case 1:
+			return ErrMockServerPanic
+		case 2:
+			return ErrUnableToWritePactFile
+		case 3:
+			return ErrMockServerNotfound
+		default:
+			return fmt.Errorf("an unknown error ocurred when writing to pact file")
+		}
+	})
 }
 
 // GetTLSConfig returns a tls.Config compatible with the TLS
@@ -344,6 +374,14 @@ func (m *MockServer) StartTransport(transport string, address string, port int,
 	}
 }
 
+// WithPactCheck sets a check of the contents of each pact file before it is written.
+// If the check returns an error, WritePactFile returns it and writes nothing.
+func (m *MockServer) WithPactCheck(check func(pact []byte) error) *MockServer {
+	m.checkPact = check
+
+	return m
+}
+
 // Sets the additional metadata on the Pact file. Common uses are to add the client library details such as the name and version
 func (m *MockServer) WithMetadata(namespace, k, v string) *MockServer {
 	cNamespace := C.CString(namespace)
@@ -398,13 +436,24 @@ func (m *MockServer) NewInteraction(description string) *Interaction {
 	defer free(cDescription)
 
 	i := &Interaction{
-		handle: C.pactffi_new_interaction(m.pact.handle, cDescription),
+		handle:  C.pactffi_new_interaction(m.pact.handle, cDescription),
+		summary: InteractionSummary{Description: description},
 	}
 	m.interactions = append(m.interactions, i)
 
 	return i
 }
 
+// Interactions summarises the interactions of the current contract
+func (m *MockServer) Interactions() []InteractionSummary {
+	summaries := make([]InteractionSummary, len(m.interactions))
+	for n, i := range m.interactions {
+		summaries[n] = i.summary
+	}
+
+	return summaries
+}
+
 // NewInteraction initialises a new interaction for the current contract
 func (i *Interaction) WithPluginInteractionContents(part interactionPart, contentType string, contents string) error {
 	cContentType := C.CString(contentType)
@@ -448,18 +497,21 @@ func (i *Interaction) UponReceiving(description string) *Interaction {
 	defer free(cDescription)
 
 	C.pactffi_upon_receiving(i.handle, cDescription)
+	i.summary.Description = description
 
 	return i
 }
 
 func (i *Interaction) Given(state string) *Interaction {
 	interactionGiven(i.handle, state)
+	i.summary.ProviderStates = append(i.summary.ProviderStates, state)
 
 	return i
 }
 
 func (i *Interaction) GivenWithParameter(state string, params map[string]interface{}) *Interaction {
 	interactionGivenWithParams(i.handle, state, params)
+	i.summary.ProviderStates = append(i.summary.ProviderStates, state)
 
 	return i
 }
@@ -473,6 +525,8 @@ func (i *Interaction) WithRequest(method string, pathOrMatcher interface{}) *Int
 	defer free(cPath)
 
 	C.pactffi_with_request(i.handle, cMethod, cPath)
+	i.summary.Method = method
+	i.summary.Path = path
 
 	return i
 }
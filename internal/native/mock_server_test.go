@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
 	"github.com/pact-foundation/pact-go/v2/log"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
@@ -129,7 +130,7 @@ func TestMockServer_WritePactfile(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error sending request: %v", err)
 	}
-	err = m.WritePactFile(port, tmpPactFolder)
+	err = m.WritePactFile(port, tmpPactFolder, pactfile.MergeMode)
 
 	if err != nil {
 		t.Fatal("error: ", err)
@@ -180,7 +181,7 @@ func TestHandleBasedHTTPTests(t *testing.T) {
 		t.Fatalf("want 0 mismatches, got '%d'", len(mismatches))
 	}
 
-	err = m.WritePactFile(port, tmpPactFolder)
+	err = m.WritePactFile(port, tmpPactFolder, pactfile.MergeMode)
 	assert.NoError(t, err)
 }
 
@@ -239,6 +240,6 @@ func TestPluginInteraction(t *testing.T) {
 		t.Log(mismatches)
 	}
 
-	err = m.WritePactFile(port, tmpPactFolder)
+	err = m.WritePactFile(port, tmpPactFolder, pactfile.MergeMode)
 	assert.NoError(t, err)
 }
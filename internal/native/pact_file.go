@@ -0,0 +1,53 @@
+package native
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+)
+
+// writePactFiles has the core write the pact into a scratch directory, and then
+// merges each file it wrote into dir following the mode. Merging is done under an exclusive file lock,
+// so tests running concurrently - in this or other processes - may share a pact file.
+// If check is not nil, it is given the contents of each file first, and nothing is
+// merged if it returns an error.
+func writePactFiles(dir string, mode pactfile.Mode, check func(pact []byte) error, write func(scratch string) error) error {
+	scratch, err := os.MkdirTemp("", "pact-go-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := write(scratch); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(scratch, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	pacts := make(map[string][]byte, len(files))
+	for _, f := range files {
+		contents, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if check != nil {
+			if err := check(contents); err != nil {
+				return err
+			}
+		}
+		pacts[filepath.Base(f)] = contents
+	}
+
+	for _, f := range files {
+		name := filepath.Base(f)
+		if err := pactfile.Write(filepath.Join(dir, name), pacts[name], mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,352 @@
+package pactfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+)
+
+// Categories of matching rules
+const (
+	RulesPath   = "path"
+	RulesQuery  = "query"
+	RulesHeader = "header"
+	RulesBody   = "body"
+)
+
+// HTTPInteraction is an HTTP interaction of a V2, V3 or V4 pact, normalised so that
+// the differences between the specification versions can be ignored
+type HTTPInteraction struct {
+	Description    string
+	ProviderStates []string
+	Request        HTTPRequest
+	Response       HTTPResponse
+}
+
+// HTTPRequest is the expected request of an interaction
+type HTTPRequest struct {
+	Method  string
+	Path    string
+	Query   map[string][]string
+	Headers map[string][]string
+	Body    *Body
+
+	// Rules are the matching rules of the request, keyed by category and then by
+	// path (for the body), name (for the query and headers) or "" (for the path)
+	Rules map[string]map[string]*matchingrules.Rule
+}
+
+// HTTPResponse is the expected response of an interaction
+type HTTPResponse struct {
+	Status  int
+	Headers map[string][]string
+	Body    *Body
+	Rules   map[string]map[string]*matchingrules.Rule
+}
+
+// Body is the body of a request or response
+type Body struct {
+	ContentType string
+
+	// JSON is set if Content is a decoded JSON document, rather than text or base64
+	JSON    bool
+	Content interface{}
+}
+
+// Header returns the values of the header, ignoring the case of its name
+func Header(headers map[string][]string, name string) []string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// HTTPInteractions returns the HTTP interactions of a pact. Message and plugin
+// interactions are ignored.
+func HTTPInteractions(data []byte) ([]HTTPInteraction, error) {
+	var pact map[string]interface{}
+	if err := decode(data, &pact); err != nil {
+		return nil, fmt.Errorf("unable to read pact: %w", err)
+	}
+
+	version := specificationVersion(pact)
+
+	raw, _ := pact["interactions"].([]interface{})
+	interactions := make([]HTTPInteraction, 0, len(raw))
+
+	for n, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unable to read pact: invalid interaction %d", n)
+		}
+		if t, ok := m["type"].(string); ok && t != "Synchronous/HTTP" {
+			continue
+		}
+
+		i, err := httpInteraction(m, version)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read interaction %q: %w", i.Description, err)
+		}
+		interactions = append(interactions, i)
+	}
+
+	return interactions, nil
+}
+
+func httpInteraction(m map[string]interface{}, version string) (HTTPInteraction, error) {
+	v2 := strings.HasPrefix(version, "1") || strings.HasPrefix(version, "2")
+	v4 := strings.HasPrefix(version, "4") || m["type"] != nil
+
+	i := HTTPInteraction{}
+	i.Description, _ = m["description"].(string)
+
+	if state, ok := m["providerState"].(string); ok && state != "" {
+		i.ProviderStates = append(i.ProviderStates, state)
+	}
+	states, _ := m["providerStates"].([]interface{})
+	for _, s := range states {
+		if state, ok := s.(map[string]interface{}); ok {
+			name, _ := state["name"].(string)
+			i.ProviderStates = append(i.ProviderStates, name)
+		}
+	}
+
+	request, _ := m["request"].(map[string]interface{})
+	response, _ := m["response"].(map[string]interface{})
+	if request == nil || response == nil {
+		return i, fmt.Errorf("missing request or response")
+	}
+
+	var err error
+	i.Request.Method, _ = request["method"].(string)
+	i.Request.Method = strings.ToUpper(i.Request.Method)
+	i.Request.Path, _ = request["path"].(string)
+	if i.Request.Query, err = query(request["query"]); err != nil {
+		return i, err
+	}
+	i.Request.Headers = headers(request["headers"])
+	i.Request.Body = body(request, i.Request.Headers, v4)
+	if i.Request.Rules, err = rules(request["matchingRules"], v2); err != nil {
+		return i, fmt.Errorf("request: %w", err)
+	}
+
+	status, ok := response["status"].(json.Number)
+	if !ok {
+		return i, fmt.Errorf("missing response status")
+	}
+	s, err := status.Int64()
+	if err != nil {
+		return i, fmt.Errorf("invalid response status %s", status)
+	}
+	i.Response.Status = int(s)
+	i.Response.Headers = headers(response["headers"])
+	i.Response.Body = body(response, i.Response.Headers, v4)
+	if i.Response.Rules, err = rules(response["matchingRules"], v2); err != nil {
+		return i, fmt.Errorf("response: %w", err)
+	}
+
+	return i, nil
+}
+
+// query reads a V2 query string, or a V3/V4 map of query parameters
+func query(v interface{}) (map[string][]string, error) {
+	switch q := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		values, err := url.ParseQuery(q)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query %q: %w", q, err)
+		}
+		return values, nil
+	case map[string]interface{}:
+		return headers(q), nil
+	}
+
+	return nil, fmt.Errorf("invalid query %v", v)
+}
+
+// headers reads a map of single values (V2/V3) or lists of values (V4)
+func headers(v interface{}) map[string][]string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	values := make(map[string][]string, len(m))
+	for k, value := range m {
+		switch h := value.(type) {
+		case []interface{}:
+			for _, s := range h {
+				values[k] = append(values[k], fmt.Sprint(s))
+			}
+		default:
+			values[k] = []string{fmt.Sprint(h)}
+		}
+	}
+
+	return values
+}
+
+// body reads a V2/V3 body, which is the body itself, or a V4 body which describes its content
+func body(part map[string]interface{}, headers map[string][]string, v4 bool) *Body {
+	v, ok := part["body"]
+	if !ok || v == nil {
+		return nil
+	}
+
+	b := &Body{Content: v}
+	if contentType := Header(headers, "Content-Type"); len(contentType) > 0 {
+		b.ContentType = contentType[0]
+	}
+
+	if described, ok := v.(map[string]interface{}); ok && v4 {
+		if content, ok := described["content"]; ok {
+			b.Content = content
+			if contentType, ok := described["contentType"].(string); ok {
+				b.ContentType = contentType
+			}
+
+			switch encoded := described["encoded"].(type) {
+			case string:
+				if !strings.EqualFold(encoded, "json") {
+					return b
+				}
+			case bool:
+				if encoded {
+					return b
+				}
+			}
+		}
+	}
+
+	if !isJSON(b.ContentType) && b.ContentType != "" {
+		return b
+	}
+
+	// JSON bodies may be embedded as a string, otherwise the body is a JSON string
+	if s, ok := b.Content.(string); ok {
+		var content interface{}
+		if err := decode([]byte(s), &content); err == nil {
+			b.Content = content
+		} else if b.ContentType == "" {
+			return b
+		}
+	}
+	b.JSON = true
+
+	return b
+}
+
+func isJSON(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// rules reads the matching rules of a request or response. V2 rules are keyed by a
+// single path (e.g. $.body.name or $.headers.Accept), while V3/V4 rules are grouped
+// by category.
+func rules(v interface{}, v2 bool) (map[string]map[string]*matchingrules.Rule, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	result := make(map[string]map[string]*matchingrules.Rule)
+	add := func(category string, key string, rule *matchingrules.Rule) {
+		if result[category] == nil {
+			result[category] = make(map[string]*matchingrules.Rule)
+		}
+		result[category][key] = rule
+	}
+
+	if v2 {
+		for _, path := range sortedKeys(m) {
+			matcher, ok := m[path].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid matching rule for %s", path)
+			}
+			category, key, err := v2RulePath(path)
+			if err != nil {
+				return nil, err
+			}
+			add(category, key, &matchingrules.Rule{Combine: "AND", Matchers: []map[string]interface{}{matcher}})
+		}
+		return result, nil
+	}
+
+	for category, c := range m {
+		entries, ok := c.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s matching rules", category)
+		}
+
+		// The path category has a single rule, rather than a rule per key
+		if _, ok := entries["matchers"]; ok {
+			rule, err := matchingrules.ParseRule(entries)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s matching rule: %w", category, err)
+			}
+			add(category, "", rule)
+			continue
+		}
+
+		for key, r := range entries {
+			entry, _ := r.(map[string]interface{})
+			rule, err := matchingrules.ParseRule(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s matching rule for %s: %w", category, key, err)
+			}
+			add(category, key, rule)
+		}
+	}
+
+	return result, nil
+}
+
+// v2RulePath splits a V2 matching rule path into its category and key, e.g.
+// $.body.name is the body path $.name, and $.headers.Accept is the header Accept
+func v2RulePath(path string) (string, string, error) {
+	segments, err := matchingrules.ParsePath(path)
+	if err != nil {
+		return "", "", err
+	}
+	if len(segments) == 0 {
+		return "", "", fmt.Errorf("invalid matching rule path %s", path)
+	}
+
+	switch segments[0] {
+	case "path":
+		return RulesPath, "", nil
+	case "query":
+		if len(segments) == 2 {
+			return RulesQuery, segments[1], nil
+		}
+	case "header", "headers":
+		if len(segments) == 2 {
+			return RulesHeader, segments[1], nil
+		}
+	case "body":
+		return RulesBody, matchingrules.FormatPath(segments[1:]), nil
+	}
+
+	return "", "", fmt.Errorf("unsupported matching rule path %s", path)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
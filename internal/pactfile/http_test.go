@@ -0,0 +1,107 @@
+package pactfile
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPInteractionsV2(t *testing.T) {
+	interactions, err := HTTPInteractions([]byte(`{
+		"consumer": {"name": "consumer"},
+		"provider": {"name": "provider"},
+		"interactions": [{
+			"description": "a request for users",
+			"providerState": "users exist",
+			"request": {
+				"method": "get",
+				"path": "/users",
+				"query": "page=1&sort=name",
+				"headers": {"Accept": "application/json"},
+				"matchingRules": {"$.query.page": {"match": "regex", "regex": "\\d+"}}
+			},
+			"response": {
+				"status": 200,
+				"headers": {"Content-Type": "application/json"},
+				"body": [{"id": 1}],
+				"matchingRules": {
+					"$.body": {"min": 1, "match": "type"},
+					"$.body[*].id": {"match": "type"},
+					"$.headers.Content-Type": {"match": "regex", "regex": "json"}
+				}
+			}
+		}],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}
+	}`))
+	assert.NoError(t, err)
+	assert.Len(t, interactions, 1)
+
+	i := interactions[0]
+	assert.Equal(t, "a request for users", i.Description)
+	assert.Equal(t, []string{"users exist"}, i.ProviderStates)
+	assert.Equal(t, "GET", i.Request.Method)
+	assert.Equal(t, map[string][]string{"page": {"1"}, "sort": {"name"}}, i.Request.Query)
+	assert.Equal(t, []string{"application/json"}, Header(i.Request.Headers, "accept"))
+	assert.Equal(t, "regex", i.Request.Rules[RulesQuery]["page"].Matchers[0]["match"])
+	assert.Nil(t, i.Request.Body)
+
+	assert.Equal(t, 200, i.Response.Status)
+	assert.True(t, i.Response.Body.JSON)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": json.Number("1")}}, i.Response.Body.Content)
+	assert.Equal(t, map[string]*matchingrules.Rule{
+		"$":       {Combine: "AND", Matchers: []map[string]interface{}{{"match": "type", "min": json.Number("1")}}},
+		"$[*].id": {Combine: "AND", Matchers: []map[string]interface{}{{"match": "type"}}},
+	}, i.Response.Rules[RulesBody])
+	assert.Equal(t, "regex", i.Response.Rules[RulesHeader]["Content-Type"].Matchers[0]["match"])
+}
+
+func TestHTTPInteractionsV4(t *testing.T) {
+	interactions, err := HTTPInteractions([]byte(`{
+		"consumer": {"name": "consumer"},
+		"provider": {"name": "provider"},
+		"interactions": [{
+			"type": "Synchronous/HTTP",
+			"description": "a request to create a user",
+			"providerStates": [{"name": "no users exist"}],
+			"request": {
+				"method": "POST",
+				"path": "/users/1",
+				"query": {"dryRun": ["true"]},
+				"headers": {"Content-Type": ["application/json"]},
+				"body": {"content": {"name": "billy"}, "contentType": "application/json", "encoded": false},
+				"matchingRules": {
+					"path": {"combine": "AND", "matchers": [{"match": "regex", "regex": "^/users/\\d+$"}]},
+					"body": {"$.name": {"combine": "AND", "matchers": [{"match": "type"}]}}
+				}
+			},
+			"response": {
+				"status": 201,
+				"body": {"content": "ok", "contentType": "text/plain", "encoded": false}
+			}
+		}, {
+			"type": "Asynchronous/Messages",
+			"description": "a message"
+		}],
+		"metadata": {"pactSpecification": {"version": "4.0"}}
+	}`))
+	assert.NoError(t, err)
+	assert.Len(t, interactions, 1)
+
+	i := interactions[0]
+	assert.Equal(t, []string{"no users exist"}, i.ProviderStates)
+	assert.Equal(t, map[string][]string{"dryRun": {"true"}}, i.Request.Query)
+	assert.Equal(t, &Body{ContentType: "application/json", JSON: true, Content: map[string]interface{}{"name": "billy"}}, i.Request.Body)
+	assert.Equal(t, "regex", i.Request.Rules[RulesPath][""].Matchers[0]["match"])
+	assert.Equal(t, "type", i.Request.Rules[RulesBody]["$.name"].Matchers[0]["match"])
+	assert.Equal(t, &Body{ContentType: "text/plain", Content: "ok"}, i.Response.Body)
+}
+
+func TestHTTPInteractionsInvalid(t *testing.T) {
+	_, err := HTTPInteractions([]byte(`{`))
+	assert.Error(t, err)
+
+	_, err = HTTPInteractions([]byte(`{"interactions": [{"description": "x", "request": {}}]}`))
+	assert.ErrorContains(t, err, "missing request or response")
+}
@@ -0,0 +1,446 @@
+// Package pactfile merges pact documents into pact files on disk, so that
+// interactions written by concurrently running tests and test processes
+// (e.g. the separate package binaries run by `go test ./...`) are combined
+// deterministically. It also reads the HTTP interactions of pact files of any
+// specification version.
+package pactfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/filelock"
+)
+
+// collections are the top level keys of a pact file that contain interactions.
+// V2/V3 HTTP and all V4 pacts use "interactions", V3 message pacts use "messages".
+var collections = []string{"interactions", "messages"}
+
+// ConflictError is returned when an interaction with the same description and
+// provider state(s), but different content, has already been written to a pact file
+// by the same test run
+type ConflictError struct {
+	// PactFile is the path of the pact file that could not be written
+	PactFile string
+
+	// Description of the conflicting interaction
+	Description string
+
+	// ProviderStates of the conflicting interaction, in their JSON form
+	ProviderStates string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("unable to merge into pact file %s: the interaction %q with provider state(s) %s "+
+		"has already been written with different content by another test or process of this run. "+
+		"Interactions must have a unique description and provider state",
+		e.PactFile, e.Description, e.ProviderStates)
+}
+
+// Mode is how Write combines a pact document with an existing pact file
+type Mode int
+
+const (
+	// MergeMode merges the interactions of the pact into the pact file. Interactions
+	// of the file with different content are replaced if they were written by an
+	// earlier test run, as the Pact core does, and are otherwise a ConflictError.
+	MergeMode Mode = iota
+
+	// UpdateMode merges the interactions of the pact into the pact file, replacing
+	// all interactions of the file that have different content
+	UpdateMode
+
+	// OverwriteMode replaces the pact file
+	OverwriteMode
+)
+
+// Write merges the incoming pact document into the pact file at path, creating it
+// if it doesn't exist, or replaces the file, following the mode.
+//
+// An exclusive lock is held for the duration of the merge, so concurrent writers
+// (in this or other processes) merge one at a time.
+func Write(path string, incoming []byte, mode Mode) error {
+	lock, err := filelock.Acquire(lockPath(path))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			log.Println("[WARN] unable to release pact file lock:", err)
+		}
+	}()
+
+	run := readRun(path)
+
+	merged := incoming
+	if mode != OverwriteMode {
+		existing, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		if len(bytes.TrimSpace(existing)) > 0 {
+			merged, err = merge(existing, incoming, mode == UpdateMode, run.Interactions)
+			if err != nil {
+				var conflict *ConflictError
+				if errors.As(err, &conflict) {
+					conflict.PactFile = path
+				}
+				return err
+			}
+		}
+	}
+
+	log.Println("[DEBUG] writing pact file:", path)
+
+	if err := writeAtomically(path, merged); err != nil {
+		return err
+	}
+
+	return run.record(path, incoming)
+}
+
+// lockPath returns a lock file for the given pact file. Lock files are kept out of
+// the pact directory so they are not mistaken for (or published as) pacts.
+func lockPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	sum := sha256.Sum256([]byte(path))
+
+	return filepath.Join(os.TempDir(), "pact-go-"+hex.EncodeToString(sum[:8])+".lock")
+}
+
+// writeAtomically writes the file via a temporary file, so that readers never see
+// a partially written pact
+func writeAtomically(path string, contents []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".pact-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, err := f.Write(contents); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// run records the interactions written to a pact file by the current test run, so
+// an interaction that has changed since an earlier run can be told apart from one
+// written with different content by two tests of the same run. It is kept beside
+// the lock file, as the package test binaries run by `go test ./...` are separate
+// processes.
+type run struct {
+	// ID identifies the run. The test binaries run by `go test` are all children of
+	// the go command, so it is the process ID of the parent.
+	ID string `json:"id"`
+
+	// Interactions are the contents of the interactions written, by their key
+	Interactions map[string]string `json:"interactions"`
+}
+
+var runID = strconv.Itoa(os.Getppid())
+
+// runPath returns the file recording the current run of the given pact file
+func runPath(path string) string {
+	return strings.TrimSuffix(lockPath(path), ".lock") + ".run.json"
+}
+
+// readRun reads the interactions written to the pact file by the current run
+func readRun(path string) *run {
+	var r run
+	if data, err := os.ReadFile(runPath(path)); err == nil {
+		if err := json.Unmarshal(data, &r); err != nil {
+			log.Println("[WARN] unable to read the interactions written by this test run:", err)
+		}
+	}
+
+	if r.ID != runID || r.Interactions == nil {
+		return &run{ID: runID, Interactions: make(map[string]string)}
+	}
+
+	return &r
+}
+
+// record adds the interactions of the pact document written to the pact file
+func (r *run) record(path string, pact []byte) error {
+	var doc map[string]interface{}
+	if err := decode(pact, &doc); err != nil {
+		return fmt.Errorf("unable to parse pact: %w", err)
+	}
+
+	for _, collection := range collections {
+		interactions, _ := doc[collection].([]interface{})
+		for _, i := range interactions {
+			k, err := keyOf(i)
+			if err != nil {
+				return err
+			}
+			r.Interactions[k.id()] = k.content
+		}
+	}
+
+	data, err := marshal(r, "")
+	if err != nil {
+		return err
+	}
+
+	return writeAtomically(runPath(path), data)
+}
+
+// Merge combines the interactions of two pact documents for the same consumer and
+// provider. Interactions are identified by their type, description and provider
+// state(s); identical interactions are de-duplicated, and a ConflictError is
+// returned if the same interaction has different content in each document.
+//
+// The result is independent of the order documents are merged in: interactions
+// are sorted, and metadata from the incoming document takes precedence.
+func Merge(existing []byte, incoming []byte) ([]byte, error) {
+	return merge(existing, incoming, false, nil)
+}
+
+// Update combines two pact documents as Merge does, except that interactions of the
+// existing document with different content are replaced by the incoming ones
+func Update(existing []byte, incoming []byte) ([]byte, error) {
+	return merge(existing, incoming, true, nil)
+}
+
+// merge combines two pact documents. If written is not nil, only the interactions
+// of the existing document in written (by their key and content) are a conflict.
+func merge(existing []byte, incoming []byte, update bool, written map[string]string) ([]byte, error) {
+	var a, b map[string]interface{}
+
+	if err := decode(existing, &a); err != nil {
+		return nil, fmt.Errorf("unable to parse existing pact file: %w", err)
+	}
+	if err := decode(incoming, &b); err != nil {
+		return nil, fmt.Errorf("unable to parse pact: %w", err)
+	}
+
+	for _, pacticipant := range []string{"consumer", "provider"} {
+		if nameOf(a[pacticipant]) != nameOf(b[pacticipant]) {
+			return nil, fmt.Errorf("unable to merge pacts: %s %q does not match %q", pacticipant, nameOf(a[pacticipant]), nameOf(b[pacticipant]))
+		}
+	}
+
+	if va, vb := specificationVersion(a), specificationVersion(b); va != "" && vb != "" && va != vb {
+		return nil, fmt.Errorf("unable to merge pacts: existing pact file is specification version %s, but the pact is version %s", va, vb)
+	}
+
+	merged := mergeMaps(a, b)
+
+	for _, collection := range collections {
+		existingInteractions, _ := a[collection].([]interface{})
+		incomingInteractions, _ := b[collection].([]interface{})
+
+		if existingInteractions == nil && incomingInteractions == nil {
+			continue
+		}
+
+		interactions, err := mergeInteractions(existingInteractions, incomingInteractions, update, written)
+		if err != nil {
+			return nil, err
+		}
+		merged[collection] = interactions
+	}
+
+	return encode(merged)
+}
+
+func decode(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	return d.Decode(v)
+}
+
+func encode(v interface{}) ([]byte, error) {
+	return marshal(v, "  ")
+}
+
+// marshal encodes v as JSON without escaping HTML characters, which would
+// otherwise change the content of bodies and matching rules
+func marshal(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", indent)
+
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func nameOf(pacticipant interface{}) string {
+	if m, ok := pacticipant.(map[string]interface{}); ok {
+		name, _ := m["name"].(string)
+		return name
+	}
+
+	return ""
+}
+
+func specificationVersion(pact map[string]interface{}) string {
+	metadata, _ := pact["metadata"].(map[string]interface{})
+	for _, key := range []string{"pactSpecification", "pact-specification"} {
+		if spec, ok := metadata[key].(map[string]interface{}); ok {
+			version, _ := spec["version"].(string)
+			return version
+		}
+	}
+
+	return ""
+}
+
+// mergeMaps deep merges two JSON objects, with values from b taking precedence
+func mergeMaps(a map[string]interface{}, b map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		merged[k] = v
+	}
+
+	for k, v := range b {
+		am, aok := merged[k].(map[string]interface{})
+		bm, bok := v.(map[string]interface{})
+		if aok && bok {
+			merged[k] = mergeMaps(am, bm)
+		} else {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+type keyedInteraction struct {
+	description string
+	states      string
+	kind        string
+	content     string
+	interaction interface{}
+
+	// existing is true if the interaction is from the existing pact file
+	existing bool
+}
+
+// id is the key of the interaction, by which interactions are merged
+func (k keyedInteraction) id() string {
+	return k.kind + "\x00" + k.description + "\x00" + k.states
+}
+
+// mergeInteractions de-duplicates the interactions by their key. Incoming
+// interactions replace existing interactions with different content if update is
+// true, or if they were written by an earlier run (i.e. written is not nil, and does
+// not have them), and are otherwise a ConflictError.
+func mergeInteractions(existing []interface{}, incoming []interface{}, update bool, written map[string]string) ([]interface{}, error) {
+	byKey := make(map[string]keyedInteraction)
+
+	for n, i := range append(append([]interface{}{}, existing...), incoming...) {
+		k, err := keyOf(i)
+		if err != nil {
+			return nil, err
+		}
+		id := k.id()
+
+		if previous, ok := byKey[id]; ok && previous.content != k.content {
+			_, thisRun := written[id]
+			earlierRun := written != nil && previous.existing && !thisRun
+			if replacing := n >= len(existing) && (update || earlierRun); !replacing {
+				return nil, &ConflictError{
+					Description:    k.description,
+					ProviderStates: k.states,
+				}
+			}
+		}
+		k.existing = n < len(existing)
+		byKey[id] = k
+	}
+
+	keyed := make([]keyedInteraction, 0, len(byKey))
+	for _, k := range byKey {
+		keyed = append(keyed, k)
+	}
+	sort.Slice(keyed, func(i, j int) bool {
+		if keyed[i].description != keyed[j].description {
+			return keyed[i].description < keyed[j].description
+		}
+		if keyed[i].states != keyed[j].states {
+			return keyed[i].states < keyed[j].states
+		}
+		return keyed[i].kind < keyed[j].kind
+	})
+
+	interactions := make([]interface{}, len(keyed))
+	for i, k := range keyed {
+		interactions[i] = k.interaction
+	}
+
+	return interactions, nil
+}
+
+// keyOf identifies an interaction, and captures its content for comparison
+func keyOf(interaction interface{}) (keyedInteraction, error) {
+	m, ok := interaction.(map[string]interface{})
+	if !ok {
+		return keyedInteraction{}, fmt.Errorf("unable to merge pacts: invalid interaction %v", interaction)
+	}
+
+	description, _ := m["description"].(string)
+	kind, _ := m["type"].(string)
+
+	var states interface{} = []interface{}{}
+	if s, ok := m["providerStates"]; ok {
+		states = s
+	} else if s, ok := m["providerState"]; ok {
+		states = s
+	}
+	statesJSON, err := marshal(states, "")
+	if err != nil {
+		return keyedInteraction{}, err
+	}
+
+	// The V4 interaction key is a hash of the content generated by the core, and
+	// may vary between versions of the core for otherwise identical interactions
+	comparable := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != "key" {
+			comparable[k] = v
+		}
+	}
+	content, err := marshal(comparable, "")
+	if err != nil {
+		return keyedInteraction{}, err
+	}
+
+	return keyedInteraction{
+		description: description,
+		states:      string(bytes.TrimSpace(statesJSON)),
+		kind:        kind,
+		content:     string(content),
+		interaction: interaction,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package pactfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pact(interactions ...string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"consumer": {"name": "consumer"},
+		"provider": {"name": "provider"},
+		"interactions": [%s],
+		"metadata": {"pactSpecification": {"version": "4.0"}, "pactRust": {"ffi": "0.4.0"}}
+	}`, joinInteractions(interactions)))
+}
+
+func joinInteractions(interactions []string) string {
+	out := ""
+	for i, interaction := range interactions {
+		if i > 0 {
+			out += ","
+		}
+		out += interaction
+	}
+	return out
+}
+
+func interaction(description string, state string, status int) string {
+	return fmt.Sprintf(`{
+		"type": "Synchronous/HTTP",
+		"key": "%s-%d",
+		"description": %q,
+		"providerStates": [{"name": %q}],
+		"request": {"method": "GET", "path": "/"},
+		"response": {"status": %d}
+	}`, description, status, description, state, status)
+}
+
+func descriptions(t *testing.T, data []byte) []string {
+	var p struct {
+		Interactions []struct {
+			Description string `json:"description"`
+		} `json:"interactions"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &p))
+
+	var out []string
+	for _, i := range p.Interactions {
+		out = append(out, i.Description)
+	}
+	return out
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("combines and sorts interactions", func(t *testing.T) {
+		merged, err := Merge(pact(interaction("b", "", 200)), pact(interaction("a", "", 200)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, descriptions(t, merged))
+	})
+
+	t.Run("is independent of merge order", func(t *testing.T) {
+		a := pact(interaction("a", "", 200), interaction("c", "", 200))
+		b := pact(interaction("b", "", 200))
+
+		ab, err := Merge(a, b)
+		assert.NoError(t, err)
+		ba, err := Merge(b, a)
+		assert.NoError(t, err)
+
+		assert.JSONEq(t, string(ab), string(ba))
+	})
+
+	t.Run("de-duplicates identical interactions", func(t *testing.T) {
+		merged, err := Merge(pact(interaction("a", "s", 200)), pact(interaction("a", "s", 200)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, descriptions(t, merged))
+	})
+
+	t.Run("ignores the interaction key when comparing", func(t *testing.T) {
+		a := pact(`{"key": "1", "description": "a", "response": {"status": 200}}`)
+		b := pact(`{"key": "2", "description": "a", "response": {"status": 200}}`)
+		_, err := Merge(a, b)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("keeps interactions with the same description but different states", func(t *testing.T) {
+		merged, err := Merge(pact(interaction("a", "s1", 200)), pact(interaction("a", "s2", 404)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "a"}, descriptions(t, merged))
+	})
+
+	t.Run("returns a conflict for the same interaction with different content", func(t *testing.T) {
+		_, err := Merge(pact(interaction("a", "s", 200)), pact(interaction("a", "s", 404)))
+
+		var conflict *ConflictError
+		assert.True(t, errors.As(err, &conflict))
+		assert.Equal(t, "a", conflict.Description)
+		assert.Equal(t, `[{"name":"s"}]`, conflict.ProviderStates)
+	})
+
+	t.Run("merges V3 message pacts", func(t *testing.T) {
+		a := `{"consumer": {"name": "c"}, "provider": {"name": "p"}, "messages": [{"description": "b", "contents": {}}]}`
+		b := `{"consumer": {"name": "c"}, "provider": {"name": "p"}, "messages": [{"description": "a", "contents": {}}]}`
+		merged, err := Merge([]byte(a), []byte(b))
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(merged), `"messages"`)
+		assert.NotContains(t, string(merged), `"interactions"`)
+	})
+
+	t.Run("rejects pacts for different pacticipants", func(t *testing.T) {
+		other := []byte(`{"consumer": {"name": "other"}, "provider": {"name": "provider"}, "interactions": []}`)
+		_, err := Merge(pact(), other)
+
+		assert.ErrorContains(t, err, `consumer "consumer" does not match "other"`)
+	})
+
+	t.Run("rejects pacts with different specification versions", func(t *testing.T) {
+		v2 := []byte(`{"consumer": {"name": "consumer"}, "provider": {"name": "provider"}, "interactions": [], "metadata": {"pactSpecification": {"version": "2.0.0"}}}`)
+		_, err := Merge(pact(), v2)
+
+		assert.ErrorContains(t, err, "specification version")
+	})
+
+	t.Run("preserves numbers", func(t *testing.T) {
+		merged, err := Merge(pact(), pact(`{"description": "a", "response": {"body": {"id": 12345678901234567890}}}`))
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(merged), "12345678901234567890")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("creates the pact file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pacts", "consumer-provider.json")
+
+		assert.NoError(t, Write(path, pact(interaction("a", "", 200)), MergeMode))
+		contents, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, descriptions(t, contents))
+	})
+
+	t.Run("merges into an existing pact file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+		assert.NoError(t, Write(path, pact(interaction("a", "", 200)), MergeMode))
+		assert.NoError(t, Write(path, pact(interaction("b", "", 200)), MergeMode))
+
+		contents, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, descriptions(t, contents))
+	})
+
+	t.Run("replaces the pact file when overwriting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+		assert.NoError(t, Write(path, pact(interaction("a", "", 200)), MergeMode))
+		assert.NoError(t, Write(path, pact(interaction("b", "", 200)), OverwriteMode))
+
+		contents, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b"}, descriptions(t, contents))
+	})
+
+	t.Run("replaces changed interactions when updating", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+		assert.NoError(t, Write(path, pact(interaction("a", "s", 200), interaction("b", "", 200)), MergeMode))
+		assert.NoError(t, Write(path, pact(interaction("a", "s", 500)), UpdateMode))
+
+		contents, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, descriptions(t, contents))
+		assert.Equal(t, 1, strings.Count(string(contents), `"status": 500`))
+		assert.Equal(t, 1, strings.Count(string(contents), `"status": 200`))
+	})
+
+	t.Run("reports the pact file in conflicts", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+		assert.NoError(t, Write(path, pact(interaction("a", "s", 200)), MergeMode))
+		err := Write(path, pact(interaction("a", "s", 500)), MergeMode)
+
+		var conflict *ConflictError
+		assert.True(t, errors.As(err, &conflict))
+		assert.Equal(t, path, conflict.PactFile)
+		assert.Contains(t, err.Error(), path)
+	})
+
+	t.Run("replaces interactions written by an earlier run", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+		defer func(id string) { runID = id }(runID)
+		runID = "earlier"
+		assert.NoError(t, Write(path, pact(interaction("a", "s", 200), interaction("b", "", 200)), MergeMode))
+
+		runID = "later"
+		assert.NoError(t, Write(path, pact(interaction("a", "s", 500)), MergeMode))
+		err := Write(path, pact(interaction("a", "s", 404)), MergeMode)
+		assert.True(t, errors.As(err, new(*ConflictError)))
+
+		contents, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, descriptions(t, contents))
+		assert.Equal(t, 1, strings.Count(string(contents), `"status": 500`))
+	})
+
+	t.Run("concurrent writers do not lose interactions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, Write(path, pact(interaction(fmt.Sprintf("%02d", i), "", 200)), MergeMode))
+			}(i)
+		}
+		wg.Wait()
+
+		contents, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Len(t, descriptions(t, contents), 20)
+	})
+}
@@ -0,0 +1,78 @@
+// Package runner runs consumer integration tests, bounding them by a context.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// TimeoutError is returned when the context of a test expires (or is cancelled)
+// before its integration test completes
+type TimeoutError struct {
+	// TestName is the name of the test that did not complete
+	TestName string
+
+	// Err is the context error, context.DeadlineExceeded or context.Canceled
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if errors.Is(e.Err, context.Canceled) {
+		return fmt.Sprintf("pact test %s was cancelled before the integration test completed", e.TestName)
+	}
+
+	return fmt.Sprintf("pact test %s timed out before the integration test completed: %v", e.TestName, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Run executes the integration test, returning a TimeoutError as soon as the
+// context expires. The test is passed the context, and should use it to abandon
+// any outstanding work, as it is left running in the background on expiry.
+//
+// Contexts that can never expire (e.g. context.Background()) run the test
+// synchronously, so that it may safely call t.FailNow and friends. Otherwise the test
+// runs in its own goroutine, where t.FailNow (or t.Fatal, require.*, etc.) exits the
+// goroutine without returning; Run then returns an error, so the test is not treated
+// as having passed.
+func Run(ctx context.Context, testName string, integrationTest func(context.Context) error) error {
+	if ctx.Done() == nil {
+		return integrationTest(ctx)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return &TimeoutError{TestName: testName, Err: err}
+	}
+
+	var err error
+	completed := false
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		err = integrationTest(ctx)
+		completed = true
+	}()
+
+	select {
+	case <-finished:
+		if !completed {
+			return fmt.Errorf("the integration test of %s exited without returning, e.g. by calling t.FailNow", testName)
+		}
+
+		// The test may have given up because of the context, e.g. an HTTP
+		// client returning a wrapped context.DeadlineExceeded
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return &TimeoutError{TestName: testName, Err: ctxErr}
+		}
+
+		return err
+	case <-ctx.Done():
+		log.Println("[WARN] integration test did not complete before the context expired:", testName)
+
+		return &TimeoutError{TestName: testName, Err: ctx.Err()}
+	}
+}
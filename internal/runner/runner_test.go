@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("returns the result of the test", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := Run(ctx, "test", func(ctx context.Context) error {
+			return errors.New("failed")
+		})
+
+		assert.EqualError(t, err, "failed")
+	})
+
+	t.Run("runs synchronously for contexts that can't expire", func(t *testing.T) {
+		ran := false
+		err := Run(context.Background(), "test", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, ran)
+	})
+
+	t.Run("returns an error when the test exits its goroutine", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		err := Run(ctx, "test", func(ctx context.Context) error {
+			runtime.Goexit()
+			return nil
+		})
+
+		assert.ErrorContains(t, err, "exited without returning")
+	})
+
+	t.Run("returns a timeout when the deadline passes", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		release := make(chan struct{})
+		defer close(release)
+
+		err := Run(ctx, "hung", func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		var timeout *TimeoutError
+		assert.True(t, errors.As(err, &timeout))
+		assert.Equal(t, "hung", timeout.TestName)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "timed out")
+	})
+
+	t.Run("returns a timeout when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := Run(ctx, "cancelled", func(ctx context.Context) error {
+			cancel()
+			<-ctx.Done()
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, err.Error(), "cancelled")
+	})
+
+	t.Run("treats errors caused by the context as a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := Run(ctx, "test", func(ctx context.Context) error {
+			<-ctx.Done()
+			return fmt.Errorf("request failed: %w", ctx.Err())
+		})
+
+		var timeout *TimeoutError
+		assert.True(t, errors.As(err, &timeout))
+	})
+
+	t.Run("does not run the test if the context has already expired", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ran := false
+		err := Run(ctx, "test", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, ran)
+	})
+}
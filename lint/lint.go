@@ -0,0 +1,325 @@
+// Package lint checks pacts for contract smells: interactions that are likely to make
+// verification brittle, or the intent of the contract unclear.
+//
+// Each rule reports findings at a severity, which may be changed (or the rule turned
+// off) by a Config. A check fails if it has findings at or above the FailOn severity
+// of the configuration.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity of a finding
+type Severity string
+
+const (
+	// Off turns a rule off
+	Off     Severity = "off"
+	Info    Severity = "info"
+	Warning Severity = "warning"
+	Error   Severity = "error"
+)
+
+var severities = map[Severity]int{
+	Off:     0,
+	Info:    1,
+	Warning: 2,
+	Error:   3,
+}
+
+// ParseSeverity reads a severity, i.e. off, info, warning or error
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severities[severity]; !ok {
+		return "", fmt.Errorf("invalid severity %q, expected one of off, info, warning or error", s)
+	}
+
+	return severity, nil
+}
+
+// Rule is a check of a pact
+type Rule struct {
+	// Name identifies the rule in a Config, e.g. exact-body
+	Name string
+
+	Description string
+
+	// Severity is the default severity of the rule's findings
+	Severity Severity
+
+	check func(pact *models.PactFile) []Finding
+}
+
+// Rules returns the rules that pacts are checked with
+func Rules() []Rule {
+	return []Rule{
+		{
+			Name:        "exact-body",
+			Description: "response bodies and message contents matched exactly, without any matchers",
+			Severity:    Warning,
+			check:       exactBodies,
+		},
+		{
+			Name:        "duplicate-description",
+			Description: "descriptions and provider states used by more than one interaction",
+			Severity:    Error,
+			check:       duplicateDescriptions,
+		},
+		{
+			Name:        "mutating-without-state",
+			Description: "POST, PUT, PATCH and DELETE requests without a provider state",
+			Severity:    Warning,
+			check:       mutatingWithoutState,
+		},
+		{
+			Name:        "timestamp-without-matcher",
+			Description: "dates and times matched exactly, without a date, time or regex matcher",
+			Severity:    Error,
+			check:       timestampsWithoutMatchers,
+		},
+	}
+}
+
+// Config sets the severity of the rules, and the severity at which a check fails. The
+// zero value checks every rule at its default severity, and fails on errors.
+type Config struct {
+	// Rules sets the severity of rules by name. Rules that are not given have their
+	// default severity.
+	Rules map[string]Severity `yaml:"rules" json:"rules"`
+
+	// FailOn is the lowest severity that fails a check. Defaults to error.
+	FailOn Severity `yaml:"failOn" json:"failOn"`
+}
+
+// LoadConfig reads a configuration from a YAML or JSON file, e.g.
+//
+//	failOn: warning
+//	rules:
+//	  exact-body: off
+//	  mutating-without-state: error
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("unable to read lint configuration %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid lint configuration %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	known := make(map[string]bool)
+	for _, r := range Rules() {
+		known[r.Name] = true
+	}
+
+	for _, name := range sortedKeys(c.Rules) {
+		if !known[name] {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+		if _, err := ParseSeverity(string(c.Rules[name])); err != nil {
+			return fmt.Errorf("rule %s: %w", name, err)
+		}
+	}
+	if c.FailOn != "" {
+		if _, err := ParseSeverity(string(c.FailOn)); err != nil {
+			return fmt.Errorf("failOn: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) failOn() Severity {
+	if c.FailOn == "" {
+		return Error
+	}
+
+	return Severity(strings.ToLower(string(c.FailOn)))
+}
+
+// Finding is a smell found in a pact
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+
+	// Pact is the path of the pact file, if the pact was read from a file
+	Pact string `json:"pact,omitempty"`
+
+	// Interaction is the description of the interaction
+	Interaction string `json:"interaction,omitempty"`
+
+	// Location is the part of the interaction, e.g. request.method,
+	// response.header.Date or response.body.$.createdAt
+	Location string `json:"location,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	parts := []string{strings.ToUpper(string(f.Severity)), f.Rule}
+	for _, p := range []string{f.Pact, f.Interaction, f.Location} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return fmt.Sprintf("%s %s: %s", parts[0], strings.Join(parts[1:], ": "), f.Message)
+}
+
+// Report is the result of checking pacts
+type Report struct {
+	Findings []Finding `json:"findings"`
+
+	// FailOn is the lowest severity that fails the check
+	FailOn Severity `json:"failOn"`
+}
+
+// Failed reports whether there are any findings at or above the FailOn severity
+func (r *Report) Failed() bool {
+	return len(r.Failures()) > 0
+}
+
+// Failures returns the findings at or above the FailOn severity
+func (r *Report) Failures() []Finding {
+	failures := make([]Finding, 0)
+	for _, f := range r.Findings {
+		if severities[f.Severity] >= severities[r.FailOn] && r.FailOn != Off {
+			failures = append(failures, f)
+		}
+	}
+
+	return failures
+}
+
+// FailedError is returned when a pact fails the check
+type FailedError struct {
+	Failures []Finding
+}
+
+func (e *FailedError) Error() string {
+	lines := []string{fmt.Sprintf("the pact has %d contract smell(s) that fail the lint check:", len(e.Failures))}
+	for _, f := range e.Failures {
+		lines = append(lines, "  "+f.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Err returns a *FailedError if the check failed, and nil otherwise
+func (r *Report) Err() error {
+	if failures := r.Failures(); len(failures) > 0 {
+		return &FailedError{Failures: failures}
+	}
+
+	return nil
+}
+
+// Check checks the pact with the rules of the configuration, which may be nil to use
+// the default severities
+func Check(pact *models.PactFile, config *Config) (*Report, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Findings: make([]Finding, 0),
+		FailOn:   config.failOn(),
+	}
+	for _, rule := range Rules() {
+		severity := rule.Severity
+		if s, ok := config.Rules[rule.Name]; ok {
+			severity, _ = ParseSeverity(string(s))
+		}
+		if severity == Off {
+			continue
+		}
+
+		for _, f := range rule.check(pact) {
+			f.Rule = rule.Name
+			f.Severity = severity
+			report.Findings = append(report.Findings, f)
+		}
+	}
+
+	return report, nil
+}
+
+// CheckFiles checks the pact files at the paths, which may also be directories of pact
+// files (*.json), with the rules of the configuration
+func CheckFiles(paths []string, config *Config) (*Report, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	report := &Report{
+		Findings: make([]Finding, 0),
+		FailOn:   config.failOn(),
+	}
+	for _, file := range files {
+		pact, err := models.Load(file)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := Check(pact, config)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range r.Findings {
+			f.Pact = file
+			report.Findings = append(report.Findings, f)
+		}
+	}
+
+	return report, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
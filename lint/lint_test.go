@@ -0,0 +1,191 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, pact string) *models.PactFile {
+	p, err := models.ParsePactFile([]byte(pact))
+	assert.NoError(t, err)
+
+	return p
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		pact string
+		want []Finding
+	}{
+		{
+			name: "no smells",
+			pact: `{"interactions": [{
+				"description": "a request to create a user", "providerStates": [{"name": "no users"}],
+				"request": {"method": "POST", "path": "/users", "body": {"name": "billy"}},
+				"response": {"status": 201, "body": {"id": 1, "createdAt": "2024-01-02T03:04:05Z"},
+				  "matchingRules": {"body": {"$.id": {"matchers": [{"match": "integer"}]}, "$.createdAt": {"matchers": [{"match": "timestamp", "timestamp": "yyyy-MM-dd'T'HH:mm:ssX"}]}}}}
+			}], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+		},
+		{
+			name: "exact body",
+			pact: `{"interactions": [
+				{"description": "a request for a user", "request": {"method": "GET", "path": "/users/1"}, "response": {"status": 200, "body": {"id": 1}}},
+				{"description": "a request for a name", "request": {"method": "GET", "path": "/name"}, "response": {"status": 200, "body": "billy"}}
+			], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+			want: []Finding{
+				{Rule: "exact-body", Severity: Warning, Interaction: "a request for a user", Location: "response.body", Message: "the body has no matchers, so the provider must return exactly this example"},
+			},
+		},
+		{
+			name: "duplicate description",
+			pact: `{"interactions": [
+				{"description": "a request", "providerStates": [{"name": "a user"}], "request": {"method": "GET", "path": "/users/1"}, "response": {"status": 200}},
+				{"description": "a request", "providerStates": [{"name": "a user"}], "request": {"method": "GET", "path": "/users/2"}, "response": {"status": 404}}
+			], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+			want: []Finding{
+				{Rule: "duplicate-description", Severity: Error, Interaction: "a request", Message: "the description and provider state(s) are used by 2 interactions, so they cannot be told apart"},
+			},
+		},
+		{
+			name: "same description with different states",
+			pact: `{"interactions": [
+				{"description": "a request for a user", "providerStates": [{"name": "a user exists"}], "request": {"method": "GET", "path": "/users/1"}, "response": {"status": 200}},
+				{"description": "a request for a user", "providerStates": [{"name": "no users exist"}], "request": {"method": "GET", "path": "/users/1"}, "response": {"status": 404}}
+			], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+		},
+		{
+			name: "mutating without state",
+			pact: `{"interactions": [
+				{"description": "a request to delete a user", "request": {"method": "delete", "path": "/users/1"}, "response": {"status": 204}},
+				{"description": "a request for users", "request": {"method": "GET", "path": "/users"}, "response": {"status": 200}}
+			], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+			want: []Finding{
+				{Rule: "mutating-without-state", Severity: Warning, Interaction: "a request to delete a user", Location: "request.method", Message: "the DELETE request has no provider state"},
+			},
+		},
+		{
+			name: "timestamps without matchers",
+			pact: `{"interactions": [{
+				"description": "a request for events", "providerStates": [{"name": "events"}],
+				"request": {"method": "GET", "path": "/events", "query": {"since": ["2024-01-02"]}, "headers": {"If-Modified-Since": "Tue, 02 Jan 2024 03:04:05 GMT"},
+				  "matchingRules": {"header": {"if-modified-since": {"matchers": [{"match": "regex", "regex": ".+"}]}}}},
+				"response": {"status": 200, "headers": {"Date": "Tue, 02 Jan 2024 03:04:05 GMT"},
+				  "body": {"events": [{"at": "2024-01-02T03:04:05.123+10:00", "name": "2024"}], "updated": {"at": "2024-01-02 03:04"}},
+				  "matchingRules": {"body": {"$.events": {"matchers": [{"match": "type"}]}}}}
+			}], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+			want: []Finding{
+				{Rule: "timestamp-without-matcher", Severity: Error, Interaction: "a request for events", Location: "request.query.since", Message: "the timestamp has no matcher, so only this exact value matches"},
+				{Rule: "timestamp-without-matcher", Severity: Error, Interaction: "a request for events", Location: "response.header.Date", Message: "the timestamp has no matcher, so only this exact value matches"},
+				{Rule: "timestamp-without-matcher", Severity: Error, Interaction: "a request for events", Location: "response.body.$.updated.at", Message: "the timestamp has no matcher, so only this exact value matches"},
+			},
+		},
+		{
+			name: "messages",
+			pact: `{"messages": [
+				{"description": "a user event", "contents": {"id": 1}, "metadata": {"sentAt": "2024-01-02T03:04:05Z"}}
+			], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`,
+			want: []Finding{
+				{Rule: "exact-body", Severity: Warning, Interaction: "a user event", Location: "message.contents", Message: "the body has no matchers, so the provider must return exactly this example"},
+				{Rule: "timestamp-without-matcher", Severity: Error, Interaction: "a user event", Location: "message.metadata.sentAt", Message: "the timestamp has no matcher, so only this exact value matches"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Check(parse(t, tt.pact), nil)
+			assert.NoError(t, err)
+
+			if tt.want == nil {
+				tt.want = []Finding{}
+			}
+			assert.Equal(t, tt.want, report.Findings)
+		})
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	pact := parse(t, `{"interactions": [
+		{"description": "a request to create a user", "request": {"method": "POST", "path": "/users"}, "response": {"status": 201, "body": {"id": 1}}}
+	], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`)
+
+	report, err := Check(pact, nil)
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 2)
+	assert.False(t, report.Failed())
+	assert.NoError(t, report.Err())
+
+	report, err = Check(pact, &Config{FailOn: "Warning", Rules: map[string]Severity{"exact-body": Off}})
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 1)
+	assert.True(t, report.Failed())
+	assert.Equal(t, "WARNING mutating-without-state: a request to create a user: request.method: the POST request has no provider state", report.Findings[0].String())
+
+	var failed *FailedError
+	assert.ErrorAs(t, report.Err(), &failed)
+	assert.Len(t, failed.Failures, 1)
+
+	report, err = Check(pact, &Config{Rules: map[string]Severity{"mutating-without-state": Error}})
+	assert.NoError(t, err)
+	assert.Len(t, report.Failures(), 1)
+
+	report, err = Check(pact, &Config{FailOn: Off, Rules: map[string]Severity{"mutating-without-state": Error}})
+	assert.NoError(t, err)
+	assert.False(t, report.Failed())
+
+	_, err = Check(pact, &Config{Rules: map[string]Severity{"unknown": Error}})
+	assert.EqualError(t, err, `unknown rule "unknown"`)
+
+	_, err = Check(pact, &Config{Rules: map[string]Severity{"exact-body": "fatal"}})
+	assert.Error(t, err)
+
+	_, err = Check(pact, &Config{FailOn: "fatal"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lint.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("failOn: warning\nrules:\n  exact-body: off\n  mutating-without-state: error\n"), 0644))
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{
+		FailOn: Warning,
+		Rules:  map[string]Severity{"exact-body": Off, "mutating-without-state": Error},
+	}, config)
+
+	assert.NoError(t, os.WriteFile(path, []byte("rules:\n  unknown: off\n"), 0644))
+	_, err = LoadConfig(path)
+	assert.Error(t, err)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestCheckFiles(t *testing.T) {
+	dir := t.TempDir()
+	pact := `{"interactions": [{"description": "a request", "request": {"method": "PUT", "path": "/"}, "response": {"status": 200}}]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(pact), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(pact), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a pact"), 0644))
+
+	report, err := CheckFiles([]string{dir}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 2)
+	assert.Equal(t, filepath.Join(dir, "a.json"), report.Findings[0].Pact)
+	assert.Equal(t, Error, report.FailOn)
+
+	report, err = CheckFiles([]string{filepath.Join(dir, "b.json")}, &Config{FailOn: Warning})
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 1)
+	assert.True(t, report.Failed())
+
+	_, err = CheckFiles([]string{filepath.Join(dir, "missing.json")}, nil)
+	assert.Error(t, err)
+}
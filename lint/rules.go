@@ -0,0 +1,242 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// exactBodies reports JSON response bodies and message contents without any matching
+// rules, which the provider must reproduce exactly
+func exactBodies(pact *models.PactFile) []Finding {
+	var findings []Finding
+	check := func(i models.Interaction, location string, body *models.Body, rules models.MatchingRules) {
+		switch content(body).(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			return
+		}
+		if len(rules[models.CategoryBody]) > 0 {
+			return
+		}
+		findings = append(findings, Finding{
+			Interaction: i.Details().Description,
+			Location:    location,
+			Message:     "the body has no matchers, so the provider must return exactly this example",
+		})
+	}
+
+	for _, i := range pact.Interactions {
+		switch t := i.(type) {
+		case *models.HTTPInteraction:
+			check(i, "response.body", t.Response.Body, t.Response.MatchingRules)
+		case *models.AsynchronousMessage:
+			check(i, "message.contents", t.Contents, t.MatchingRules)
+		case *models.SynchronousMessage:
+			for n, m := range t.Response {
+				check(i, fmt.Sprintf("response[%d].contents", n), m.Contents, m.MatchingRules)
+			}
+		}
+	}
+
+	return findings
+}
+
+// duplicateDescriptions reports interactions with the same description and provider
+// states as an earlier interaction of the pact. Interactions are identified by these
+// (and their type), as when pact files are merged, so interactions with the same
+// description but different provider states are not duplicates.
+func duplicateDescriptions(pact *models.PactFile) []Finding {
+	key := func(i models.Interaction) string {
+		states, _ := json.Marshal(i.Details().ProviderStates)
+		return i.Type() + "\x00" + i.Details().Description + "\x00" + string(states)
+	}
+
+	counts := make(map[string]int)
+	for _, i := range pact.Interactions {
+		counts[key(i)]++
+	}
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	for _, i := range pact.Interactions {
+		k := key(i)
+		if !seen[k] {
+			seen[k] = true
+			continue
+		}
+		findings = append(findings, Finding{
+			Interaction: i.Details().Description,
+			Message:     fmt.Sprintf("the description and provider state(s) are used by %d interactions, so they cannot be told apart", counts[k]),
+		})
+	}
+
+	return findings
+}
+
+var mutatingMethods = map[string]bool{
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// mutatingWithoutState reports requests that change the state of the provider, but do
+// not say what state it must be in first
+func mutatingWithoutState(pact *models.PactFile) []Finding {
+	var findings []Finding
+	for _, i := range pact.Interactions {
+		h, ok := i.(*models.HTTPInteraction)
+		if !ok || !mutatingMethods[strings.ToUpper(h.Request.Method)] || len(h.ProviderStates) > 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Interaction: h.Description,
+			Location:    "request.method",
+			Message:     fmt.Sprintf("the %s request has no provider state", strings.ToUpper(h.Request.Method)),
+		})
+	}
+
+	return findings
+}
+
+var timestamps = []*regexp.Regexp{
+	// ISO 8601 dates, and date-times
+	regexp.MustCompile(`^\d{4}-\d{2}-\d{2}([T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?)?$`),
+	// HTTP dates, e.g. Tue, 15 Nov 1994 08:12:31 GMT
+	regexp.MustCompile(`^[A-Z][a-z]{2}, \d{2} [A-Z][a-z]{2} \d{4} \d{2}:\d{2}:\d{2} GMT$`),
+}
+
+func isTimestamp(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	for _, r := range timestamps {
+		if r.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// timestampsWithoutMatchers reports dates and times that are matched by equality, and
+// so only match the example (e.g. the time the consumer test was written)
+func timestampsWithoutMatchers(pact *models.PactFile) []Finding {
+	var findings []Finding
+	for _, i := range pact.Interactions {
+		var locations []string
+		switch t := i.(type) {
+		case *models.HTTPInteraction:
+			locations = append(locations, valueTimestamps("request.query", t.Request.Query, t.Request.MatchingRules[models.CategoryQuery], false)...)
+			locations = append(locations, valueTimestamps("request.header", t.Request.Headers, t.Request.MatchingRules[models.CategoryHeader], true)...)
+			locations = append(locations, bodyTimestamps("request.body", t.Request.Body, t.Request.MatchingRules)...)
+			locations = append(locations, valueTimestamps("response.header", t.Response.Headers, t.Response.MatchingRules[models.CategoryHeader], true)...)
+			locations = append(locations, bodyTimestamps("response.body", t.Response.Body, t.Response.MatchingRules)...)
+		case *models.AsynchronousMessage:
+			locations = append(locations, messageTimestamps("message", t.Message)...)
+		case *models.SynchronousMessage:
+			locations = append(locations, messageTimestamps("request", t.Request)...)
+			for n, m := range t.Response {
+				locations = append(locations, messageTimestamps(fmt.Sprintf("response[%d]", n), m)...)
+			}
+		}
+
+		for _, location := range locations {
+			findings = append(findings, Finding{
+				Interaction: i.Details().Description,
+				Location:    location,
+				Message:     "the timestamp has no matcher, so only this exact value matches",
+			})
+		}
+	}
+
+	return findings
+}
+
+func messageTimestamps(prefix string, m models.Message) []string {
+	metadata := make(map[string][]interface{}, len(m.Metadata))
+	for k, v := range m.Metadata {
+		metadata[k] = []interface{}{v}
+	}
+
+	return append(valueTimestamps(prefix+".metadata", metadata, m.MatchingRules[models.CategoryMetadata], false),
+		bodyTimestamps(prefix+".contents", m.Contents, m.MatchingRules)...)
+}
+
+// valueTimestamps returns the locations of the named values (headers, query parameters
+// or metadata) that are timestamps without a rule
+func valueTimestamps[V any](prefix string, values map[string][]V, rules map[string]*models.MatchingRule, ignoreCase bool) []string {
+	hasRule := func(name string) bool {
+		for k, r := range rules {
+			if (k == name || (ignoreCase && strings.EqualFold(k, name))) && r != nil && len(r.Matchers) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	var locations []string
+	for _, name := range sortedKeys(values) {
+		for _, v := range values[name] {
+			if isTimestamp(v) && !hasRule(name) {
+				locations = append(locations, prefix+"."+name)
+				break
+			}
+		}
+	}
+
+	return locations
+}
+
+// bodyTimestamps returns the locations of the values of a body that are timestamps
+// matched by equality
+func bodyTimestamps(prefix string, body *models.Body, rules models.MatchingRules) []string {
+	var locations []string
+
+	var walk func(node interface{}, segments []string)
+	walk = func(node interface{}, segments []string) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for _, k := range sortedKeys(n) {
+				walk(n[k], append(append([]string{}, segments...), k))
+			}
+		case []interface{}:
+			for i, v := range n {
+				walk(v, append(append([]string{}, segments...), matchingrules.Index(i)))
+			}
+		default:
+			path := matchingrules.FormatPath(segments)
+			if isTimestamp(n) && rules.Applying(models.CategoryBody, path) == nil {
+				locations = append(locations, prefix+"."+path)
+			}
+		}
+	}
+	walk(content(body), []string{})
+
+	return locations
+}
+
+// content returns the document of a body. JSON embedded in a string is decoded.
+func content(b *models.Body) interface{} {
+	if b == nil {
+		return nil
+	}
+
+	if s, ok := b.Content.(string); ok && b.Encoded == "json" {
+		var doc interface{}
+		d := json.NewDecoder(strings.NewReader(s))
+		d.UseNumber()
+		if err := d.Decode(&doc); err == nil {
+			return doc
+		}
+	}
+
+	return b.Content
+}
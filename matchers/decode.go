@@ -0,0 +1,164 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// literal is a value without matchers, which is matched by equality
+type literal struct {
+	value interface{}
+}
+
+func (l literal) GetValue() interface{} {
+	return l.value
+}
+
+func (l literal) isMatcher() {}
+
+func (l literal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.value)
+}
+
+// FromMatchingRules converts a body and its matching rules, as read from a pact
+// file, into an equivalent matcher tree. This allows the contracts of existing
+// pacts to be loaded, modified and given to the consumer DSL again.
+//
+// The body may be given as JSON ([]byte or json.RawMessage) or as its decoded
+// form. The rules may be the V2 matching rules of the request or response (keyed by
+// paths such as $.body.name), the V3/V4 matching rules or only their body category.
+// Rules that apply to no value of the body are ignored.
+func FromMatchingRules(body interface{}, rules map[string]interface{}) (Matcher, error) {
+	if raw, ok := body.([]byte); ok {
+		body = json.RawMessage(raw)
+	}
+	example, err := generic(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+
+	bodyRules, err := matchingrules.BodyRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	joined, err := matchingrules.Join(example, bodyRules, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Decode numbers in the rules as the example is
+	node, err := generic(joined)
+	if err != nil {
+		return nil, err
+	}
+
+	m := fromJSON(node)
+	if matcher, ok := m.(Matcher); ok {
+		return matcher, nil
+	}
+
+	return literal{m}, nil
+}
+
+// fromJSON converts the JSON form of matchers into matchers. Matchers without an
+// equivalent constructor are kept in their JSON form.
+func fromJSON(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, ok := n["pact:matcher:type"]; ok {
+			return fromJSONMatcher(n)
+		}
+		result := make(StructMatcher, len(n))
+		for k, v := range n {
+			result[k] = fromJSON(v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(n))
+		for i, v := range n {
+			result[i] = fromJSON(v)
+		}
+		return result
+	default:
+		return node
+	}
+}
+
+func fromJSONMatcher(obj map[string]interface{}) Matcher {
+	value := fromJSON(obj["value"])
+	str := func(k string) (string, bool) {
+		s, ok := obj[k].(string)
+		return s, ok
+	}
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := obj[k]; !ok {
+				return false
+			}
+		}
+		return len(obj) == len(keys)
+	}
+
+	t, _ := obj["pact:matcher:type"].(string)
+	generator, _ := str("pact:generator:type")
+
+	switch {
+	case t == "type" && has("pact:matcher:type", "value"):
+		return like{Specification: models.V2, Type: "type", Value: value}
+	case t == "type" && has("pact:matcher:type", "value", "min"):
+		min, ok := limit(obj["min"])
+		list, isList := value.([]interface{})
+		if ok && isList {
+			return eachLike{Value: list, Min: min}
+		}
+	case t == "type" && has("pact:matcher:type", "value", "min", "max"):
+		min, okMin := limit(obj["min"])
+		max, okMax := limit(obj["max"])
+		if okMin && okMax {
+			return minMaxLike{Specification: models.V3, Type: "type", Contents: value, Min: min, Max: max}
+		}
+	case t == "type" && generator == "ProviderState" && has("pact:matcher:type", "pact:generator:type", "expression", "value"):
+		expression, _ := str("expression")
+		if s, ok := value.(string); ok {
+			return FromProviderState(expression, s)
+		}
+	case t == "regex" && has("pact:matcher:type", "regex", "value"):
+		regex, _ := str("regex")
+		if s, ok := value.(string); ok {
+			return Term(s, regex)
+		}
+	case (t == "integer" || t == "decimal") && has("pact:matcher:type", "value"):
+		return like{Specification: models.V3, Type: t, Value: value}
+	case t == "null" && has("pact:matcher:type", "value"):
+		return Null{}
+	case t == "equality" && has("pact:matcher:type", "value"):
+		return equality{Specification: models.V3, Type: t, Contents: value}
+	case t == "include" && has("pact:matcher:type", "value"):
+		if s, ok := value.(string); ok {
+			return Includes(s)
+		}
+	case t == "values" && has("pact:matcher:type", "value"):
+		return eachKeyLike{Specification: models.V3, Type: t, Contents: value}
+	case t == "arrayContains" && has("pact:matcher:type", "variants"):
+		variants, _ := fromJSON(obj["variants"]).([]interface{})
+		return ArrayContaining(variants)
+	case (t == "date" || t == "time" || t == "timestamp") && has("pact:matcher:type", "pact:generator:type", "format", "value"):
+		format, _ := str("format")
+		s, ok := value.(string)
+		if ok && generator == map[string]string{"date": "Date", "time": "Time", "timestamp": "DateTime"}[t] {
+			return stringGenerator{Specification: models.V3, Type: t, Generator: generator, Contents: s, Format: format}
+		}
+	}
+
+	r := make(rule, len(obj))
+	for k, v := range obj {
+		r[k] = v
+	}
+	if _, ok := obj["value"]; ok {
+		r["value"] = value
+	}
+
+	return r
+}
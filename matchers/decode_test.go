@@ -0,0 +1,135 @@
+package matchers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMatchingRules(t *testing.T) {
+	body := `{"id": 10, "name": "billy", "tags": ["a", "b"], "created": "2020-01-01", "role": "admin"}`
+
+	tests := []struct {
+		name  string
+		rules string
+		want  Matcher
+	}{
+		{
+			name: "V3 rules",
+			rules: `{"body": {
+				"$.id": {"combine": "AND", "matchers": [{"match": "integer"}]},
+				"$.name": {"combine": "AND", "matchers": [{"match": "regex", "regex": "^[a-z]+$"}]},
+				"$.tags": {"combine": "AND", "matchers": [{"match": "type", "min": 1}]},
+				"$.tags[*]": {"combine": "AND", "matchers": [{"match": "type"}]},
+				"$.created": {"combine": "AND", "matchers": [{"match": "date", "format": "yyyy-MM-dd"}]},
+				"$.missing": {"combine": "AND", "matchers": [{"match": "type"}]}
+			}, "header": {"Accept": {"combine": "AND", "matchers": [{"match": "type"}]}}}`,
+			want: StructMatcher{
+				"id":      like{Specification: "3.0.0", Type: "integer", Value: json.Number("10")},
+				"name":    Term("billy", "^[a-z]+$"),
+				"tags":    eachLike{Value: []interface{}{Like("a"), Like("b")}, Min: 1},
+				"created": rule{"pact:matcher:type": "date", "format": "yyyy-MM-dd", "value": "2020-01-01"},
+				"role":    "admin",
+			},
+		},
+		{
+			name: "V2 rules",
+			rules: `{
+				"$.body.id": {"match": "type"},
+				"$.body.name": {"regex": "^[a-z]+$"},
+				"$.body.tags": {"min": 1, "match": "type"},
+				"$.headers.Accept": {"match": "type"}
+			}`,
+			want: StructMatcher{
+				"id":      Like(json.Number("10")),
+				"name":    Term("billy", "^[a-z]+$"),
+				"tags":    eachLike{Value: []interface{}{"a", "b"}, Min: 1},
+				"created": "2020-01-01",
+				"role":    "admin",
+			},
+		},
+		{
+			name: "combined rules",
+			rules: `{
+				"$.name": {"combine": "OR", "matchers": [{"match": "type"}, {"match": "null"}]},
+				"$.role": {"combine": "AND", "matchers": [{"match": "include", "value": "adm"}]}
+			}`,
+			want: StructMatcher{
+				"id":      json.Number("10"),
+				"name":    rule{"pact:matcher:type": []interface{}{map[string]interface{}{"pact:matcher:type": "type"}, map[string]interface{}{"pact:matcher:type": "null"}}, "pact:matcher:combine": "OR", "value": "billy"},
+				"tags":    []interface{}{"a", "b"},
+				"created": "2020-01-01",
+				"role":    Includes("adm"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.rules), &rules))
+
+			got, err := FromMatchingRules([]byte(body), rules)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromMatchingRulesRoundTrip(t *testing.T) {
+	type dto struct {
+		ID       int      `json:"id" pact:"example=10"`
+		Birthday *string  `json:"birthday" pact:"format=date,nullable"`
+		Roles    []string `json:"roles" pact:"min=2,max=5,example=admin"`
+		Tags     map[string]string
+	}
+	original := StructMatcher{
+		"user":  MatchV4(dto{}),
+		"items": ArrayContaining([]interface{}{StructMatcher{"id": Integer(1)}, Like("a")}),
+		"path":  FromProviderState("/users/${id}", "/users/1"),
+	}
+	// The example and rules as the FFI writes them to the pact file
+	example, err := Reify(original)
+	assert.NoError(t, err)
+
+	rules := map[string]interface{}{
+		"$.user.id":             map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "integer"}}},
+		"$.user.birthday":       map[string]interface{}{"combine": "OR", "matchers": []interface{}{map[string]interface{}{"match": "date", "format": "yyyy-MM-dd"}, map[string]interface{}{"match": "null"}}},
+		"$.user.roles":          map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "type", "min": 2, "max": 5}}},
+		"$.user.roles[*]":       map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "type"}}},
+		"$.user.Tags":           map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "values"}}},
+		"$.user.Tags.*":         map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "type"}}},
+		"$.items":               map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "arrayContains", "variants": []interface{}{map[string]interface{}{"index": 0, "rules": map[string]interface{}{"$.id": map[string]interface{}{"combine": "AND", "matchers": []interface{}{map[string]interface{}{"match": "integer"}}}}}, map[string]interface{}{"index": 1, "rules": map[string]interface{}{"$": map[string]interface{}{"combine": "AND", "matchers": []interface{}{map[string]interface{}{"match": "type"}}}}}}}}},
+		"$.path":                map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "type"}}},
+		"$.unused[*].something": map[string]interface{}{"matchers": []interface{}{map[string]interface{}{"match": "type"}}},
+	}
+
+	got, err := FromMatchingRules(example, rules)
+	assert.NoError(t, err)
+
+	// The matchers evaluate the same values as the originals
+	for _, actual := range []string{
+		`{"user": {"id": 1, "birthday": null, "roles": ["a", "b"], "Tags": {"a": "b"}}, "items": [2, {"id": 3}, "b"], "path": "/x"}`,
+		`{"user": {"id": "1", "birthday": 1, "roles": ["a"], "Tags": {"a": 1}}, "items": [{"id": "3"}], "path": 1}`,
+	} {
+		assert.Equal(t, len(Evaluate(original, []byte(actual))), len(Evaluate(got, []byte(actual))), actual)
+	}
+
+	reified, err := Reify(got)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(example), string(reified))
+}
+
+func TestFromMatchingRulesErrors(t *testing.T) {
+	_, err := FromMatchingRules([]byte(`{`), nil)
+	assert.Error(t, err)
+
+	_, err = FromMatchingRules([]byte(`{}`), map[string]interface{}{"$.a": "type"})
+	assert.EqualError(t, err, "invalid matching rule for $.a")
+
+	got, err := FromMatchingRules([]byte(`[1, 2]`), nil)
+	assert.NoError(t, err)
+	data, _ := json.Marshal(got)
+	assert.JSONEq(t, `[1, 2]`, string(data))
+}
@@ -0,0 +1,748 @@
+package matchers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// semver matches a semantic version, as used by the semver matcher
+var semver = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// identifier matches keys that don't need quoting in a path
+var identifier = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Mismatch is a value that doesn't satisfy the matcher it is evaluated against,
+// reported in the form the mock server uses for body mismatches
+type Mismatch struct {
+	// Type is the type of mismatch, always "BodyMismatch"
+	Type string `json:"type"`
+
+	// Path is the JSON path of the value, e.g. "$.items[0].id"
+	Path string `json:"path"`
+
+	// Expected is the expected value. Strings are given as is, other values in
+	// their JSON form
+	Expected string `json:"expected"`
+
+	// Actual is the actual value, in the same form as Expected. It is empty if
+	// the value is missing
+	Actual string `json:"actual"`
+
+	// Mismatch describes the mismatch
+	Mismatch string `json:"mismatch"`
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s: %s", m.Path, m.Mismatch)
+}
+
+// Evaluate checks the actual value against the matcher, following the matching
+// rules of the pact specification, and returns any mismatches. This allows a
+// matcher to be tested without a mock server.
+//
+// The actual value may be given as JSON ([]byte or json.RawMessage), or as any
+// value that can be encoded to JSON. As with the responses of a provider, objects
+// may have keys that aren't expected. Generators are ignored.
+func Evaluate(m Matcher, actual interface{}) []Mismatch {
+	e := &evaluator{}
+
+	expected, err := generic(m)
+	if err != nil {
+		e.add("$", nil, nil, fmt.Sprintf("Unable to encode the matcher: %v", err))
+		return e.mismatches
+	}
+
+	if raw, ok := actual.([]byte); ok {
+		actual = json.RawMessage(raw)
+	}
+	value, err := generic(actual)
+	if err != nil {
+		e.add("$", nil, nil, fmt.Sprintf("Unable to parse the actual value: %v", err))
+		return e.mismatches
+	}
+
+	e.compare(expected, value, "$", false)
+
+	return e.mismatches
+}
+
+// generic converts v into its generic JSON representation, preserving numbers
+func generic(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+type evaluator struct {
+	mismatches []Mismatch
+}
+
+func (e *evaluator) add(path string, expected interface{}, actual interface{}, mismatch string) {
+	e.mismatches = append(e.mismatches, Mismatch{
+		Type:     "BodyMismatch",
+		Path:     path,
+		Expected: mismatchValue(strip(expected)),
+		Actual:   mismatchValue(actual),
+		Mismatch: mismatch,
+	})
+}
+
+// passes reports whether the actual value satisfies the expected value
+func passes(expected interface{}, actual interface{}, path string, byType bool) bool {
+	e := &evaluator{}
+	e.compare(expected, actual, path, byType)
+
+	return len(e.mismatches) == 0
+}
+
+// compare compares the actual value with the expected value, which may be (or
+// contain) a matcher. Values are compared by type if a type matcher applies to an
+// ancestor, otherwise by equality.
+func (e *evaluator) compare(expected interface{}, actual interface{}, path string, byType bool) {
+	if obj, ok := expected.(map[string]interface{}); ok {
+		switch t := obj["pact:matcher:type"].(type) {
+		case string:
+			e.matcher(obj, t, actual, path)
+			return
+		case []interface{}:
+			e.combined(obj, t, actual, path)
+			return
+		}
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			e.add(path, expected, actual, fmt.Sprintf("Type mismatch: Expected %s %s but received %s %s", typeName(expected), describe(expected), typeName(actual), describe(actual)))
+			return
+		}
+
+		var missing []string
+		for _, k := range sortedKeys(exp) {
+			v, ok := act[k]
+			if !ok {
+				missing = append(missing, k)
+				continue
+			}
+			e.compare(exp[k], v, childPath(path, k), byType)
+		}
+		if len(missing) > 0 {
+			e.add(path, expected, actual, fmt.Sprintf("Actual map is missing the following keys: %s", strings.Join(missing, ", ")))
+		}
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			e.add(path, expected, actual, fmt.Sprintf("Type mismatch: Expected %s %s but received %s %s", typeName(expected), describe(expected), typeName(actual), describe(actual)))
+			return
+		}
+		if !byType && len(exp) != len(act) {
+			e.add(path, expected, actual, fmt.Sprintf("Expected a List with %d elements but received %d elements", len(exp), len(act)))
+		}
+		e.elements(exp, act, path, byType)
+	default:
+		if byType {
+			e.sameType(expected, actual, path)
+			return
+		}
+		if !equal(expected, actual) {
+			e.add(path, expected, actual, fmt.Sprintf("Expected %s but received %s", describe(expected), describe(actual)))
+		}
+	}
+}
+
+// elements compares each actual element with the expected element at the same
+// index, or the first expected element if there are more actual elements
+func (e *evaluator) elements(expected []interface{}, actual []interface{}, path string, byType bool) {
+	if len(expected) == 0 {
+		return
+	}
+	for i, v := range actual {
+		if i >= len(expected) && !byType {
+			return
+		}
+		template := expected[0]
+		if i < len(expected) {
+			template = expected[i]
+		}
+		e.compare(template, v, fmt.Sprintf("%s[%d]", path, i), byType)
+	}
+}
+
+func (e *evaluator) matcher(obj map[string]interface{}, t string, actual interface{}, path string) {
+	value := strip(obj["value"])
+
+	switch t {
+	case "type":
+		if !e.sameType(value, actual, path) {
+			return
+		}
+		if list, ok := actual.([]interface{}); ok {
+			e.size(obj, list, path)
+		}
+		switch v := obj["value"].(type) {
+		case map[string]interface{}:
+			e.compare(v, actual, path, true)
+		case []interface{}:
+			e.elements(v, actual.([]interface{}), path, true)
+		}
+	case "regex":
+		regex, _ := obj["regex"].(string)
+		re, err := compileRegex(regex)
+		if err != nil {
+			log.Printf("[WARN] the regular expression '%s' at %s is not supported, so it is not checked: %v", regex, path, err)
+			return
+		}
+		if s, ok := scalar(actual); !ok || !re.MatchString(s) {
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to match '%s'", describe(actual), regex))
+		}
+	case "include":
+		s, ok := actual.(string)
+		if !ok || !strings.Contains(s, fmt.Sprint(value)) {
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to include '%v'", describe(actual), value))
+		}
+	case "equality":
+		if !equal(value, actual) {
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to be equal to %s", describe(actual), describe(value)))
+		}
+	case "integer", "decimal", "number":
+		if !isNumber(actual, t) {
+			article := map[string]string{"integer": "an integer", "decimal": "a decimal number", "number": "a number"}[t]
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to be %s", describe(actual), article))
+		}
+	case "boolean":
+		if _, ok := actual.(bool); !ok {
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to be a boolean", describe(actual)))
+		}
+	case "null":
+		if actual != nil {
+			e.add(path, nil, actual, fmt.Sprintf("Expected %s to be a null value", describe(actual)))
+		}
+	case "notEmpty":
+		if isEmpty(actual) {
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to not be empty", describe(actual)))
+		} else {
+			e.sameType(value, actual, path)
+		}
+	case "semver":
+		if s, ok := actual.(string); !ok || !semver.MatchString(s) {
+			e.add(path, value, actual, fmt.Sprintf("%s is not a valid semantic version", describe(actual)))
+		}
+	case "timestamp", "date", "time":
+		format, _ := obj["format"].(string)
+		if err := checkDate(t, format, actual); err != nil {
+			e.add(path, value, actual, fmt.Sprintf("Expected %s to match a %s pattern of '%s': %v", describe(actual), map[string]string{"timestamp": "datetime", "date": "date", "time": "time"}[t], format, err))
+		}
+	case "values":
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			e.add(path, value, actual, fmt.Sprintf("Type mismatch: Expected %s to be a Map", describe(actual)))
+			return
+		}
+		// Every value is compared with the value of the first key of the template
+		template, _ := obj["value"].(map[string]interface{})
+		keys := sortedKeys(template)
+		if len(keys) == 0 {
+			return
+		}
+		for _, k := range sortedKeys(act) {
+			e.compare(template[keys[0]], act[k], childPath(path, k), false)
+		}
+	case "arrayContains":
+		act, ok := actual.([]interface{})
+		if !ok {
+			e.add(path, nil, actual, fmt.Sprintf("Type mismatch: Expected %s to be a List", describe(actual)))
+			return
+		}
+		variants, _ := obj["variants"].([]interface{})
+		for i, variant := range variants {
+			found := false
+			for j, v := range act {
+				if passes(variant, v, fmt.Sprintf("%s[%d]", path, j), false) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				e.add(path, strip(variant), actual, fmt.Sprintf("Variant at index %d (%s) was not found in the actual list", i, describe(strip(variant))))
+			}
+		}
+	default:
+		e.add(path, value, actual, fmt.Sprintf("Unsupported matcher type '%s'", t))
+	}
+}
+
+// combined evaluates a matcher combining several matchers, e.g. a nullable value
+func (e *evaluator) combined(obj map[string]interface{}, types []interface{}, actual interface{}, path string) {
+	or := obj["pact:matcher:combine"] == "OR"
+
+	var first *evaluator
+	for _, t := range types {
+		m, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matcher := make(map[string]interface{}, len(m)+1)
+		for k, v := range m {
+			matcher[k] = v
+		}
+		matcher["value"] = obj["value"]
+
+		result := &evaluator{}
+		result.compare(matcher, actual, path, false)
+		if or && len(result.mismatches) == 0 {
+			return
+		}
+		if !or {
+			e.mismatches = append(e.mismatches, result.mismatches...)
+		} else if first == nil {
+			first = result
+		}
+	}
+
+	if or && first != nil {
+		e.mismatches = append(e.mismatches, first.mismatches...)
+	}
+}
+
+// size checks the size of an array against the min and max of its matcher
+func (e *evaluator) size(obj map[string]interface{}, list []interface{}, path string) {
+	if min, ok := limit(obj["min"]); ok && len(list) < min {
+		e.add(path, strip(obj["value"]), list, fmt.Sprintf("Expected %s (size %d) to have minimum size of %d", describe(list), len(list), min))
+	}
+	if max, ok := limit(obj["max"]); ok && len(list) > max {
+		e.add(path, strip(obj["value"]), list, fmt.Sprintf("Expected %s (size %d) to have maximum size of %d", describe(list), len(list), max))
+	}
+}
+
+// sameType reports whether the actual value has the same type as the expected
+// value, adding a mismatch if not
+func (e *evaluator) sameType(expected interface{}, actual interface{}, path string) bool {
+	if category(expected) == category(actual) {
+		return true
+	}
+	e.add(path, expected, actual, fmt.Sprintf("Expected %s (%s) to be the same type as %s (%s)", describe(actual), typeName(actual), describe(expected), typeName(expected)))
+
+	return false
+}
+
+// strip replaces any matchers within the value with their example values
+func strip(v interface{}) interface{} {
+	switch n := v.(type) {
+	case map[string]interface{}:
+		if _, ok := n["pact:matcher:type"]; ok {
+			return strip(n["value"])
+		}
+		result := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			result[k] = strip(v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(n))
+		for i, v := range n {
+			result[i] = strip(v)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func limit(v interface{}) (int, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := n.Int64()
+
+	return int(i), err == nil
+}
+
+// category is the type of a JSON value for the type matcher, on which all
+// numbers are alike
+func category(v interface{}) string {
+	if _, ok := v.(json.Number); ok {
+		return "Number"
+	}
+
+	return typeName(v)
+}
+
+// typeName is the name of the type of a JSON value
+func typeName(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "Null"
+	case bool:
+		return "Boolean"
+	case json.Number:
+		if isNumber(n, "integer") {
+			return "Integer"
+		}
+		return "Decimal"
+	case string:
+		return "String"
+	case []interface{}:
+		return "List"
+	case map[string]interface{}:
+		return "Map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// isNumber reports whether v is a number of the kind used by the integer, decimal
+// and number matchers
+func isNumber(v interface{}, kind string) bool {
+	n, ok := v.(json.Number)
+	if !ok {
+		return false
+	}
+	decimal := strings.ContainsAny(n.String(), ".eE")
+
+	switch kind {
+	case "integer":
+		return !decimal
+	case "decimal":
+		return decimal
+	default:
+		return true
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	switch n := v.(type) {
+	case nil:
+		return true
+	case string:
+		return n == ""
+	case []interface{}:
+		return len(n) == 0
+	case map[string]interface{}:
+		return len(n) == 0
+	default:
+		return false
+	}
+}
+
+// compileRegex compiles the regex of a regex matcher. Regexes are written for the
+// mock server, which accepts some constructs that Go does not (e.g. the lookaheads
+// and backreferences of Timestamp), so a regex that doesn't compile is translated
+// with re2Pattern.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err == nil {
+		return re, nil
+	}
+
+	if translated, terr := regexp.Compile(re2Pattern(pattern)); terr == nil {
+		return translated, nil
+	}
+
+	return nil, err
+}
+
+// re2Pattern translates the constructs of a Perl style regex that Go's RE2 syntax
+// lacks. \Z becomes an end of text that allows a final newline, lookaround
+// assertions are removed and backreferences match anything their group can, so
+// the translated regex accepts everything the original does, and possibly more.
+func re2Pattern(pattern string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			next := pattern[i+1]
+			switch {
+			case next == 'Z':
+				b.WriteString(`(?:\n?\z)`)
+			case next >= '1' && next <= '9':
+				n := int(next - '0')
+				for i+2 < len(pattern) && pattern[i+2] >= '0' && pattern[i+2] <= '9' {
+					n = n*10 + int(pattern[i+2]-'0')
+					i++
+				}
+				if group, ok := captureGroup(pattern, n); ok && !strings.Contains(group, `\`+strconv.Itoa(n)) {
+					b.WriteString("(?:" + re2Pattern(group) + ")")
+				} else {
+					b.WriteString(`(?s:.*)`)
+				}
+			default:
+				b.WriteString(pattern[i : i+2])
+			}
+			i++
+		case c == '[':
+			end := classEnd(pattern, i)
+			b.WriteString(pattern[i : end+1])
+			i = end
+		case isLookaround(pattern[i:]):
+			i = groupEnd(pattern, i)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func isLookaround(pattern string) bool {
+	for _, prefix := range []string{"(?=", "(?!", "(?<=", "(?<!"} {
+		if strings.HasPrefix(pattern, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// captureGroup returns the contents of the nth capturing group of the pattern
+func captureGroup(pattern string, n int) (string, bool) {
+	count := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			i = classEnd(pattern, i)
+		case '(':
+			rest := pattern[i+1:]
+			named := strings.HasPrefix(rest, "?P<") || (strings.HasPrefix(rest, "?<") && !isLookaround(pattern[i:]))
+			if strings.HasPrefix(rest, "?") && !named {
+				continue
+			}
+			if count++; count < n {
+				continue
+			}
+			end := groupEnd(pattern, i)
+			if pattern[end] != ')' || end == i {
+				return "", false
+			}
+			group := pattern[i+1 : end]
+			if named {
+				group = group[strings.IndexByte(group, '>')+1:]
+			}
+			return group, true
+		}
+	}
+
+	return "", false
+}
+
+// classEnd returns the index of the ']' closing the character class starting at i
+func classEnd(pattern string, i int) int {
+	j := i + 1
+	if j < len(pattern) && pattern[j] == '^' {
+		j++
+	}
+	// A ']' at the start of the class is a literal
+	if j < len(pattern) && pattern[j] == ']' {
+		j++
+	}
+	for ; j < len(pattern); j++ {
+		switch pattern[j] {
+		case '\\':
+			j++
+		case ']':
+			return j
+		}
+	}
+
+	return len(pattern) - 1
+}
+
+// groupEnd returns the index of the ')' closing the group starting at i
+func groupEnd(pattern string, i int) int {
+	depth := 0
+	for j := i; j < len(pattern); j++ {
+		switch pattern[j] {
+		case '\\':
+			j++
+		case '[':
+			j = classEnd(pattern, j)
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+
+	return len(pattern) - 1
+}
+
+// scalar converts strings, numbers and booleans into a string, to match with a regex
+func scalar(v interface{}) (string, bool) {
+	switch n := v.(type) {
+	case string:
+		return n, true
+	case json.Number:
+		return n.String(), true
+	case bool:
+		return fmt.Sprint(n), true
+	default:
+		return "", false
+	}
+}
+
+func equal(a interface{}, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	if na, ok := a.(json.Number); ok {
+		if nb, ok := b.(json.Number); ok {
+			fa, errA := na.Float64()
+			fb, errB := nb.Float64()
+			return errA == nil && errB == nil && fa == fb
+		}
+	}
+
+	return bytes.Equal(x, y)
+}
+
+// describe formats a value for a mismatch message, quoting strings
+func describe(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return "'" + s + "'"
+	}
+	data, _ := json.Marshal(v)
+
+	return string(data)
+}
+
+// mismatchValue formats a value for the expected and actual fields of a mismatch
+func mismatchValue(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return n
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// childPath appends the key to the path, e.g. $.id or $['first name']
+func childPath(path string, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "['" + strings.ReplaceAll(key, "'", `\'`) + "']"
+}
+
+// checkDate checks that the value is a date, time or timestamp in the given
+// SimpleDateFormat pattern, or ISO 8601 if none is given
+func checkDate(t string, format string, v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("expected a string")
+	}
+
+	var layouts []string
+	if format != "" {
+		layout, err := goLayout(format)
+		if err != nil {
+			return err
+		}
+		layouts = []string{layout}
+	} else {
+		layouts = map[string][]string{
+			"timestamp": {time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05"},
+			"date":      {"2006-01-02"},
+			"time":      {"15:04:05.999999999", "15:04:05Z07:00", "15:04"},
+		}[t]
+	}
+
+	var err error
+	for _, layout := range layouts {
+		if _, err = time.Parse(layout, s); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// dateLetters are the Go layouts of the SimpleDateFormat pattern letters, longest first
+var dateLetters = []struct {
+	pattern string
+	layout  string
+}{
+	{"yyyy", "2006"}, {"yy", "06"}, {"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"dd", "02"}, {"d", "2"}, {"EEEE", "Monday"}, {"EEE", "Mon"}, {"HH", "15"}, {"hh", "03"}, {"h", "3"},
+	{"mm", "04"}, {"m", "4"}, {"ss", "05"}, {"s", "5"}, {"SSSSSS", "000000"}, {"SSS", "000"}, {"a", "PM"},
+	{"XXX", "Z07:00"}, {"XX", "Z0700"}, {"X", "Z07"}, {"Z", "-0700"}, {"z", "MST"},
+}
+
+// goLayout converts a SimpleDateFormat pattern into a Go time layout
+func goLayout(pattern string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated quote in pattern '%s'", pattern)
+			}
+			if end == 0 {
+				b.WriteByte('\'')
+			}
+			b.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		found := false
+		for _, l := range dateLetters {
+			if strings.HasPrefix(pattern[i:], l.pattern) {
+				b.WriteString(l.layout)
+				i += len(l.pattern)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("unsupported pattern letter '%c' in '%s'", c, pattern)
+		}
+	}
+
+	return b.String(), nil
+}
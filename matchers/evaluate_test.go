@@ -0,0 +1,245 @@
+package matchers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		actual  string
+		want    []Mismatch
+	}{
+		{
+			name:    "like",
+			matcher: Like("billy"),
+			actual:  `"sally"`,
+		},
+		{
+			name:    "like - type mismatch",
+			matcher: Like("billy"),
+			actual:  `1`,
+			want:    []Mismatch{{Path: "$", Expected: "billy", Actual: "1", Mismatch: "Expected 1 (Integer) to be the same type as 'billy' (String)"}},
+		},
+		{
+			name:    "like cascades to children",
+			matcher: Like(map[string]interface{}{"name": "billy", "tags": []string{"a"}}),
+			actual:  `{"name": 1, "tags": ["b", "c", 2], "extra": true}`,
+			want: []Mismatch{
+				{Path: "$.name", Expected: "billy", Actual: "1", Mismatch: "Expected 1 (Integer) to be the same type as 'billy' (String)"},
+				{Path: "$.tags[2]", Expected: "a", Actual: "2", Mismatch: "Expected 2 (Integer) to be the same type as 'a' (String)"},
+			},
+		},
+		{
+			name:    "literal values are compared by equality",
+			matcher: StructMatcher{"name": "billy", "items": []int{1, 2}},
+			actual:  `{"name": "sally", "items": [1]}`,
+			want: []Mismatch{
+				{Path: "$.items", Expected: "[1,2]", Actual: "[1]", Mismatch: "Expected a List with 2 elements but received 1 elements"},
+				{Path: "$.name", Expected: "billy", Actual: "sally", Mismatch: "Expected 'billy' but received 'sally'"},
+			},
+		},
+		{
+			name:    "missing keys",
+			matcher: StructMatcher{"id": Integer(1), "name": Like("billy")},
+			actual:  `{}`,
+			want:    []Mismatch{{Path: "$", Expected: `{"id":1,"name":"billy"}`, Actual: "{}", Mismatch: "Actual map is missing the following keys: id, name"}},
+		},
+		{
+			name:    "regex",
+			matcher: StructMatcher{"date": Regex("2000-01-01", `^\d{4}-\d{2}-\d{2}$`), "id": Term("1", `^\d+$`)},
+			actual:  `{"date": "01/01/2000", "id": 12}`,
+			want:    []Mismatch{{Path: "$.date", Expected: "2000-01-01", Actual: "01/01/2000", Mismatch: `Expected '01/01/2000' to match '^\d{4}-\d{2}-\d{2}$'`}},
+		},
+		{
+			name:    "each like",
+			matcher: EachLike(StructMatcher{"id": Integer(1)}, 2),
+			actual:  `[{"id": 1}]`,
+			want:    []Mismatch{{Path: "$", Expected: `[{"id":1},{"id":1}]`, Actual: `[{"id":1}]`, Mismatch: `Expected [{"id":1}] (size 1) to have minimum size of 2`}},
+		},
+		{
+			name:    "array min max",
+			matcher: ArrayMinMaxLike(Like("a"), 1, 2),
+			actual:  `["a", "b", 3]`,
+			want: []Mismatch{
+				{Path: "$", Expected: `["a","a"]`, Actual: `["a","b",3]`, Mismatch: `Expected ["a","b",3] (size 3) to have maximum size of 2`},
+				{Path: "$[2]", Expected: "a", Actual: "3", Mismatch: "Expected 3 (Integer) to be the same type as 'a' (String)"},
+			},
+		},
+		{
+			name:    "numbers",
+			matcher: StructMatcher{"integer": Integer(1), "decimal": Decimal(1.5), "bool": Like(true)},
+			actual:  `{"integer": 1.5, "decimal": 2, "bool": false}`,
+			want: []Mismatch{
+				{Path: "$.decimal", Expected: "1.5", Actual: "2", Mismatch: "Expected 2 to be a decimal number"},
+				{Path: "$.integer", Expected: "1", Actual: "1.5", Mismatch: "Expected 1.5 to be an integer"},
+			},
+		},
+		{
+			name:    "include and equality",
+			matcher: StructMatcher{"name": Includes("ill"), "role": Equality("admin")},
+			actual:  `{"name": "sally", "role": "user"}`,
+			want: []Mismatch{
+				{Path: "$.name", Expected: "ill", Actual: "sally", Mismatch: "Expected 'sally' to include 'ill'"},
+				{Path: "$.role", Expected: "admin", Actual: "user", Mismatch: "Expected 'user' to be equal to 'admin'"},
+			},
+		},
+		{
+			name:    "null",
+			matcher: Null{},
+			actual:  `"billy"`,
+			want:    []Mismatch{{Path: "$", Actual: "billy", Mismatch: "Expected 'billy' to be a null value"}},
+		},
+		{
+			name:    "each key like",
+			matcher: EachKeyLike("key", StructMatcher{"key": Integer(1)}),
+			actual:  `{"a": 1, "b c": "2"}`,
+			want:    []Mismatch{{Path: "$['b c']", Expected: "1", Actual: "2", Mismatch: "Expected '2' to be an integer"}},
+		},
+		{
+			name: "dates",
+			matcher: StructMatcher{
+				"date":     DateGenerated("2000-02-01", "yyyy-MM-dd"),
+				"time":     TimeGenerated("12:30", "HH:mm"),
+				"datetime": DateTimeGenerated("2000-02-01T12:30:00Z", "yyyy-MM-dd'T'HH:mm:ssXXX"),
+			},
+			actual: `{"date": "2000-02-30", "time": "12:45", "datetime": "2000-02-01T12:30:00+10:00"}`,
+			want: []Mismatch{{
+				Path:     "$.date",
+				Expected: "2000-02-01",
+				Actual:   "2000-02-30",
+				Mismatch: `Expected '2000-02-30' to match a date pattern of 'yyyy-MM-dd': parsing time "2000-02-30": day out of range`,
+			}},
+		},
+		{
+			name:    "array containing",
+			matcher: ArrayContaining([]interface{}{Like("a"), StructMatcher{"id": Integer(1)}}),
+			actual:  `["b", {"id": "1"}]`,
+			want:    []Mismatch{{Path: "$", Expected: `{"id":1}`, Actual: `["b",{"id":"1"}]`, Mismatch: `Variant at index 1 ({"id":1}) was not found in the actual list`}},
+		},
+		{
+			name: "nullable",
+			matcher: MatchV4(struct {
+				Age *int `pact:"nullable"`
+			}{}),
+			actual: `{"Age": null}`,
+		},
+		{
+			name: "nullable mismatch",
+			matcher: MatchV4(struct {
+				Age *int `pact:"nullable"`
+			}{}),
+			actual: `{"Age": "1"}`,
+			want:   []Mismatch{{Path: "$.Age", Expected: "1", Actual: "1", Mismatch: "Expected '1' to be an integer"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.want {
+				tt.want[i].Type = "BodyMismatch"
+			}
+			got := Evaluate(tt.matcher, []byte(tt.actual))
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvaluateValues(t *testing.T) {
+	type user struct {
+		ID    int      `json:"id"`
+		Name  string   `json:"name"`
+		Roles []string `json:"roles"`
+	}
+
+	assert.Empty(t, Evaluate(MatchV2(user{}), user{ID: 1, Name: "billy", Roles: []string{"admin"}}))
+	assert.Equal(t, []Mismatch{{
+		Type:     "BodyMismatch",
+		Path:     "$.roles",
+		Expected: `["string"]`,
+		Actual:   "[]",
+		Mismatch: "Expected [] (size 0) to have minimum size of 1",
+	}}, Evaluate(MatchV2(user{}), user{Roles: []string{}}))
+
+	mismatches := Evaluate(Like(1), []byte("{"))
+	assert.Len(t, mismatches, 1)
+	assert.Contains(t, mismatches[0].Mismatch, "Unable to parse the actual value")
+
+	data, _ := json.Marshal(Mismatch{Type: "BodyMismatch", Path: "$.id", Mismatch: "Expected 'a' to be an integer"})
+	assert.JSONEq(t, `{"type": "BodyMismatch", "path": "$.id", "expected": "", "actual": "", "mismatch": "Expected 'a' to be an integer"}`, string(data))
+}
+
+func TestEvaluateBuiltInRegexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		valid   []string
+		invalid string
+	}{
+		{name: "HexValue", matcher: HexValue(), valid: []string{"deadBEEF"}, invalid: "xyz"},
+		{name: "IPAddress", matcher: IPAddress(), valid: []string{"10.0.0.255"}, invalid: "localhost"},
+		{name: "IPv4Address", matcher: IPv4Address(), valid: []string{"192.168.1.1"}, invalid: "localhost"},
+		{name: "IPv6Address", matcher: IPv6Address(), invalid: "localhost"},
+		{name: "Timestamp", matcher: Timestamp(), valid: []string{"2020-12-31T23:59:59Z", "2020-12-31T23:59:59.123+10:00", "2020-12-31"}, invalid: "yesterday"},
+		{name: "Date", matcher: Date(), valid: []string{"2020-12-31", "2020-W53"}, invalid: "31/12/2020"},
+		{name: "Time", matcher: Time(), valid: []string{"T23:59:59", "T23:59:59.123+10:00"}, invalid: "23:59"},
+		{name: "UUID", matcher: UUID(), valid: []string{"123e4567-e89b-42d3-a456-426614174000"}, invalid: "not-a-uuid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			example := tt.matcher.GetValue()
+			assert.Empty(t, Evaluate(tt.matcher, example), "the example %v", example)
+			for _, v := range tt.valid {
+				assert.Empty(t, Evaluate(tt.matcher, v), v)
+			}
+
+			mismatches := Evaluate(tt.matcher, tt.invalid)
+			if assert.Len(t, mismatches, 1) {
+				assert.Contains(t, mismatches[0].Mismatch, "to match")
+			}
+		})
+	}
+}
+
+func TestCompileRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   []string
+		invalid []string
+	}{
+		{pattern: timestamp, valid: []string{"2000-02-01T12:30:00Z", "20000201T1230"}, invalid: []string{"2000-13-01", "2000-02-01T25:00"}},
+		{pattern: date, valid: []string{"2000-02-01", "2000-032"}, invalid: []string{"02/01/2000"}},
+		{pattern: ipv6Address, valid: []string{"2001:db8::1", "::ffff:192.0.2.128"}, invalid: []string{"2001:db8::1\nx", "127.0.0.1"}},
+		{pattern: `^(?P<x>a|b)\1(?<=a)[\])(]$`, valid: []string{"aa]", "ab)"}, invalid: []string{"ac]", "aa"}},
+	}
+
+	for _, tt := range tests {
+		re, err := compileRegex(tt.pattern)
+		if !assert.NoError(t, err, tt.pattern) {
+			continue
+		}
+		for _, v := range tt.valid {
+			assert.True(t, re.MatchString(v), v)
+		}
+		for _, v := range tt.invalid {
+			assert.False(t, re.MatchString(v), v)
+		}
+	}
+
+	for _, pattern := range []string{`[a-`, `(\1`, `\1(`} {
+		_, err := compileRegex(pattern)
+		assert.Error(t, err, pattern)
+	}
+
+	// Regexes that can't be compiled aren't checked
+	assert.Empty(t, Evaluate(Term("a", `[a-`), "b"))
+}
@@ -0,0 +1,230 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"time"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// providerStateExpression matches the ${name} placeholders of ProviderState generators
+var providerStateExpression = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// GeneratorContext is the context of a generated value
+type GeneratorContext struct {
+	// ProviderState are the parameters of the provider state of the interaction, which
+	// give the values of the expressions of ProviderState generators, e.g. ${id}
+	ProviderState map[string]interface{}
+
+	// MockServerURL is the base URL of the server sending the generated value, for
+	// MockServerURL generators
+	MockServerURL string
+}
+
+// Generate produces a value with a generator of a pact file, as the mock server and
+// verifier do, e.g. a random integer for {"type": "RandomInt", "min": 1, "max": 9}.
+// The example is the value of the pact file that the generated value replaces. It is
+// returned by generators that lack the context to produce a value, e.g. a
+// ProviderState generator without the parameter of its expression.
+//
+// Date and time generators produce the current time; their expressions (e.g.
+// "+1 day") are not supported.
+func Generate(generator models.Generator, example interface{}, context GeneratorContext) (interface{}, error) {
+	str := func(key string) string {
+		s, _ := generator[key].(string)
+		return s
+	}
+	size := func(key string, def int) int {
+		switch n := generator[key].(type) {
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				return int(i)
+			}
+		case int:
+			return n
+		case float64:
+			return int(n)
+		}
+		return def
+	}
+
+	switch t := str("type"); t {
+	case "RandomInt":
+		min, max := size("min", 0), size("max", 2147483647)
+		if max < min {
+			return nil, fmt.Errorf("invalid RandomInt generator, max %d is less than min %d", max, min)
+		}
+		return min + rand.IntN(max-min+1), nil
+	case "RandomDecimal":
+		digits := size("digits", 10)
+		if digits < 2 {
+			digits = 2
+		}
+		s := randomString(digits, "0123456789")
+		s = string("123456789"[rand.IntN(9)]) + s[1:]
+		point := 1 + rand.IntN(digits-1)
+		return json.Number(s[:point] + "." + s[point:]), nil
+	case "RandomHexadecimal":
+		return randomString(size("digits", 10), "0123456789abcdef"), nil
+	case "RandomString":
+		return randomString(size("size", 10), "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"), nil
+	case "RandomBoolean":
+		return rand.IntN(2) == 1, nil
+	case "Regex":
+		return generateRegex(str("regex"))
+	case "Uuid":
+		uuid := randomString(32, "0123456789abcdef")
+		hyphenated := uuid[:8] + "-" + uuid[8:12] + "-4" + uuid[13:16] + "-" + string("89ab"[rand.IntN(4)]) + uuid[17:20] + "-" + uuid[20:]
+		switch str("format") {
+		case "simple":
+			return strings.ReplaceAll(hyphenated, "-", ""), nil
+		case "upper-case-hyphenated":
+			return strings.ToUpper(hyphenated), nil
+		case "URN":
+			return "urn:uuid:" + hyphenated, nil
+		default:
+			return hyphenated, nil
+		}
+	case "Date", "Time", "DateTime":
+		layout := map[string]string{"Date": "2006-01-02", "Time": "15:04:05", "DateTime": time.RFC3339}[t]
+		if format := str("format"); format != "" {
+			var err error
+			if layout, err = goLayout(format); err != nil {
+				return nil, err
+			}
+		}
+		return time.Now().Format(layout), nil
+	case "ProviderState":
+		return providerStateValue(str("expression"), example, context.ProviderState), nil
+	case "MockServerURL":
+		if context.MockServerURL == "" {
+			return example, nil
+		}
+		value, ok := generator["example"].(string)
+		if !ok {
+			value, _ = example.(string)
+		}
+		re, err := regexp.Compile(str("regex"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid MockServerURL generator: %w", err)
+		}
+		if m := re.FindStringSubmatch(value); len(m) > 1 {
+			return strings.TrimSuffix(context.MockServerURL, "/") + m[1], nil
+		}
+		return example, nil
+	default:
+		return nil, fmt.Errorf("unsupported generator type '%s'", t)
+	}
+}
+
+// providerStateValue replaces the ${name} placeholders of the expression with the
+// parameters of the provider state. An expression that is a single placeholder is
+// replaced by the parameter itself, keeping its type.
+func providerStateValue(expression string, example interface{}, params map[string]interface{}) interface{} {
+	if m := providerStateExpression.FindStringSubmatch(expression); m != nil && m[0] == expression {
+		if v, ok := params[m[1]]; ok {
+			return v
+		}
+		return example
+	}
+
+	missing := false
+	value := providerStateExpression.ReplaceAllStringFunc(expression, func(placeholder string) string {
+		v, ok := params[placeholder[2:len(placeholder)-1]]
+		if !ok {
+			missing = true
+			return placeholder
+		}
+		return fmt.Sprint(v)
+	})
+	if missing {
+		return example
+	}
+
+	return value
+}
+
+func randomString(n int, alphabet string) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rand.IntN(len(alphabet))]
+	}
+
+	return string(b)
+}
+
+// generateRegex produces a random string that matches the regular expression.
+// Unbounded repetitions are repeated at most a few times.
+func generateRegex(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("invalid Regex generator: %w", err)
+	}
+
+	var b strings.Builder
+	writeRegex(&b, re.Simplify())
+
+	return b.String(), nil
+}
+
+func writeRegex(b *strings.Builder, re *syntax.Regexp) {
+	repeat := func(min int, max int) {
+		if max < 0 {
+			max = min + 3
+		}
+		for i := min + rand.IntN(max-min+1); i > 0; i-- {
+			writeRegex(b, re.Sub[0])
+		}
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteString(randomString(1, "abcdefghijklmnopqrstuvwxyz"))
+	case syntax.OpCapture:
+		writeRegex(b, re.Sub[0])
+	case syntax.OpStar:
+		repeat(0, -1)
+	case syntax.OpPlus:
+		repeat(1, -1)
+	case syntax.OpQuest:
+		repeat(0, 1)
+	case syntax.OpRepeat:
+		repeat(re.Min, re.Max)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegex(b, sub)
+		}
+	case syntax.OpAlternate:
+		writeRegex(b, re.Sub[rand.IntN(len(re.Sub))])
+	}
+}
+
+// classRune picks a rune of a character class, given as pairs of the first and last
+// runes of its ranges. Printable ASCII runes are preferred, for readable values.
+func classRune(ranges []rune) rune {
+	var printable [][2]rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], ' '+1), min(ranges[i+1], '~')
+		if lo <= hi {
+			printable = append(printable, [2]rune{lo, hi})
+		}
+	}
+	if len(printable) > 0 {
+		r := printable[rand.IntN(len(printable))]
+		return r[0] + rand.Int32N(r[1]-r[0]+1)
+	}
+	if len(ranges) < 2 {
+		return ' '
+	}
+
+	return ranges[0]
+}
@@ -0,0 +1,140 @@
+package matchers
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	context := GeneratorContext{
+		ProviderState: map[string]interface{}{"id": json.Number("42"), "name": "billy"},
+		MockServerURL: "http://localhost:8080",
+	}
+
+	tests := []struct {
+		name      string
+		generator models.Generator
+		example   interface{}
+		check     func(t *testing.T, v interface{})
+	}{
+		{
+			name:      "random integer",
+			generator: models.Generator{"type": "RandomInt", "min": json.Number("5"), "max": 7},
+			check: func(t *testing.T, v interface{}) {
+				assert.GreaterOrEqual(t, v, 5)
+				assert.LessOrEqual(t, v, 7)
+			},
+		},
+		{
+			name:      "random decimal",
+			generator: models.Generator{"type": "RandomDecimal", "digits": 6},
+			check: func(t *testing.T, v interface{}) {
+				assert.Regexp(t, `^[1-9][0-9]*\.[0-9]+$`, v)
+				assert.Len(t, string(v.(json.Number)), 7)
+			},
+		},
+		{
+			name:      "random hexadecimal",
+			generator: models.Generator{"type": "RandomHexadecimal", "digits": 8},
+			check:     func(t *testing.T, v interface{}) { assert.Regexp(t, `^[0-9a-f]{8}$`, v) },
+		},
+		{
+			name:      "random string",
+			generator: models.Generator{"type": "RandomString"},
+			check:     func(t *testing.T, v interface{}) { assert.Regexp(t, `^[a-zA-Z0-9]{10}$`, v) },
+		},
+		{
+			name:      "regex",
+			generator: models.Generator{"type": "Regex", "regex": `^(ab|cd)-\d{3}[A-Z]+\.json$`},
+			check:     func(t *testing.T, v interface{}) { assert.Regexp(t, `^(ab|cd)-\d{3}[A-Z]+\.json$`, v) },
+		},
+		{
+			name:      "negated character class",
+			generator: models.Generator{"type": "Regex", "regex": `[^/]+`},
+			check:     func(t *testing.T, v interface{}) { assert.Regexp(t, `^[!-~]+$`, v) },
+		},
+		{
+			name:      "uuid",
+			generator: models.Generator{"type": "Uuid"},
+			check: func(t *testing.T, v interface{}) {
+				assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, v)
+			},
+		},
+		{
+			name:      "urn uuid",
+			generator: models.Generator{"type": "Uuid", "format": "URN"},
+			check:     func(t *testing.T, v interface{}) { assert.Regexp(t, `^urn:uuid:[0-9a-f-]{36}$`, v) },
+		},
+		{
+			name:      "date with format",
+			generator: models.Generator{"type": "Date", "format": "dd/MM/yyyy"},
+			check:     func(t *testing.T, v interface{}) { assert.Equal(t, time.Now().Format("02/01/2006"), v) },
+		},
+		{
+			name:      "date time",
+			generator: models.Generator{"type": "DateTime"},
+			check: func(t *testing.T, v interface{}) {
+				_, err := time.Parse(time.RFC3339, v.(string))
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:      "provider state value",
+			generator: models.Generator{"type": "ProviderState", "expression": "${id}"},
+			example:   json.Number("1"),
+			check:     func(t *testing.T, v interface{}) { assert.Equal(t, json.Number("42"), v) },
+		},
+		{
+			name:      "provider state expression",
+			generator: models.Generator{"type": "ProviderState", "expression": "/users/${id}/${name}"},
+			example:   "/users/1/bob",
+			check:     func(t *testing.T, v interface{}) { assert.Equal(t, "/users/42/billy", v) },
+		},
+		{
+			name:      "missing provider state parameter",
+			generator: models.Generator{"type": "ProviderState", "expression": "/orders/${orderId}"},
+			example:   "/orders/1",
+			check:     func(t *testing.T, v interface{}) { assert.Equal(t, "/orders/1", v) },
+		},
+		{
+			name:      "mock server URL",
+			generator: models.Generator{"type": "MockServerURL", "regex": `.*(/orders/\d+)$`, "example": "http://localhost:1234/orders/1"},
+			example:   "http://localhost:1234/orders/1",
+			check:     func(t *testing.T, v interface{}) { assert.Equal(t, "http://localhost:8080/orders/1", v) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Generate(tt.generator, tt.example, context)
+			assert.NoError(t, err)
+			tt.check(t, v)
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate(models.Generator{"type": "Unknown"}, nil, GeneratorContext{})
+	assert.Error(t, err)
+
+	_, err = Generate(models.Generator{"type": "RandomInt", "min": 10, "max": 1}, nil, GeneratorContext{})
+	assert.Error(t, err)
+
+	_, err = Generate(models.Generator{"type": "Regex", "regex": "("}, nil, GeneratorContext{})
+	assert.Error(t, err)
+}
+
+func TestGenerateRegexRepeats(t *testing.T) {
+	re := regexp.MustCompile(`^[a-z]{2,4}\d*$`)
+	for i := 0; i < 50; i++ {
+		v, err := generateRegex(re.String())
+		assert.NoError(t, err)
+		assert.Regexp(t, re, v, strconv.Itoa(i))
+	}
+}
@@ -0,0 +1,832 @@
+package matchers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// rfc3339 matches times as encoded by time.Time, with optional fractional seconds
+const rfc3339 = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// dateFormat is the matcher type, default SimpleDateFormat pattern, example and
+// generator of a date or time format
+type dateFormat struct {
+	matcher   string
+	pattern   string
+	example   string
+	generator string
+}
+
+var dateFormats = map[string]dateFormat{
+	"date-time": {"timestamp", "yyyy-MM-dd'T'HH:mm:ssXXX", timeExample.Format(time.RFC3339), "datetime"},
+	"date":      {"date", "yyyy-MM-dd", timeExample.Format("2006-01-02"), "date"},
+	"time":      {"time", "HH:mm:ss", timeExample.Format("15:04:05"), "time"},
+}
+
+// formatAliases are alternative names of the date-time format
+var formatAliases = map[string]string{
+	"datetime":  "date-time",
+	"timestamp": "date-time",
+}
+
+// regexFormat is the regular expression and default example of a string format
+type regexFormat struct {
+	regex   string
+	example string
+}
+
+var regexFormats = map[string]regexFormat{
+	"uuid": {uuid, "fc763eba-0905-41c5-a27f-3934ab26786c"},
+	"ipv4": {ipAddress, "127.0.0.1"},
+	"ipv6": {ipv6Address, "::ffff:192.0.2.128"},
+	"hex":  {hexadecimal, "3F"},
+}
+
+// rule is a matcher in its JSON form. It is used where the tags of a field combine
+// attributes that no single matcher describes, e.g. a matcher with a generator.
+type rule map[string]interface{}
+
+func (r rule) GetValue() interface{} {
+	return r["value"]
+}
+
+func (r rule) isMatcher() {}
+
+// toRule converts the matcher into its JSON form
+func toRule(m Matcher) rule {
+	switch v := m.(type) {
+	case rule:
+		r := make(rule, len(v))
+		for k, value := range v {
+			r[k] = value
+		}
+		return r
+	case StructMatcher:
+		return rule{"pact:matcher:type": "type", "value": v}
+	}
+
+	r := rule{}
+	data, _ := json.Marshal(m)
+	_ = json.Unmarshal(data, &r)
+	delete(r, "specification")
+	r["value"] = m.GetValue()
+
+	return r
+}
+
+// MatchV3 recursively traverses the provided type and outputs a matcher for it
+// using the matchers of the V3 specification. It supports the same tags as MatchV4,
+// other than those requiring the V4 specification (notempty and format=semver).
+//
+// It panics if a tag is invalid, or a type can't be matched; use MatchV3E to handle
+// these as errors.
+func MatchV3(src interface{}) Matcher {
+	return mustMatch(MatchV3E(src))
+}
+
+// MatchV3E is MatchV3, returning an error if a tag is invalid or a type can't be
+// matched
+func MatchV3E(src interface{}) (Matcher, error) {
+	return matchTags(src, models.V3)
+}
+
+// MatchV4 recursively traverses the provided type and outputs a matcher for it
+// using the matchers of the V4 specification. Integers are matched with Integer,
+// floats with Decimal, slices with EachLike (or ArrayMinMaxLike when bounded),
+// maps with EachKeyLike and time.Time values as RFC3339 timestamps.
+//
+// Optionally, the matchers may be refined with pact tags on struct fields. Options
+// are separated by commas (use \, for a literal comma) and may be combined:
+//
+//	example=<value>        the example value
+//	min=<n>, max=<n>       the size of a slice, or the range of a random integer
+//	format=<format>        one of date-time, date or time (optionally with a
+//	                       SimpleDateFormat pattern, e.g. date:dd/MM/yyyy), uuid,
+//	                       ipv4, ipv6, hex, number or semver
+//	generator=<generator>  one of random, uuid, regex, date, time, datetime or
+//	                       providerstate:<expression>
+//	nullable               the value may also be null
+//	notempty               the string must not be empty
+//	regex=<regex>          the regular expression to match (must be the last option)
+//
+// On a slice or map, min, max and nullable apply to the collection and all other
+// options to its elements.
+//
+// Example:
+//
+//	type User struct {
+//		ID        int       `json:"id" pact:"example=10"`
+//		Roles     []string  `json:"roles" pact:"min=1,max=5,example=admin"`
+//		CreatedAt time.Time `json:"createdAt" pact:"generator=datetime"`
+//		DeletedAt *string   `json:"deletedAt" pact:"format=date,nullable"`
+//	}
+//
+// It panics if a tag is invalid, or a type can't be matched; use MatchV4E to handle
+// these as errors.
+func MatchV4(src interface{}) Matcher {
+	return mustMatch(MatchV4E(src))
+}
+
+// MatchV4E is MatchV4, returning an error if a tag is invalid or a type can't be
+// matched
+func MatchV4E(src interface{}) (Matcher, error) {
+	return matchTags(src, models.V4)
+}
+
+func mustMatch(m Matcher, err error) Matcher {
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return m
+}
+
+func matchTags(src interface{}, version models.SpecificationVersion) (Matcher, error) {
+	if src == nil {
+		return nil, errors.New("match: unable to match a nil value")
+	}
+
+	w := &tagWalker{
+		version:  version,
+		visiting: make(map[reflect.Type]bool),
+	}
+	m, err := w.match(reflect.ValueOf(src), reflect.TypeOf(src), tag{}, "$")
+	if err != nil {
+		return nil, fmt.Errorf("match: %w", err)
+	}
+
+	return m, nil
+}
+
+// tag is a parsed pact struct tag
+type tag struct {
+	options    []string
+	example    *string
+	min        *int
+	max        *int
+	format     string
+	pattern    string
+	generator  string
+	expression string
+	regex      string
+	nullable   bool
+	notEmpty   bool
+}
+
+// parseTag parses a pact struct tag, e.g. `pact:"min=1,max=5"`
+func parseTag(s string) (tag, error) {
+	var t tag
+
+	for s != "" {
+		var option string
+		if strings.HasPrefix(s, "regex=") {
+			option, s = s, ""
+		} else {
+			option, s = nextOption(s)
+		}
+		if option == "" {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(option, "=")
+		if t.has(key) {
+			return t, fmt.Errorf("%s is given more than once", key)
+		}
+		t.options = append(t.options, key)
+
+		switch key {
+		case "nullable", "notempty":
+			if hasValue {
+				return t, fmt.Errorf("%s does not take a value", key)
+			}
+			t.nullable = t.nullable || key == "nullable"
+			t.notEmpty = t.notEmpty || key == "notempty"
+			continue
+		case "example":
+			t.example = &value
+			continue
+		}
+
+		if value == "" {
+			return t, fmt.Errorf("%s must not be empty", key)
+		}
+
+		switch key {
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return t, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+			}
+			if key == "min" {
+				t.min = &n
+			} else {
+				t.max = &n
+			}
+		case "format":
+			t.format, t.pattern, _ = strings.Cut(value, ":")
+			if alias, ok := formatAliases[t.format]; ok {
+				t.format = alias
+			}
+		case "generator":
+			t.generator, t.expression, _ = strings.Cut(value, ":")
+		case "regex":
+			t.regex = value
+		default:
+			return t, fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	return t, nil
+}
+
+// nextOption returns the option up to the first unescaped comma, and the rest
+func nextOption(s string) (string, string) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		case s[i] == ',':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), ""
+}
+
+// has reports whether the option was given
+func (t tag) has(option string) bool {
+	for _, o := range t.options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// only returns an error if an option other than those allowed was given
+func (t tag) only(path string, kind string, allowed ...string) error {
+	for _, o := range t.options {
+		ok := false
+		for _, a := range allowed {
+			ok = ok || o == a
+		}
+		if !ok {
+			return fmt.Errorf("%s: %s is not supported on %s", path, o, kind)
+		}
+	}
+
+	return nil
+}
+
+// split separates the options of a collection from those of its elements
+func (t tag) split() (collection tag, element tag) {
+	for _, o := range t.options {
+		switch o {
+		case "min", "max", "nullable":
+			collection.options = append(collection.options, o)
+		default:
+			element.options = append(element.options, o)
+		}
+	}
+	collection.min, collection.max, collection.nullable = t.min, t.max, t.nullable
+	element.example, element.format, element.pattern = t.example, t.format, t.pattern
+	element.generator, element.expression = t.generator, t.expression
+	element.regex, element.notEmpty = t.regex, t.notEmpty
+
+	return collection, element
+}
+
+// tagWalker builds the matcher of a type from its pact tags
+type tagWalker struct {
+	version  models.SpecificationVersion
+	visiting map[reflect.Type]bool
+}
+
+func (w *tagWalker) match(v reflect.Value, t reflect.Type, tg tag, path string) (Matcher, error) {
+	switch t {
+	case timeType:
+		return w.time(tg, path)
+	case rawMessageType:
+		return w.rawMessage(tg, path)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+		return w.match(v, t.Elem(), tg, path)
+	case reflect.Interface:
+		if v.IsValid() && !v.IsNil() {
+			return w.match(v.Elem(), v.Elem().Type(), tg, path)
+		}
+		return nil, fmt.Errorf("%s: unable to determine the type of %s, give it a value or use a concrete type", path, t)
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return w.bytes(tg, path)
+		}
+		return w.slice(v, t, tg, path)
+	case reflect.Map:
+		return w.mapOf(t, tg, path)
+	case reflect.Struct:
+		return w.object(v, t, tg, path)
+	case reflect.String:
+		return w.str(tg, path)
+	case reflect.Bool:
+		return w.boolean(tg, path)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return w.integer(tg, path)
+	case reflect.Float32, reflect.Float64:
+		return w.decimal(tg, path)
+	default:
+		return nil, fmt.Errorf("%s: unable to match the type %s", path, t)
+	}
+}
+
+func (w *tagWalker) slice(v reflect.Value, t reflect.Type, tg tag, path string) (Matcher, error) {
+	collection, element := tg.split()
+
+	var ev reflect.Value
+	if v.IsValid() && v.Len() > 0 {
+		ev = v.Index(0)
+	}
+	elem, err := w.match(ev, t.Elem(), element, path+"[*]")
+	if err != nil {
+		return nil, err
+	}
+
+	minimum := 1
+	if collection.min != nil {
+		minimum = *collection.min
+	}
+	examples := make([]interface{}, max(minimum, 1))
+	for i := range examples {
+		examples[i] = elem
+	}
+
+	var m Matcher = eachLike{Value: examples, Min: minimum}
+	if collection.max != nil {
+		if *collection.max < len(examples) {
+			return nil, fmt.Errorf("%s: max %d is less than min %d", path, *collection.max, len(examples))
+		}
+		m = minMaxLike{
+			Specification: models.V3,
+			Type:          "type",
+			Contents:      examples,
+			Min:           minimum,
+			Max:           *collection.max,
+		}
+	}
+
+	return w.finish(m, collection, path)
+}
+
+func (w *tagWalker) mapOf(t reflect.Type, tg tag, path string) (Matcher, error) {
+	switch t.Key().Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, fmt.Errorf("%s: unable to match a map with %s keys", path, t.Key())
+	}
+
+	collection, element := tg.split()
+	if err := collection.only(path, "a map", "nullable"); err != nil {
+		return nil, err
+	}
+
+	elem, err := w.match(reflect.Value{}, t.Elem(), element, path+".*")
+	if err != nil {
+		return nil, err
+	}
+
+	return w.finish(EachKeyLike("key", StructMatcher{"key": elem}), collection, path)
+}
+
+func (w *tagWalker) object(v reflect.Value, t reflect.Type, tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "an object", "nullable"); err != nil {
+		return nil, err
+	}
+	if w.visiting[t] {
+		return nil, fmt.Errorf("%s: %s is a cyclic type", path, t)
+	}
+	w.visiting[t] = true
+	defer delete(w.visiting, t)
+
+	result := StructMatcher{}
+	for _, f := range jsonFields(t) {
+		fieldPath := path + "." + f.name
+
+		var fv reflect.Value
+		if v.IsValid() {
+			fv, _ = v.FieldByIndexErr(f.index)
+		}
+
+		ft, err := parseTag(f.tag)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pact tag %q: %v", fieldPath, f.tag, err)
+		}
+
+		m, err := w.match(fv, f.typ, ft, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		result[f.name] = m
+	}
+
+	return w.finish(result, tg, path)
+}
+
+// jsonField is a field of a struct as encoded by encoding/json
+type jsonField struct {
+	name  string
+	index []int
+	typ   reflect.Type
+	tag   string
+}
+
+// jsonFields returns the fields of the struct as encoded by encoding/json, skipping
+// unexported and ignored fields and flattening embedded structs. A field hides any
+// field of the same name embedded deeper in the struct.
+func jsonFields(t reflect.Type) []jsonField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []jsonField
+	seen := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+
+	for level := []embedded{{t, nil}}; len(level) > 0; {
+		var next []embedded
+		found := make(map[string]bool)
+
+		for _, e := range level {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				f := e.typ.Field(i)
+				index := append(append([]int{}, e.index...), i)
+				jsonTag := f.Tag.Get("json")
+				if jsonTag == "-" {
+					continue
+				}
+				name, _, _ := strings.Cut(jsonTag, ",")
+
+				if f.Anonymous && name == "" {
+					ft := f.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{ft, index})
+						continue
+					}
+				}
+				if !f.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = f.Name
+				}
+				if seen[name] || found[name] {
+					continue
+				}
+				found[name] = true
+
+				fields = append(fields, jsonField{
+					name:  name,
+					index: index,
+					typ:   f.Type,
+					tag:   f.Tag.Get("pact"),
+				})
+			}
+		}
+
+		for name := range found {
+			seen[name] = true
+		}
+		level = next
+	}
+
+	return fields
+}
+
+func (w *tagWalker) str(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "a string", "example", "format", "generator", "regex", "nullable", "notempty"); err != nil {
+		return nil, err
+	}
+	if w.version < models.V4 && (tg.notEmpty || tg.format == "semver") {
+		return nil, fmt.Errorf("%s: notempty and format=semver require the V4 specification", path)
+	}
+	example := func(value string) string {
+		if tg.example != nil {
+			return *tg.example
+		}
+		return value
+	}
+
+	var m Matcher
+	switch {
+	case tg.regex != "":
+		if tg.format != "" {
+			return nil, fmt.Errorf("%s: format can't be combined with regex", path)
+		}
+		if tg.example == nil {
+			return nil, fmt.Errorf("%s: regex requires an example", path)
+		}
+		m = Regex(*tg.example, tg.regex)
+	case tg.format == "":
+		m = Like(example("string"))
+	case tg.format == "semver":
+		m = rule{
+			"pact:specification": models.V4,
+			"pact:matcher:type":  "semver",
+			"value":              example("1.0.0"),
+		}
+	default:
+		if f, ok := dateFormats[tg.format]; ok {
+			return w.date(f, tg, path)
+		}
+		f, ok := regexFormats[tg.format]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown format %q for a string", path, tg.format)
+		}
+		m = Regex(example(f.example), f.regex)
+	}
+	if tg.pattern != "" {
+		return nil, fmt.Errorf("%s: format %s does not take a pattern", path, tg.format)
+	}
+
+	if tg.notEmpty {
+		if tg.regex != "" || tg.format != "" {
+			return nil, fmt.Errorf("%s: notempty can't be combined with regex or format", path)
+		}
+		m = rule{
+			"pact:specification": models.V4,
+			"pact:matcher:type":  "notEmpty",
+			"value":              m.GetValue(),
+		}
+	}
+
+	return w.generate(m, tg, path, "RandomString", "uuid", "regex")
+}
+
+// date matches a string with a date or time format
+func (w *tagWalker) date(f dateFormat, tg tag, path string) (Matcher, error) {
+	if tg.notEmpty {
+		return nil, fmt.Errorf("%s: notempty can't be combined with format", path)
+	}
+
+	pattern, example := f.pattern, f.example
+	if tg.pattern != "" {
+		if tg.example == nil {
+			return nil, fmt.Errorf("%s: a format pattern requires an example", path)
+		}
+		pattern = tg.pattern
+	}
+	if tg.example != nil {
+		example = *tg.example
+	}
+
+	if tg.generator == f.generator {
+		var m Matcher
+		switch f.matcher {
+		case "date":
+			m = DateGenerated(example, pattern)
+		case "time":
+			m = TimeGenerated(example, pattern)
+		default:
+			m = DateTimeGenerated(example, pattern)
+		}
+		return w.finish(m, tg, path)
+	}
+
+	return w.generate(rule{
+		"pact:matcher:type": f.matcher,
+		"format":            pattern,
+		"value":             example,
+	}, tg, path, "")
+}
+
+// time matches a time.Time, as encoded by encoding/json
+func (w *tagWalker) time(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "a time", "example", "format", "generator", "nullable"); err != nil {
+		return nil, err
+	}
+	if tg.format != "" && (tg.format != "date-time" || tg.pattern != "") {
+		return nil, fmt.Errorf("%s: a time can only have the date-time format", path)
+	}
+
+	example := timeExample.Format(time.RFC3339)
+	if tg.example != nil {
+		example = *tg.example
+	}
+	m := Regex(example, rfc3339)
+
+	if tg.generator == "datetime" {
+		r := toRule(m)
+		r["pact:generator:type"] = "DateTime"
+		r["format"] = dateFormats["date-time"].pattern
+		return w.finish(r, tg, path)
+	}
+
+	return w.generate(m, tg, path, "")
+}
+
+func (w *tagWalker) boolean(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "a bool", "example", "generator", "nullable"); err != nil {
+		return nil, err
+	}
+
+	value := true
+	if tg.example != nil {
+		b, err := strconv.ParseBool(*tg.example)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid example %q for a bool", path, *tg.example)
+		}
+		value = b
+	}
+
+	return w.generate(Like(value), tg, path, "RandomBoolean")
+}
+
+func (w *tagWalker) integer(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "an integer", "example", "format", "generator", "min", "max", "nullable"); err != nil {
+		return nil, err
+	}
+	if (tg.min != nil || tg.max != nil) && tg.generator != "random" {
+		return nil, fmt.Errorf("%s: min and max are only supported on an integer with generator=random", path)
+	}
+
+	value := 1
+	if tg.example != nil {
+		n, err := strconv.Atoi(*tg.example)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid example %q for an integer", path, *tg.example)
+		}
+		value = n
+	}
+
+	return w.generate(w.number(Integer(value), value, tg), tg, path, "RandomInt")
+}
+
+func (w *tagWalker) decimal(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "a float", "example", "format", "generator", "nullable"); err != nil {
+		return nil, err
+	}
+
+	value := 1.1
+	if tg.example != nil {
+		f, err := strconv.ParseFloat(*tg.example, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid example %q for a float", path, *tg.example)
+		}
+		value = f
+	}
+
+	return w.generate(w.number(Decimal(value), value, tg), tg, path, "RandomDecimal")
+}
+
+// number returns a number matcher if the tag has the number format, or m
+func (w *tagWalker) number(m Matcher, value interface{}, tg tag) Matcher {
+	if tg.format != "number" {
+		return m
+	}
+
+	return rule{
+		"pact:matcher:type": "number",
+		"value":             value,
+	}
+}
+
+// bytes matches a []byte, which encoding/json encodes as a base64 string
+func (w *tagWalker) bytes(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "a []byte", "example", "nullable"); err != nil {
+		return nil, err
+	}
+
+	example := "cGFjdA=="
+	if tg.example != nil {
+		example = *tg.example
+	}
+
+	return w.finish(Like(example), tg, path)
+}
+
+// rawMessage matches a json.RawMessage by the type of its example
+func (w *tagWalker) rawMessage(tg tag, path string) (Matcher, error) {
+	if err := tg.only(path, "a json.RawMessage", "example", "nullable"); err != nil {
+		return nil, err
+	}
+	if tg.example == nil {
+		return nil, fmt.Errorf("%s: a json.RawMessage requires an example", path)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(*tg.example), &value); err != nil {
+		return nil, fmt.Errorf("%s: invalid JSON example: %v", path, err)
+	}
+
+	return w.finish(Like(value), tg, path)
+}
+
+// generate adds the tag's generator to the matcher, along with the nullable option.
+// The random generator is given for the type being matched, or is empty if the type
+// has none, and generators lists any others that may be used.
+func (w *tagWalker) generate(m Matcher, tg tag, path string, random string, generators ...string) (Matcher, error) {
+	if tg.generator == "" {
+		return w.finish(m, tg, path)
+	}
+	if tg.expression != "" && tg.generator != "providerstate" {
+		return nil, fmt.Errorf("%s: generator %s does not take an expression", path, tg.generator)
+	}
+
+	allowed := tg.generator == "providerstate" || (tg.generator == "random" && random != "")
+	for _, g := range generators {
+		allowed = allowed || g == tg.generator
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%s: generator %s is not supported here", path, tg.generator)
+	}
+
+	base := toRule(m)
+	m, err := w.finish(m, tg, path)
+	if err != nil {
+		return nil, err
+	}
+	r := toRule(m)
+
+	switch tg.generator {
+	case "providerstate":
+		if tg.expression == "" {
+			return nil, fmt.Errorf("%s: generator providerstate requires an expression", path)
+		}
+		r["pact:generator:type"] = "ProviderState"
+		r["expression"] = tg.expression
+	case "uuid":
+		r["pact:generator:type"] = "Uuid"
+	case "regex":
+		regex, ok := base["regex"]
+		if !ok {
+			return nil, fmt.Errorf("%s: generator regex requires a regex or format", path)
+		}
+		r["pact:generator:type"] = "Regex"
+		r["regex"] = regex
+	case "random":
+		r["pact:generator:type"] = random
+		if tg.min != nil {
+			r["min"] = *tg.min
+		}
+		if tg.max != nil {
+			r["max"] = *tg.max
+		}
+	}
+
+	return r, nil
+}
+
+// finish applies the nullable option of the tag to the matcher
+func (w *tagWalker) finish(m Matcher, tg tag, path string) (Matcher, error) {
+	if !tg.nullable {
+		return m, nil
+	}
+
+	// A nullable value matches either its own matcher or null
+	r := toRule(m)
+	matcher := make(map[string]interface{}, len(r))
+	for k, v := range r {
+		if k != "value" && k != "pact:generator:type" {
+			matcher[k] = v
+		}
+	}
+
+	return rule{
+		"pact:matcher:type":    []interface{}{matcher, map[string]interface{}{"pact:matcher:type": "null"}},
+		"pact:matcher:combine": "OR",
+		"value":                r["value"],
+	}, nil
+}
@@ -0,0 +1,270 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchV4(t *testing.T) {
+	type base struct {
+		ID   int    `json:"id" pact:"example=10"`
+		Kind string `json:"kind"`
+	}
+	type user struct {
+		base
+		Kind      string          `json:"type"`
+		Name      string          `json:"name" pact:"example=billy,notempty"`
+		Balance   float64         `json:"balance"`
+		Active    bool            `json:"active" pact:"example=false"`
+		Roles     []string        `json:"roles" pact:"min=1,max=5,example=admin,regex=^[a-z]+$"`
+		Tags      map[string]int  `json:"tags"`
+		CreatedAt time.Time       `json:"createdAt" pact:"generator=datetime"`
+		Birthday  *string         `json:"birthday" pact:"format=date,nullable"`
+		Session   string          `json:"session" pact:"format=uuid,generator=uuid"`
+		Version   string          `json:"version,omitempty" pact:"format=semver"`
+		Extra     json.RawMessage `json:"extra" pact:"example={\"a\": 1}"`
+		Meta      interface{}     `json:"meta"`
+		Ignored   string          `json:"-"`
+		internal  string
+		Headers   map[string]string `json:"headers" pact:"nullable,example=a\\,b"`
+	}
+
+	got, err := json.Marshal(MatchV4(user{Meta: 1, internal: "ignored"}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": {"pact:matcher:type": "integer", "specification": "3.0.0", "value": 10},
+		"kind": {"pact:matcher:type": "type", "specification": "2.0.0", "value": "string"},
+		"type": {"pact:matcher:type": "type", "specification": "2.0.0", "value": "string"},
+		"name": {"pact:matcher:type": "notEmpty", "pact:specification": "4.0.0", "value": "billy"},
+		"balance": {"pact:matcher:type": "decimal", "specification": "3.0.0", "value": 1.1},
+		"active": {"pact:matcher:type": "type", "specification": "2.0.0", "value": false},
+		"roles": {
+			"pact:matcher:type": "type", "pact:specification": "3.0.0", "min": 1, "max": 5,
+			"value": [{"pact:matcher:type": "regex", "regex": "^[a-z]+$", "value": "admin"}]
+		},
+		"tags": {
+			"pact:matcher:type": "values", "pact:specification": "3.0.0",
+			"value": {"key": {"pact:matcher:type": "integer", "specification": "3.0.0", "value": 1}}
+		},
+		"createdAt": {
+			"pact:matcher:type": "regex", "regex": "^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}(\\.\\d+)?(Z|[+-]\\d{2}:\\d{2})$",
+			"pact:generator:type": "DateTime", "format": "yyyy-MM-dd'T'HH:mm:ssXXX", "value": "2000-02-01T12:30:00Z"
+		},
+		"birthday": {
+			"pact:matcher:type": [
+				{"pact:matcher:type": "date", "format": "yyyy-MM-dd"},
+				{"pact:matcher:type": "null"}
+			],
+			"pact:matcher:combine": "OR",
+			"value": "2000-02-01"
+		},
+		"session": {
+			"pact:matcher:type": "regex", "regex": "[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}",
+			"pact:generator:type": "Uuid", "value": "fc763eba-0905-41c5-a27f-3934ab26786c"
+		},
+		"version": {"pact:matcher:type": "semver", "pact:specification": "4.0.0", "value": "1.0.0"},
+		"extra": {"pact:matcher:type": "type", "specification": "2.0.0", "value": {"a": 1}},
+		"meta": {"pact:matcher:type": "integer", "specification": "3.0.0", "value": 1},
+		"headers": {
+			"pact:matcher:type": [
+				{"pact:matcher:type": "values", "pact:specification": "3.0.0"},
+				{"pact:matcher:type": "null"}
+			],
+			"pact:matcher:combine": "OR",
+			"value": {"key": {"pact:matcher:type": "type", "specification": "2.0.0", "value": "a,b"}}
+		}
+	}`, string(got))
+}
+
+func TestMatchV4Tags(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want string
+	}{
+		{
+			name: "slice",
+			src: struct {
+				Items []int `json:"items" pact:"min=2"`
+			}{},
+			want: `{"items": {"pact:matcher:type": "type", "min": 2, "value": [
+				{"pact:matcher:type": "integer", "specification": "3.0.0", "value": 1},
+				{"pact:matcher:type": "integer", "specification": "3.0.0", "value": 1}
+			]}}`,
+		},
+		{
+			name: "random integer",
+			src: struct {
+				Age int `json:"age" pact:"example=21,generator=random,min=18,max=99"`
+			}{},
+			want: `{"age": {"pact:matcher:type": "integer", "pact:generator:type": "RandomInt", "min": 18, "max": 99, "value": 21}}`,
+		},
+		{
+			name: "number format",
+			src: struct {
+				Price float32 `json:"price" pact:"format=number,generator=random"`
+			}{},
+			want: `{"price": {"pact:matcher:type": "number", "pact:generator:type": "RandomDecimal", "value": 1.1}}`,
+		},
+		{
+			name: "custom date format",
+			src: struct {
+				Date string `json:"date" pact:"format=date:dd/MM/yyyy,example=01/02/2000,generator=date"`
+			}{},
+			want: `{"date": {"pact:matcher:type": "date", "pact:specification": "3.0.0", "pact:generator:type": "Date",
+				"format": "dd/MM/yyyy", "value": "01/02/2000"}}`,
+		},
+		{
+			name: "provider state",
+			src: struct {
+				Path string `json:"path" pact:"example=/users/10,generator=providerstate:/users/${id}"`
+			}{},
+			want: `{"path": {"pact:matcher:type": "type", "pact:generator:type": "ProviderState",
+				"expression": "/users/${id}", "value": "/users/10"}}`,
+		},
+		{
+			name: "nullable random integer",
+			src: struct {
+				Count *int `json:"count" pact:"nullable,generator=random"`
+			}{},
+			want: `{"count": {
+				"pact:matcher:type": [{"pact:matcher:type": "integer"}, {"pact:matcher:type": "null"}],
+				"pact:matcher:combine": "OR", "pact:generator:type": "RandomInt", "value": 1
+			}}`,
+		},
+		{
+			name: "regex generator",
+			src: struct {
+				Code string `json:"code" pact:"example=AB1,generator=regex,regex=^[A-Z]{2}\\d$"`
+			}{},
+			want: `{"code": {"pact:matcher:type": "regex", "pact:generator:type": "Regex", "regex": "^[A-Z]{2}\\d$", "value": "AB1"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(MatchV4(tt.src))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+type node struct {
+	Children []node `json:"children"`
+}
+
+func TestMatchV4Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want string
+	}{
+		{
+			name: "cyclic type",
+			src:  node{},
+			want: "match: $.children[*]: matchers.node is a cyclic type",
+		},
+		{
+			name: "unknown option",
+			src: struct {
+				A string `json:"a" pact:"size=1"`
+			}{},
+			want: `match: $.a: invalid pact tag "size=1": unknown option "size"`,
+		},
+		{
+			name: "unsupported option",
+			src: struct {
+				A bool `json:"a" pact:"format=uuid"`
+			}{},
+			want: "match: $.a: format is not supported on a bool",
+		},
+		{
+			name: "min without a random generator",
+			src: struct {
+				A int `json:"a" pact:"min=1"`
+			}{},
+			want: "match: $.a: min and max are only supported on an integer with generator=random",
+		},
+		{
+			name: "max less than min",
+			src: struct {
+				A []int `json:"a" pact:"min=3,max=2"`
+			}{},
+			want: "match: $.a: max 2 is less than min 3",
+		},
+		{
+			name: "invalid example",
+			src: struct {
+				A int `json:"a" pact:"example=one"`
+			}{},
+			want: `match: $.a: invalid example "one" for an integer`,
+		},
+		{
+			name: "unsupported generator",
+			src: struct {
+				A string `json:"a" pact:"format=date,generator=datetime"`
+			}{},
+			want: "match: $.a: generator datetime is not supported here",
+		},
+		{
+			name: "nil interface",
+			src: struct {
+				A interface{} `json:"a"`
+			}{},
+			want: "match: $.a: unable to determine the type of interface {}, give it a value or use a concrete type",
+		},
+		{
+			name: "unhandled type",
+			src: struct {
+				A chan int `json:"a"`
+			}{},
+			want: "match: $.a: unable to match the type chan int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MatchV4E(tt.src)
+			assert.EqualError(t, err, tt.want)
+			assert.PanicsWithValue(t, tt.want, func() {
+				MatchV4(tt.src)
+			})
+		})
+	}
+}
+
+func TestMatchV3(t *testing.T) {
+	type dto struct {
+		Name string `json:"name" pact:"notempty"`
+	}
+
+	assert.PanicsWithValue(t, "match: $.name: notempty and format=semver require the V4 specification", func() {
+		MatchV3(dto{})
+	})
+	_, err := MatchV3E(dto{})
+	assert.EqualError(t, err, "match: $.name: notempty and format=semver require the V4 specification")
+	assert.Equal(t, Integer(1), MatchV3(1))
+
+	_, err = MatchV3E(nil)
+	assert.EqualError(t, err, "match: unable to match a nil value")
+}
+
+func TestParseTag(t *testing.T) {
+	tg, err := parseTag(`example=a\,b,min=1,nullable,regex=^a,b$`)
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b", *tg.example)
+	assert.Equal(t, 1, *tg.min)
+	assert.True(t, tg.nullable)
+	assert.Equal(t, "^a,b$", tg.regex)
+	assert.Equal(t, []string{"example", "min", "nullable", "regex"}, tg.options)
+
+	for _, invalid := range []string{"min=-1", "max=a", "nullable=true", "format=", "min=1,min=2"} {
+		_, err := parseTag(invalid)
+		assert.Error(t, err, fmt.Sprintf("tag %q", invalid))
+	}
+}
@@ -2,6 +2,7 @@ package matchers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
@@ -285,59 +286,137 @@ func objectToString(obj interface{}) string {
 // matcher string for it that is compatible with the Pact dsl.
 // By default, it requires slices to have a minimum of 1 element.
 // For concrete types, it uses `dsl.Like` to assert that types match.
+// time.Time values are matched as RFC3339 timestamps and interfaces by
+// the type of their value. Maps can't be matched, as the V2 specification
+// has no matcher for arbitrary keys (see MatchV3 and MatchV4). Struct fields
+// are named, skipped and flattened (if embedded) as per encoding/json.
 // Optionally, you may override these defaults by supplying custom
 // pact tags on your structs.
 //
 // Supported Tag Formats
 // Minimum Slice Size: `pact:"min=2"`
 // String RegEx:       `pact:"example=2000-01-01,regex=^\\d{4}-\\d{2}-\\d{2}$"`
+// Raw JSON:           `pact:"example={\"id\": 1}"`
+//
+// MatchV2 panics if a tag is invalid or a type can't be matched (e.g. a cyclic
+// type), and is kept for compatibility; use MatchV2E to handle these as errors.
 func MatchV2(src interface{}) Matcher {
-	return match(reflect.TypeOf(src), getDefaults())
+	m, err := MatchV2E(src)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return m
+}
+
+// MatchV2E is MatchV2, returning an error if a tag is invalid or a type can't be
+// matched, e.g. a map or a cyclic type such as `type Node struct{ Children []Node }`
+func MatchV2E(src interface{}) (Matcher, error) {
+	return match(reflect.ValueOf(src), reflect.TypeOf(src), getDefaults(), make(map[reflect.Type]bool))
 }
 
 // match recursively traverses the provided type and outputs a
 // matcher string for it that is compatible with the Pact dsl.
-func match(srcType reflect.Type, params params) Matcher {
+// The value, if valid, determines the type of any interfaces, and
+// visiting holds the structs being traversed to detect cyclic types.
+func match(src reflect.Value, srcType reflect.Type, params params, visiting map[reflect.Type]bool) (Matcher, error) {
+	if srcType == nil {
+		return nil, errors.New("match: unable to match a nil value")
+	}
+
+	switch srcType {
+	case timeType:
+		example := timeExample.Format(time.RFC3339)
+		if params.str.example != "" {
+			example = params.str.example
+		}
+		if params.str.regEx != "" {
+			return Term(example, params.str.regEx), nil
+		}
+		return Term(example, rfc3339), nil
+	case rawMessageType:
+		var example interface{}
+		if err := json.Unmarshal([]byte(params.str.example), &example); err != nil {
+			return nil, invalidPactTagError(params.str.example, fmt.Errorf("a json.RawMessage requires a JSON example: %w", err))
+		}
+		return Like(example), nil
+	}
+
 	switch srcType.Kind() {
 	case reflect.Pointer:
-		return match(srcType.Elem(), params)
+		if src.IsValid() && !src.IsNil() {
+			src = src.Elem()
+		} else {
+			src = reflect.Value{}
+		}
+		return match(src, srcType.Elem(), params, visiting)
+	case reflect.Interface:
+		if !src.IsValid() || src.IsNil() {
+			return nil, fmt.Errorf("match: unable to determine the type of %v, give it a value or use a concrete type", srcType)
+		}
+		return match(src.Elem(), src.Elem().Type(), params, visiting)
 	case reflect.Slice, reflect.Array:
-		return EachLike(match(srcType.Elem(), getDefaults()), params.slice.min)
+		var elem reflect.Value
+		if src.IsValid() && src.Len() > 0 {
+			elem = src.Index(0)
+		}
+		m, err := match(elem, srcType.Elem(), getDefaults(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return EachLike(m, params.slice.min), nil
+	case reflect.Map:
+		// Maps of any keys need EachKeyLike, which the V2 specification doesn't have
+		return nil, fmt.Errorf("match: maps can't be matched by the V2 specification, use MatchV3 or MatchV4: %v", srcType)
 	case reflect.Struct:
-		result := StructMatcher{}
+		if visiting[srcType] {
+			return nil, fmt.Errorf("match: cyclic type: %v", srcType)
+		}
+		visiting[srcType] = true
+		defer delete(visiting, srcType)
 
-		for i := 0; i < srcType.NumField(); i++ {
-			field := srcType.Field(i)
-			result[strings.Split(field.Tag.Get("json"), ",")[0]] = match(field.Type, pluckParams(field.Type, field.Tag.Get("pact")))
+		result := StructMatcher{}
+		for _, field := range jsonFields(srcType) {
+			var value reflect.Value
+			if src.IsValid() {
+				value, _ = src.FieldByIndexErr(field.index)
+			}
+			fieldParams, err := parseParams(field.typ, field.tag)
+			if err != nil {
+				return nil, err
+			}
+			if result[field.name], err = match(value, field.typ, fieldParams, visiting); err != nil {
+				return nil, err
+			}
 		}
-		return result
+		return result, nil
 	case reflect.String:
 		if params.str.regEx != "" {
-			return Term(params.str.example, params.str.regEx)
+			return Term(params.str.example, params.str.regEx), nil
 		}
 		if params.str.example != "" {
-			return Like(params.str.example)
+			return Like(params.str.example), nil
 		}
 
-		return Like("string")
+		return Like("string"), nil
 	case reflect.Bool:
 		if params.boolean.defined {
-			return Like(params.boolean.value)
+			return Like(params.boolean.value), nil
 		}
-		return Like(true)
+		return Like(true), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		if params.number.integer != 0 {
-			return Like(params.number.integer)
+			return Like(params.number.integer), nil
 		}
-		return Like(1)
+		return Like(1), nil
 	case reflect.Float32, reflect.Float64:
 		if params.number.float != 0 {
-			return Like(params.number.float)
+			return Like(params.number.float), nil
 		}
-		return Like(1.1)
+		return Like(1.1), nil
 	default:
-		panic(fmt.Sprintf("match: unhandled type: %v", srcType))
+		return nil, fmt.Errorf("match: unhandled type: %v", srcType)
 	}
 }
 
@@ -378,34 +457,53 @@ func getDefaults() params {
 	}
 }
 
-// pluckParams converts a 'pact' tag into a pactParams struct
+// pluckParams converts a 'pact' tag into a pactParams struct, and panics if the
+// tag is invalid
 // Supported Tag Formats
 // Minimum Slice Size: `pact:"min=2"`
 // String RegEx:       `pact:"example=2000-01-01,regex=^\\d{4}-\\d{2}-\\d{2}$"`
 func pluckParams(srcType reflect.Type, pactTag string) params {
+	params, err := parseParams(srcType, pactTag)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return params
+}
+
+// parseParams converts a 'pact' tag into a pactParams struct
+func parseParams(srcType reflect.Type, pactTag string) (params, error) {
 	params := getDefaults()
 	if pactTag == "" {
-		return params
+		return params, nil
 	}
 
-	switch kind := srcType.Kind(); kind {
+	for srcType.Kind() == reflect.Pointer {
+		srcType = srcType.Elem()
+	}
+	kind := srcType.Kind()
+	if srcType == timeType || srcType == rawMessageType {
+		kind = reflect.String
+	}
+
+	switch kind {
 	case reflect.Bool:
 		if _, err := fmt.Sscanf(pactTag, "example=%t", &params.boolean.value); err != nil {
-			triggerInvalidPactTagPanic(pactTag, err)
+			return params, invalidPactTagError(pactTag, err)
 		}
 		params.boolean.defined = true
 	case reflect.Float32, reflect.Float64:
 		if _, err := fmt.Sscanf(pactTag, "example=%g", &params.number.float); err != nil {
-			triggerInvalidPactTagPanic(pactTag, err)
+			return params, invalidPactTagError(pactTag, err)
 		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		if _, err := fmt.Sscanf(pactTag, "example=%d", &params.number.integer); err != nil {
-			triggerInvalidPactTagPanic(pactTag, err)
+			return params, invalidPactTagError(pactTag, err)
 		}
 	case reflect.Slice:
 		if _, err := fmt.Sscanf(pactTag, "min=%d", &params.slice.min); err != nil {
-			triggerInvalidPactTagPanic(pactTag, err)
+			return params, invalidPactTagError(pactTag, err)
 		}
 	case reflect.String:
 		fullRegex, _ := regexp.Compile(`regex=(.*)$`)
@@ -415,11 +513,11 @@ func pluckParams(srcType reflect.Type, pactTag string) params {
 			components := strings.Split(pactTag, ",regex=")
 
 			if len(components[1]) == 0 {
-				triggerInvalidPactTagPanic(pactTag, fmt.Errorf("invalid format: regex must not be empty"))
+				return params, invalidPactTagError(pactTag, fmt.Errorf("invalid format: regex must not be empty"))
 			}
 
 			if _, err := fmt.Sscanf(components[0], "example=%s", &params.str.example); err != nil {
-				triggerInvalidPactTagPanic(pactTag, err)
+				return params, invalidPactTagError(pactTag, err)
 			}
 			params.str.regEx = components[1]
 
@@ -427,16 +525,16 @@ func pluckParams(srcType reflect.Type, pactTag string) params {
 			components := strings.Split(pactTag, "example=")
 
 			if len(components) != 2 || strings.TrimSpace(components[1]) == "" {
-				triggerInvalidPactTagPanic(pactTag, fmt.Errorf("invalid format: example must not be empty"))
+				return params, invalidPactTagError(pactTag, fmt.Errorf("invalid format: example must not be empty"))
 			}
 
 			params.str.example = components[1]
 		}
 	}
 
-	return params
+	return params, nil
 }
 
-func triggerInvalidPactTagPanic(tag string, err error) {
-	panic(fmt.Sprintf("match: encountered invalid pact tag %q . . . parsing failed with error: %v", tag, err))
+func invalidPactTagError(tag string, err error) error {
+	return fmt.Errorf("match: encountered invalid pact tag %q . . . parsing failed with error: %v", tag, err)
 }
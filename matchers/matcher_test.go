@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -582,6 +583,26 @@ func TestMatch(t *testing.T) {
 		Integer int     `json:"integer" pact:"example=42"`
 		Float   float32 `json:"float" pact:"example=6.66"`
 	}
+	type embeddedDTO struct {
+		ID   int    `json:"id"`
+		Kind string `json:"kind"`
+	}
+	type dtoWithEmbedded struct {
+		embeddedDTO
+		Kind    string `json:"type"`
+		Ignored string `json:"-"`
+		hidden  string
+	}
+	type timeDTO struct {
+		Created time.Time  `json:"created"`
+		Updated *time.Time `json:"updated" pact:"example=2020-01-01T00:00:00Z"`
+	}
+	type rawDTO struct {
+		Raw json.RawMessage `json:"raw" pact:"example={\"id\": 1}"`
+	}
+	type cyclicDTO struct {
+		Children []cyclicDTO `json:"children"`
+	}
 	str := "str"
 	type args struct {
 		src interface{}
@@ -767,10 +788,75 @@ func TestMatch(t *testing.T) {
 			},
 			want: Like(1.1),
 		},
+		{
+			name: "recursive case - interface with a value",
+			args: args{
+				src: []interface{}{"a"},
+			},
+			want: EachLike(Like("string"), 1),
+		},
+		{
+			name: "recursive case - struct with embedded and skipped fields",
+			args: args{
+				src: dtoWithEmbedded{hidden: "hidden"},
+			},
+			want: StructMatcher{
+				"id":   Like(1),
+				"kind": Like("string"),
+				"type": Like("string"),
+			},
+		},
+		{
+			name: "base case - time",
+			args: args{
+				src: timeDTO{},
+			},
+			want: StructMatcher{
+				"created": Term("2000-02-01T12:30:00Z", rfc3339),
+				"updated": Term("2020-01-01T00:00:00Z", rfc3339),
+			},
+		},
+		{
+			name: "base case - json.RawMessage",
+			args: args{
+				src: rawDTO{},
+			},
+			want: StructMatcher{
+				"raw": Like(map[string]interface{}{"id": float64(1)}),
+			},
+		},
+		{
+			name: "error - json.RawMessage without an example",
+			args: args{
+				src: json.RawMessage{},
+			},
+			wantPanic: true,
+		},
+		{
+			name: "error - interface without a value",
+			args: args{
+				src: []interface{}{},
+			},
+			wantPanic: true,
+		},
+		{
+			name: "error - cyclic type",
+			args: args{
+				src: cyclicDTO{},
+			},
+			wantPanic: true,
+		},
+		{
+			name: "error - map",
+			args: args{
+				src: map[string]int{},
+			},
+			wantPanic: true,
+		},
 		{
 			name: "error - unhandled type",
 			args: args{
-				src: make(map[string]string),
+				src: make(chan string),
 			},
 			wantPanic: true,
 		},
@@ -797,6 +883,32 @@ func TestMatch(t *testing.T) {
 	}
 }
 
+func TestMatchV2E(t *testing.T) {
+	type node struct {
+		Children []node `json:"children"`
+	}
+	type tagged struct {
+		Count int `json:"count" pact:"example=many"`
+	}
+
+	_, err := MatchV2E(node{})
+	assert.EqualError(t, err, "match: cyclic type: matchers.node")
+
+	_, err = MatchV2E(struct {
+		Labels map[string]string `json:"labels"`
+	}{})
+	assert.EqualError(t, err, "match: maps can't be matched by the V2 specification, use MatchV3 or MatchV4: map[string]string")
+
+	_, err = MatchV2E(tagged{})
+	assert.ErrorContains(t, err, `match: encountered invalid pact tag "example=many"`)
+
+	m, err := MatchV2E(struct {
+		Name string `json:"name"`
+	}{})
+	assert.NoError(t, err)
+	assert.Equal(t, StructMatcher{"name": Like("string")}, m)
+}
+
 func Test_pluckParams(t *testing.T) {
 	type args struct {
 		srcType reflect.Type
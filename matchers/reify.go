@@ -0,0 +1,116 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Reify converts the matcher into the example JSON document it describes, as the
+// mock server would send it. Array matchers are given at least their minimum number
+// of elements, and matchers with a generator but no example use an example of the
+// value the generator produces.
+func Reify(m Matcher) ([]byte, error) {
+	node, err := generic(m)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := reify(node, "$")
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(example)
+}
+
+func reify(node interface{}, path string) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, ok := n["pact:matcher:type"]; ok {
+			return reifyMatcher(n, path)
+		}
+
+		result := make(map[string]interface{}, len(n))
+		for _, k := range sortedKeys(n) {
+			v, err := reify(n[k], childPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			result[k] = v
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(n))
+		for i, v := range n {
+			example, err := reify(v, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = example
+		}
+		return result, nil
+	default:
+		return node, nil
+	}
+}
+
+func reifyMatcher(obj map[string]interface{}, path string) (interface{}, error) {
+	switch obj["pact:matcher:type"] {
+	case "null":
+		return nil, nil
+	case "arrayContains":
+		variants, _ := obj["variants"].([]interface{})
+		return reify(variants, path)
+	}
+
+	value := obj["value"]
+	if generator, ok := obj["pact:generator:type"].(string); ok && (value == nil || value == "") {
+		return generatedExample(generator, obj, path)
+	}
+
+	// An array matcher has at least its minimum number of elements
+	if list, ok := value.([]interface{}); ok && len(list) > 0 {
+		if min, ok := limit(obj["min"]); ok {
+			for len(list) < min {
+				list = append(list, list[0])
+			}
+			value = list
+		}
+	}
+
+	return reify(value, path)
+}
+
+// generatedExample returns an example of the value produced by the generator
+func generatedExample(generator string, obj map[string]interface{}, path string) (interface{}, error) {
+	switch generator {
+	case "Uuid":
+		return regexFormats["uuid"].example, nil
+	case "RandomInt":
+		if min, ok := limit(obj["min"]); ok {
+			return min, nil
+		}
+		return 1, nil
+	case "RandomDecimal":
+		return 1.1, nil
+	case "RandomHexadecimal":
+		return regexFormats["hex"].example, nil
+	case "RandomString":
+		return "string", nil
+	case "RandomBoolean":
+		return true, nil
+	case "Date", "Time", "DateTime":
+		format, _ := obj["format"].(string)
+		if format == "" {
+			return timeExample.Format(map[string]string{"Date": "2006-01-02", "Time": "15:04:05", "DateTime": time.RFC3339}[generator]), nil
+		}
+		layout, err := goLayout(format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return timeExample.Format(layout), nil
+	default:
+		return nil, fmt.Errorf("%s: unable to give an example for the %s generator, the matcher needs an example value", path, generator)
+	}
+}
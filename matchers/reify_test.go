@@ -0,0 +1,79 @@
+package matchers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReify(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		want    string
+	}{
+		{
+			name: "nested matchers",
+			matcher: StructMatcher{
+				"id":      Integer(10),
+				"name":    Like("billy"),
+				"email":   Regex("billy@example.com", `^.+@.+$`),
+				"tags":    EachLike(Like("a"), 2),
+				"created": DateTimeGenerated("2020-01-01T08:00:45Z", "yyyy-MM-dd'T'HH:mm:ssXXX"),
+				"deleted": Null{},
+				"role":    "admin",
+			},
+			want: `{"id": 10, "name": "billy", "email": "billy@example.com", "tags": ["a", "a"],
+				"created": "2020-01-01T08:00:45Z", "deleted": null, "role": "admin"}`,
+		},
+		{
+			name:    "array minimum",
+			matcher: rule{"pact:matcher:type": "type", "min": 3, "value": []interface{}{Decimal(1.5)}},
+			want:    `[1.5, 1.5, 1.5]`,
+		},
+		{
+			name:    "array containing",
+			matcher: ArrayContaining([]interface{}{Like("a"), StructMatcher{"id": Integer(1)}}),
+			want:    `["a", {"id": 1}]`,
+		},
+		{
+			name:    "each key like",
+			matcher: EachKeyLike("key", StructMatcher{"key": Like(1)}),
+			want:    `{"key": 1}`,
+		},
+		{
+			name: "generator examples",
+			matcher: StructMatcher{
+				"date":  DateGenerated("", "dd/MM/yyyy"),
+				"time":  TimeGenerated("", "HH:mm"),
+				"id":    rule{"pact:matcher:type": "type", "pact:generator:type": "Uuid"},
+				"count": rule{"pact:matcher:type": "integer", "pact:generator:type": "RandomInt", "min": 5},
+			},
+			want: `{"date": "01/02/2000", "time": "12:30", "id": "fc763eba-0905-41c5-a27f-3934ab26786c", "count": 5}`,
+		},
+		{
+			name: "struct tags",
+			matcher: MatchV4(struct {
+				Age   *int    `json:"age" pact:"nullable,example=21"`
+				Price float64 `json:"price" pact:"example=9.99"`
+			}{}),
+			want: `{"age": 21, "price": 9.99}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reify(tt.matcher)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestReifyErrors(t *testing.T) {
+	_, err := Reify(StructMatcher{"id": rule{"pact:matcher:type": "regex", "pact:generator:type": "Regex", "regex": `\d+`}})
+	assert.EqualError(t, err, "$.id: unable to give an example for the Regex generator, the matcher needs an example value")
+
+	_, err = Reify(DateGenerated("", "yyyy-QQ"))
+	assert.Error(t, err)
+}
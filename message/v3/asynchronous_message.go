@@ -10,8 +10,8 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/pact-foundation/pact-go/v2/command"
 	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/pact-foundation/pact-go/v2/internal/version"
 	logging "github.com/pact-foundation/pact-go/v2/log"
 	"github.com/pact-foundation/pact-go/v2/models"
 )
@@ -165,7 +165,7 @@ func (p *AsynchronousPact) validateConfig() error {
 	}
 
 	p.messageserver = native.NewMessageServer(p.config.Consumer, p.config.Provider)
-	p.messageserver.WithMetadata("pact-go", "version", strings.TrimPrefix(command.Version, "v"))
+	p.messageserver.WithMetadata("pact-go", "version", strings.TrimPrefix(version.Version, "v"))
 
 	return nil
 }
@@ -245,7 +245,7 @@ func (p *AsynchronousPact) verifyMessageConsumerRaw(messageToVerify *Asynchronou
 		return err
 	}
 
-	return p.messageserver.WritePactFile(p.config.PactDir, false)
+	return p.messageserver.WritePactFile(p.config.PactDir, p.config.writeMode())
 }
 
 // VerifyMessageConsumer is a test convience function for VerifyMessageConsumerRaw,
@@ -1,5 +1,12 @@
 package v3
 
+import "github.com/pact-foundation/pact-go/v2/internal/pactfile"
+
+// PactConflictError is returned when a message with the same description and
+// provider state(s), but different content, has already been written to the pact
+// file by another test of the same run
+type PactConflictError = pactfile.ConflictError
+
 type Body interface{}
 type Metadata map[string]interface{}
 
@@ -20,4 +27,18 @@ type Config struct {
 	Consumer string
 	Provider string
 	PactDir  string
+
+	// OverwriteInteractions replaces messages written to the pact file with
+	// different content by other tests of the same run, instead of failing with a
+	// PactConflictError. Messages written by an earlier run are always replaced.
+	OverwriteInteractions bool
+}
+
+// writeMode is how the pact is combined with an existing pact file
+func (c Config) writeMode() pactfile.Mode {
+	if c.OverwriteInteractions {
+		return pactfile.UpdateMode
+	}
+
+	return pactfile.MergeMode
 }
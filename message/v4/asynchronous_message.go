@@ -1,6 +1,7 @@
 package v4
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,8 +11,10 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/pact-foundation/pact-go/v2/command"
+	"github.com/pact-foundation/pact-go/v2/internal/dsl"
 	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/pact-foundation/pact-go/v2/internal/runner"
+	"github.com/pact-foundation/pact-go/v2/internal/version"
 	logging "github.com/pact-foundation/pact-go/v2/log"
 	"github.com/pact-foundation/pact-go/v2/models"
 )
@@ -30,6 +33,9 @@ type AsynchronousMessageBuilder struct {
 
 	// The handler for this message
 	handler AsynchronousConsumer
+
+	// Configuration errors, returned by ExecuteTest
+	configErrors dsl.Errors
 }
 
 // Given specifies a provider state. Optional.
@@ -60,6 +66,7 @@ func (m *AsynchronousMessageBuilder) AddExternalReference(group, name, value str
 // message for the interaction to succeed.
 func (m *AsynchronousMessageBuilder) ExpectsToReceive(description string) *UnconfiguredAsynchronousMessageBuilder {
 	m.messageHandle.ExpectsToReceive(description)
+	m.configErrors.Description = description
 
 	return &UnconfiguredAsynchronousMessageBuilder{
 		rootBuilder: m,
@@ -71,12 +78,9 @@ type UnconfiguredAsynchronousMessageBuilder struct {
 }
 
 // UsingPlugin enables a plugin for use in the current test case
+// If the plugin is not correctly installed, ExecuteTest will return an error
 func (m *UnconfiguredAsynchronousMessageBuilder) UsingPlugin(config PluginConfig) *AsynchronousMessageWithPlugin {
-	err := m.rootBuilder.pact.messageserver.UsingPlugin(config.Plugin, config.Version)
-	if err != nil {
-		log.Println("[ERROR] failed to add plugin:", err)
-		panic(err)
-	}
+	usePlugin(m.rootBuilder.pact.messageserver, &m.rootBuilder.configErrors, config)
 
 	return &AsynchronousMessageWithPlugin{
 		rootBuilder: m.rootBuilder,
@@ -91,7 +95,7 @@ func (s *AsynchronousMessageWithPlugin) WithContents(contents string, contentTyp
 	err := s.rootBuilder.messageHandle.WithPluginInteractionContents(native.INTERACTION_PART_REQUEST, contentType, contents)
 	if err != nil {
 		log.Println("[ERROR] failed to get plugin content from message handle:", err)
-		panic(err)
+		s.rootBuilder.configErrors.Add("contents", err)
 	}
 
 	return &AsynchronousMessageWithPluginContents{
@@ -104,27 +108,45 @@ type AsynchronousMessageWithPluginContents struct {
 }
 
 func (s *AsynchronousMessageWithPluginContents) ExecuteTest(t *testing.T, integrationTest func(m AsynchronousMessage) error) error {
+	return s.ExecuteTestContext(context.Background(), t, func(_ context.Context, m AsynchronousMessage) error {
+		return integrationTest(m)
+	})
+}
+
+// ExecuteTestContext runs the current test case as per ExecuteTest, returning a
+// *TimeoutError if ctx expires before the integration test completes
+//
+// The integration test runs in its own goroutine if ctx can expire, so it must not
+// call t.FailNow (or t.Fatal, require.*, etc.); return an error instead.
+func (s *AsynchronousMessageWithPluginContents) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(ctx context.Context, m AsynchronousMessage) error) error {
 	defer s.rootBuilder.pact.messageserver.CleanupPlugins()
+	if err := s.rootBuilder.configErrors.Err(); err != nil {
+		return err
+	}
+
 	message, err := getAsynchronousMessageWithReifiedContents(s.rootBuilder.messageHandle, s.rootBuilder.Type)
 	if err != nil {
 		return err
 	}
 
 	fmt.Println()
-	err = integrationTest(message)
+	err = runner.Run(ctx, t.Name(), func(ctx context.Context) error {
+		return integrationTest(ctx, message)
+	})
 
 	if err != nil {
 		return err
 	}
 
-	return s.rootBuilder.pact.messageserver.WritePactFile(s.rootBuilder.pact.config.PactDir, false)
+	return s.rootBuilder.pact.messageserver.WritePactFile(s.rootBuilder.pact.config.PactDir, s.rootBuilder.pact.config.writeMode())
 }
 
 func (s *AsynchronousMessageWithPluginContents) StartTransport(transport string, address string, config map[string][]interface{}) *AsynchronousMessageWithTransport {
 	port, err := s.rootBuilder.pact.messageserver.StartTransport(transport, address, 0, make(map[string][]interface{}))
 
 	if err != nil {
-		log.Fatalln("unable to start plugin transport:", err)
+		log.Println("[ERROR] unable to start plugin transport:", err)
+		s.rootBuilder.configErrors.Add("transport", err)
 	}
 
 	return &AsynchronousMessageWithTransport{
@@ -142,14 +164,32 @@ type AsynchronousMessageWithTransport struct {
 }
 
 func (s *AsynchronousMessageWithTransport) ExecuteTest(t *testing.T, integrationTest func(tc TransportConfig, m AsynchronousMessage) error) error {
+	return s.ExecuteTestContext(context.Background(), t, func(_ context.Context, tc TransportConfig, m AsynchronousMessage) error {
+		return integrationTest(tc, m)
+	})
+}
+
+// ExecuteTestContext runs the current test case as per ExecuteTest. If ctx expires
+// before the integration test completes, the transport is shut down and a
+// *TimeoutError is returned.
+//
+// The integration test runs in its own goroutine if ctx can expire, so it must not
+// call t.FailNow (or t.Fatal, require.*, etc.); return an error instead.
+func (s *AsynchronousMessageWithTransport) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(ctx context.Context, tc TransportConfig, m AsynchronousMessage) error) error {
 	defer s.rootBuilder.pact.messageserver.CleanupMockServer(s.transport.Port)
 	defer s.rootBuilder.pact.messageserver.CleanupPlugins()
+	if err := s.rootBuilder.configErrors.Err(); err != nil {
+		return err
+	}
+
 	message, err := getAsynchronousMessageWithReifiedContents(s.rootBuilder.messageHandle, s.rootBuilder.Type)
 	if err != nil {
 		return err
 	}
 
-	err = integrationTest(s.transport, message)
+	err = runner.Run(ctx, t.Name(), func(ctx context.Context) error {
+		return integrationTest(ctx, s.transport, message)
+	})
 
 	if err != nil {
 		return err
@@ -161,7 +201,7 @@ func (s *AsynchronousMessageWithTransport) ExecuteTest(t *testing.T, integration
 		return fmt.Errorf("pact validation failed: %+v", mismatches)
 	}
 
-	return s.rootBuilder.pact.messageserver.WritePactFileForServer(s.transport.Port, s.rootBuilder.pact.config.PactDir, false)
+	return s.rootBuilder.pact.messageserver.WritePactFileForServer(s.transport.Port, s.rootBuilder.pact.config.PactDir, s.rootBuilder.pact.config.writeMode())
 }
 
 // WithMetadata specifies message-implementation specific metadata
@@ -223,6 +263,15 @@ func (m *AsynchronousMessageWithConsumer) Verify(t *testing.T) error {
 	return m.rootBuilder.pact.Verify(t, m.rootBuilder, m.rootBuilder.handler)
 }
 
+// VerifyContext verifies the message as per Verify, returning a *TimeoutError if
+// ctx expires before the consumer has handled the message
+//
+// The consumer runs in its own goroutine if ctx can expire, so it must not call
+// t.FailNow (or t.Fatal, require.*, etc.); return an error instead.
+func (m *AsynchronousMessageWithConsumer) VerifyContext(ctx context.Context, t *testing.T) error {
+	return m.rootBuilder.pact.VerifyContext(ctx, t, m.rootBuilder, m.rootBuilder.handler)
+}
+
 type AsynchronousPact struct {
 	config Config
 
@@ -256,7 +305,7 @@ func (p *AsynchronousPact) validateConfig() error {
 
 	p.messageserver = native.NewMessageServer(p.config.Consumer, p.config.Provider)
 	p.messageserver.WithSpecificationVersion(native.SPECIFICATION_VERSION_V4)
-	p.messageserver.WithMetadata("pact-go", "version", strings.TrimPrefix(command.Version, "v"))
+	p.messageserver.WithMetadata("pact-go", "version", strings.TrimPrefix(version.Version, "v"))
 
 	return nil
 }
@@ -285,7 +334,7 @@ func (p *AsynchronousPact) AddAsynchronousMessage() *AsynchronousMessageBuilder
 // A Message Consumer is analagous to a Provider in the HTTP Interaction model.
 // It is the receiver of an interaction, and needs to be able to handle whatever
 // request was provided.
-func (p *AsynchronousPact) verifyMessageConsumerRaw(messageToVerify *AsynchronousMessageBuilder, handler AsynchronousConsumer) error {
+func (p *AsynchronousPact) verifyMessageConsumerRaw(ctx context.Context, testName string, messageToVerify *AsynchronousMessageBuilder, handler AsynchronousConsumer) error {
 	log.Printf("[DEBUG] verify message")
 
 	m, err := getAsynchronousMessageWithReifiedContents(messageToVerify.messageHandle, messageToVerify.Type)
@@ -294,19 +343,27 @@ func (p *AsynchronousPact) verifyMessageConsumerRaw(messageToVerify *Asynchronou
 	}
 
 	// Yield message, and send through handler function
-	err = handler(m)
+	err = runner.Run(ctx, testName, func(context.Context) error {
+		return handler(m)
+	})
 
 	if err != nil {
 		return err
 	}
 
-	return p.messageserver.WritePactFile(p.config.PactDir, false)
+	return p.messageserver.WritePactFile(p.config.PactDir, p.config.writeMode())
 }
 
 // VerifyMessageConsumer is a test convience function for VerifyMessageConsumerRaw,
 // accepting an instance of `*testing.T`
 func (p *AsynchronousPact) Verify(t *testing.T, message *AsynchronousMessageBuilder, handler AsynchronousConsumer) error {
-	err := p.verifyMessageConsumerRaw(message, handler)
+	return p.VerifyContext(context.Background(), t, message, handler)
+}
+
+// VerifyContext verifies the message as per Verify, returning a *TimeoutError if
+// ctx expires before the handler returns
+func (p *AsynchronousPact) VerifyContext(ctx context.Context, t *testing.T, message *AsynchronousMessageBuilder, handler AsynchronousConsumer) error {
+	err := p.verifyMessageConsumerRaw(ctx, t.Name(), message, handler)
 
 	if err != nil {
 		t.Errorf("VerifyMessageConsumer failed: %v", err)
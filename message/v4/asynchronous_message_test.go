@@ -1,8 +1,10 @@
 package v4
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pact-foundation/pact-go/v2/log"
 	"github.com/stretchr/testify/assert"
@@ -40,6 +42,29 @@ func TestAsyncTypeSystem(t *testing.T) {
 
 }
 
+func TestAsyncVerifyContext(t *testing.T) {
+	p, _ := NewAsynchronousPact(Config{
+		Consumer: "asyncconsumer",
+		Provider: "asyncprovider",
+		PactDir:  t.TempDir(),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	consumed := false
+	err := p.AddAsynchronousMessage().
+		ExpectsToReceive("a message consumed within the deadline").
+		WithJSONContent(map[string]string{"foo": "bar"}).
+		ConsumedBy(func(mc AsynchronousMessage) error {
+			consumed = true
+			return nil
+		}).
+		VerifyContext(ctx, t)
+
+	assert.NoError(t, err)
+	assert.True(t, consumed)
+}
+
 func TestAsyncAddExternalReference(t *testing.T) {
 	p, _ := NewAsynchronousPact(Config{
 		Consumer: "asyncconsumer",
@@ -1,5 +1,24 @@
 package v4
 
+import (
+	"github.com/pact-foundation/pact-go/v2/internal/dsl"
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+	"github.com/pact-foundation/pact-go/v2/internal/runner"
+)
+
+// PactConflictError is returned when a message with the same description and
+// provider state(s), but different content, has already been written to the pact
+// file by another test of the same run
+type PactConflictError = pactfile.ConflictError
+
+// TimeoutError is returned by ExecuteTestContext when the context expires, or is
+// cancelled, before the integration test completes
+type TimeoutError = runner.TimeoutError
+
+// InteractionError is returned by ExecuteTest when a message was configured
+// incorrectly, for example with a plugin that is not installed
+type InteractionError = dsl.InteractionError
+
 type Metadata map[string]interface{}
 
 // AsynchronousMessage is a representation of a single, unidirectional message
@@ -28,4 +47,18 @@ type Config struct {
 	Consumer string
 	Provider string
 	PactDir  string
+
+	// OverwriteInteractions replaces messages written to the pact file with
+	// different content by other tests of the same run, instead of failing with a
+	// PactConflictError. Messages written by an earlier run are always replaced.
+	OverwriteInteractions bool
+}
+
+// writeMode is how the pact is combined with an existing pact file
+func (c Config) writeMode() pactfile.Mode {
+	if c.OverwriteInteractions {
+		return pactfile.UpdateMode
+	}
+
+	return pactfile.MergeMode
 }
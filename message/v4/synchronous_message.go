@@ -1,6 +1,8 @@
 package v4
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -8,8 +10,10 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/pact-foundation/pact-go/v2/command"
+	"github.com/pact-foundation/pact-go/v2/internal/dsl"
 	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/pact-foundation/pact-go/v2/internal/runner"
+	"github.com/pact-foundation/pact-go/v2/internal/version"
 	logging "github.com/pact-foundation/pact-go/v2/log"
 	"github.com/pact-foundation/pact-go/v2/models"
 )
@@ -44,6 +48,7 @@ func (m *UnconfiguredSynchronousMessageBuilder) Given(state string) *Unconfigure
 	return &UnconfiguredSynchronousMessageBuilder{
 		pact:          m.pact,
 		messageHandle: m.messageHandle,
+		configErrors:  m.configErrors,
 	}
 }
 
@@ -54,12 +59,14 @@ func (m *UnconfiguredSynchronousMessageBuilder) GivenWithParameter(state models.
 	return &UnconfiguredSynchronousMessageBuilder{
 		pact:          m.pact,
 		messageHandle: m.messageHandle,
+		configErrors:  m.configErrors,
 	}
 }
 
 type UnconfiguredSynchronousMessageBuilder struct {
 	messageHandle *native.Message
 	pact          *SynchronousPact
+	configErrors  *dsl.Errors
 }
 
 // AddExternalReference records a reference to an external resource (such as a ticket or
@@ -72,26 +79,21 @@ func (m *UnconfiguredSynchronousMessageBuilder) AddExternalReference(group, name
 }
 
 // UsingPlugin enables a plugin for use in the current test case
+// If the plugin is not correctly installed, ExecuteTest will return an error
 func (m *UnconfiguredSynchronousMessageBuilder) UsingPlugin(config PluginConfig) *SynchronousMessageWithPlugin {
-	err := m.pact.mockserver.UsingPlugin(config.Plugin, config.Version)
-	if err != nil {
-		log.Println("[ERROR] failed to add plugin:", err)
-		panic(err)
-	}
+	usePlugin(m.pact.mockserver, m.configErrors, config)
 
 	return &SynchronousMessageWithPlugin{
 		pact:          m.pact,
 		messageHandle: m.messageHandle,
+		configErrors:  m.configErrors,
 	}
 }
 
 // UsingPlugin enables a plugin for use in the current test case
+// If the plugin is not correctly installed, ExecuteTest will return an error
 func (m *SynchronousMessageWithPlugin) UsingPlugin(config PluginConfig) *SynchronousMessageWithPlugin {
-	err := m.pact.mockserver.UsingPlugin(config.Plugin, config.Version)
-	if err != nil {
-		log.Println("[ERROR] failed to add plugin:", err)
-		panic(err)
-	}
+	usePlugin(m.pact.mockserver, m.configErrors, config)
 
 	return m
 }
@@ -198,51 +200,75 @@ func (m *SynchronousMessageWithResponseBuilder) WithJSONContent(content interfac
 type SynchronousMessageWithPlugin struct {
 	messageHandle *native.Message
 	pact          *SynchronousPact
+	configErrors  *dsl.Errors
 }
 
 func (s *SynchronousMessageWithPlugin) WithContents(contents string, contentType string) *SynchronousMessageWithPluginContents {
-	_ = s.messageHandle.WithPluginInteractionContents(native.INTERACTION_PART_REQUEST, contentType, contents)
+	if err := s.messageHandle.WithPluginInteractionContents(native.INTERACTION_PART_REQUEST, contentType, contents); err != nil {
+		log.Println("[ERROR] failed to get plugin content from message handle:", err)
+		s.configErrors.Add("contents", err)
+	}
 
 	return &SynchronousMessageWithPluginContents{
 		pact:          s.pact,
 		messageHandle: s.messageHandle,
+		configErrors:  s.configErrors,
 	}
 }
 
 type SynchronousMessageWithPluginContents struct {
 	messageHandle *native.Message
 	pact          *SynchronousPact
+	configErrors  *dsl.Errors
 }
 
 // ExecuteTest runs the current test case against a Mock Service.
 // Will cleanup interactions between tests within a suite
 // and write the pact file if successful
 func (m *SynchronousMessageWithPluginContents) ExecuteTest(t *testing.T, integrationTest func(m SynchronousMessage) error) error {
+	return m.ExecuteTestContext(context.Background(), t, func(_ context.Context, md SynchronousMessage) error {
+		return integrationTest(md)
+	})
+}
+
+// ExecuteTestContext runs the current test case as per ExecuteTest, returning a
+// *TimeoutError if ctx expires before the integration test completes
+//
+// The integration test runs in its own goroutine if ctx can expire, so it must not
+// call t.FailNow (or t.Fatal, require.*, etc.); return an error instead.
+func (m *SynchronousMessageWithPluginContents) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(ctx context.Context, m SynchronousMessage) error) error {
 	defer m.pact.mockserver.CleanupPlugins()
+	if err := m.configErrors.Err(); err != nil {
+		return err
+	}
 	message, err := getSynchronousMessageWithContents(m.messageHandle)
 	if err != nil {
 		return err
 	}
 
-	err = integrationTest(message)
+	err = runner.Run(ctx, t.Name(), func(ctx context.Context) error {
+		return integrationTest(ctx, message)
+	})
 
 	if err != nil {
 		return err
 	}
 
-	return m.pact.mockserver.WritePactFile(m.pact.config.PactDir, false)
+	return m.pact.mockserver.WritePactFile(m.pact.config.PactDir, m.pact.config.writeMode())
 }
 
 func (s *SynchronousMessageWithPluginContents) StartTransport(transport string, address string, config map[string][]interface{}) *SynchronousMessageWithTransport {
 	port, err := s.pact.mockserver.StartTransport(transport, address, 0, make(map[string][]interface{}))
 
 	if err != nil {
-		log.Fatalln("unable to start plugin transport:", err)
+		log.Println("[ERROR] unable to start plugin transport:", err)
+		s.configErrors.Add("transport", err)
 	}
 
 	return &SynchronousMessageWithTransport{
 		pact:          s.pact,
 		messageHandle: s.messageHandle,
+		configErrors:  s.configErrors,
 		transport: TransportConfig{
 			Port:    port,
 			Address: address,
@@ -253,18 +279,43 @@ func (s *SynchronousMessageWithPluginContents) StartTransport(transport string,
 type SynchronousMessageWithTransport struct {
 	messageHandle *native.Message
 	pact          *SynchronousPact
+	configErrors  *dsl.Errors
 	transport     TransportConfig
 }
 
 func (s *SynchronousMessageWithTransport) ExecuteTest(t *testing.T, integrationTest func(tc TransportConfig, m SynchronousMessage) error) error {
+	return s.ExecuteTestContext(context.Background(), t, func(_ context.Context, tc TransportConfig, m SynchronousMessage) error {
+		return integrationTest(tc, m)
+	})
+}
+
+// ExecuteTestContext runs the current test case as per ExecuteTest. If ctx expires
+// before the integration test completes, the transport is shut down and a
+// *TimeoutError is returned.
+//
+// The integration test runs in its own goroutine if ctx can expire, so it must not
+// call t.FailNow (or t.Fatal, require.*, etc.); return an error instead.
+func (s *SynchronousMessageWithTransport) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(ctx context.Context, tc TransportConfig, m SynchronousMessage) error) error {
 	defer s.pact.mockserver.CleanupMockServer(s.transport.Port)
 	defer s.pact.mockserver.CleanupPlugins()
+	if err := s.configErrors.Err(); err != nil {
+		return err
+	}
+
 	message, err := getSynchronousMessageWithContents(s.messageHandle)
 	if err != nil {
 		return err
 	}
 
-	err = integrationTest(s.transport, message)
+	err = runner.Run(ctx, t.Name(), func(ctx context.Context) error {
+		return integrationTest(ctx, s.transport, message)
+	})
+
+	// The test was abandoned, so any mismatches are incidental
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return err
+	}
 
 	// matched := s.pact.mockserver.MockServerMatched(s.transport.Port)
 	// log.Println("MATHED??????????", matched)
@@ -278,7 +329,7 @@ func (s *SynchronousMessageWithTransport) ExecuteTest(t *testing.T, integrationT
 		return err
 	}
 
-	return s.pact.mockserver.WritePactFileForServer(s.transport.Port, s.pact.config.PactDir, false)
+	return s.pact.mockserver.WritePactFileForServer(s.transport.Port, s.pact.config.PactDir, s.pact.config.writeMode())
 }
 
 type PluginConfig struct {
@@ -312,7 +363,7 @@ func (m *SynchronousPact) validateConfig() error {
 
 	m.mockserver = native.NewMessageServer(m.config.Consumer, m.config.Provider)
 	m.mockserver.WithSpecificationVersion(native.SPECIFICATION_VERSION_V4)
-	m.mockserver.WithMetadata("pact-go", "version", strings.TrimPrefix(command.Version, "v"))
+	m.mockserver.WithMetadata("pact-go", "version", strings.TrimPrefix(version.Version, "v"))
 
 	return nil
 }
@@ -325,6 +376,7 @@ func (m *SynchronousPact) AddSynchronousMessage(description string) *Unconfigure
 	return &UnconfiguredSynchronousMessageBuilder{
 		messageHandle: message,
 		pact:          m,
+		configErrors:  &dsl.Errors{Description: description},
 	}
 }
 
@@ -332,18 +384,39 @@ func (m *SynchronousPact) AddSynchronousMessage(description string) *Unconfigure
 // Will cleanup interactions between tests within a suite
 // and write the pact file if successful
 func (m *SynchronousMessageWithResponse) ExecuteTest(t *testing.T, integrationTest func(md SynchronousMessage) error) error {
+	return m.ExecuteTestContext(context.Background(), t, func(_ context.Context, md SynchronousMessage) error {
+		return integrationTest(md)
+	})
+}
+
+// ExecuteTestContext runs the current test case as per ExecuteTest, returning a
+// *TimeoutError if ctx expires before the integration test completes
+//
+// The integration test runs in its own goroutine if ctx can expire, so it must not
+// call t.FailNow (or t.Fatal, require.*, etc.); return an error instead.
+func (m *SynchronousMessageWithResponse) ExecuteTestContext(ctx context.Context, t *testing.T, integrationTest func(ctx context.Context, md SynchronousMessage) error) error {
 	message, err := getSynchronousMessageWithContents(m.messageHandle)
 	if err != nil {
 		return err
 	}
 
-	err = integrationTest(message)
+	err = runner.Run(ctx, t.Name(), func(ctx context.Context) error {
+		return integrationTest(ctx, message)
+	})
 
 	if err != nil {
 		return err
 	}
 
-	return m.pact.mockserver.WritePactFile(m.pact.config.PactDir, false)
+	return m.pact.mockserver.WritePactFile(m.pact.config.PactDir, m.pact.config.writeMode())
+}
+
+// usePlugin enables a plugin, recording an error against the message if it is not available
+func usePlugin(server *native.MessageServer, configErrors *dsl.Errors, config PluginConfig) {
+	if err := server.UsingPlugin(config.Plugin, config.Version); err != nil {
+		log.Println("[ERROR] failed to add plugin:", err)
+		configErrors.Add("plugin", fmt.Errorf("unable to use plugin %s %s: %w", config.Plugin, config.Version, err))
+	}
 }
 
 func getSynchronousMessageWithContents(message *native.Message) (SynchronousMessage, error) {
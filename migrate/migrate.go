@@ -0,0 +1,276 @@
+// Package migrate upgrades pacts to a later specification version, e.g. the V2
+// pacts of NewV2Pact to V4.
+//
+// Interactions, provider states, matching rules and generators are converted to the
+// layout of the later version. Anything that cannot be converted without changing
+// what the pact means (for example, a V2 matching rule path that later versions do
+// not support) is kept as it was, and reported as an Issue.
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// ParseVersion reads a specification version to migrate to, e.g. v4, 4 or 4.0.0
+func ParseVersion(s string) (models.SpecificationVersion, error) {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v") {
+	case "3", "3.0", "3.0.0":
+		return models.V3, nil
+	case "4", "4.0", "4.0.0":
+		return models.V4, nil
+	}
+
+	return "", fmt.Errorf("invalid specification version %q, expected one of v3 or v4", s)
+}
+
+// Issue is a part of a pact that could not be converted losslessly
+type Issue struct {
+	// Interaction is the description of the interaction, if the issue is with an
+	// interaction
+	Interaction string `json:"interaction,omitempty"`
+
+	// Location is the part of the interaction, e.g. response.body or
+	// request.matchingRules.body.$..name
+	Location string `json:"location,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{i.Interaction, i.Location, i.Message} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return strings.Join(parts, ": ")
+}
+
+// Report is the result of migrating a pact
+type Report struct {
+	// Pact is the path of the pact file, and Output the path it was written to, if
+	// the pact was read from a file
+	Pact   string `json:"pact,omitempty"`
+	Output string `json:"output,omitempty"`
+
+	From models.SpecificationVersion `json:"from"`
+	To   models.SpecificationVersion `json:"to"`
+
+	Issues []Issue `json:"issues"`
+}
+
+// Lossless reports whether the pact was converted without any issues
+func (r *Report) Lossless() bool {
+	return len(r.Issues) == 0
+}
+
+// MigrateFile migrates the pact file at path to the specification version, and
+// writes it to output, which may be the same path
+func MigrateFile(path string, output string, to models.SpecificationVersion) (*Report, error) {
+	pact, err := models.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := Migrate(pact, to)
+	if err != nil {
+		return nil, fmt.Errorf("unable to migrate pact file %s: %w", path, err)
+	}
+	report.Pact = path
+	report.Output = output
+
+	if err := pact.Save(output); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Migrate converts the pact, in place, to the specification version (V3 or V4),
+// which may not be earlier than the version of the pact
+func Migrate(pact *models.PactFile, to models.SpecificationVersion) (*Report, error) {
+	target := major(to)
+	if target < 3 {
+		return nil, fmt.Errorf("pacts may only be migrated to V3 or V4, not %s", to)
+	}
+
+	from := pact.Version()
+	if major(from) > target {
+		return nil, fmt.Errorf("unable to migrate a %s pact to the earlier version %s", from, to)
+	}
+
+	report := &Report{From: from, To: to, Issues: make([]Issue, 0)}
+	for _, i := range pact.Interactions {
+		m := &migration{description: i.Details().Description, from: major(from), to: target}
+
+		switch t := i.(type) {
+		case *models.HTTPInteraction:
+			m.rules("request", t.Request.MatchingRules, models.CategoryPath, models.CategoryQuery, models.CategoryHeader, models.CategoryBody)
+			m.body("request.body", t.Request.Body, header(t.Request.Headers, "Content-Type"))
+			m.rules("response", t.Response.MatchingRules, models.CategoryStatus, models.CategoryHeader, models.CategoryBody)
+			m.body("response.body", t.Response.Body, header(t.Response.Headers, "Content-Type"))
+		case *models.AsynchronousMessage:
+			m.message("message", t.Message)
+		case *models.SynchronousMessage:
+			m.message("request", t.Request)
+			for n, r := range t.Response {
+				m.message(fmt.Sprintf("response[%d]", n), r)
+			}
+		}
+
+		report.Issues = append(report.Issues, m.issues...)
+	}
+
+	pact.Metadata.SpecificationVersion = to
+
+	return report, nil
+}
+
+// migration converts the parts of an interaction that are not converted by writing
+// it in the layout of the later version
+type migration struct {
+	description string
+	from, to    int
+	issues      []Issue
+}
+
+func (m *migration) issue(location string, format string, args ...interface{}) {
+	m.issues = append(m.issues, Issue{
+		Interaction: m.description,
+		Location:    location,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+func (m *migration) message(prefix string, msg models.Message) {
+	m.rules(prefix, msg.MatchingRules, models.CategoryMetadata, models.CategoryBody)
+
+	contentType := ""
+	for _, k := range []string{"contentType", "content-type", "Content-Type"} {
+		if s, ok := msg.Metadata[k].(string); ok {
+			contentType = s
+			break
+		}
+	}
+	m.body(prefix+".contents", msg.Contents, contentType)
+}
+
+// rules gives V2 matchers their type, and reports rules that the later version does
+// not support
+func (m *migration) rules(prefix string, rules models.MatchingRules, categories ...string) {
+	supported := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		supported[c] = true
+	}
+
+	for _, category := range sortedKeys(rules) {
+		location := prefix + ".matchingRules." + category
+		if !supported[category] {
+			m.issue(location, "the %s category of matching rules is not supported, and is kept as it was", category)
+			continue
+		}
+
+		for _, key := range sortedKeys(rules[category]) {
+			rule := rules[category][key]
+			if rule == nil {
+				continue
+			}
+			if category == models.CategoryBody {
+				if _, err := matchingrules.ParsePath(key); err != nil {
+					m.issue(location+"."+key, "the path is not supported by V3 or V4 matching rules, and is kept as it was")
+				}
+			}
+
+			// V2 matchers may only give a regex, or the min and max of a type matcher
+			for _, matcher := range rule.Matchers {
+				if _, ok := matcher["match"]; ok {
+					continue
+				}
+				if _, ok := matcher["regex"]; ok {
+					matcher["match"] = "regex"
+				} else {
+					matcher["match"] = "type"
+				}
+			}
+		}
+	}
+}
+
+// body describes the content of a V2 or V3 body, as V4 bodies are
+func (m *migration) body(location string, body *models.Body, contentType string) {
+	if body == nil || m.to < 4 || m.from >= 4 {
+		return
+	}
+
+	if contentType == "" {
+		switch body.Content.(type) {
+		case map[string]interface{}, []interface{}:
+			contentType = "application/json"
+		case string:
+			contentType = "text/plain"
+		default:
+			// Numbers, booleans and null are JSON documents too
+			contentType = "application/json"
+		}
+	}
+	body.ContentType = contentType
+	body.Encoded = false
+
+	if _, ok := body.Content.(string); ok && !textual(contentType) {
+		m.issue(location, "the body has the binary content type %s, but it is not known whether its content was base64 encoded, so it is kept as text", contentType)
+	}
+}
+
+// textual reports whether a content type is text, rather than binary
+func textual(contentType string) bool {
+	t := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	if strings.HasPrefix(t, "text/") {
+		return true
+	}
+	for _, s := range []string{"json", "xml", "javascript", "x-www-form-urlencoded", "yaml"} {
+		if strings.Contains(t, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func header(headers map[string][]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return v[0]
+		}
+	}
+
+	return ""
+}
+
+func major(v models.SpecificationVersion) int {
+	switch s := string(v); {
+	case strings.HasPrefix(s, "1"), strings.HasPrefix(s, "2"):
+		return 2
+	case strings.HasPrefix(s, "3"):
+		return 3
+	case strings.HasPrefix(s, "4"):
+		return 4
+	}
+
+	return 0
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
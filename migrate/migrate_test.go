@@ -0,0 +1,157 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "web-users.json")
+
+	report, err := MigrateFile(filepath.Join("testdata", "v2.json"), output, models.V4)
+	assert.NoError(t, err)
+	assert.Equal(t, models.V2, report.From)
+	assert.Equal(t, models.SpecificationVersion(models.V4), report.To)
+	assert.Equal(t, []Issue{
+		{
+			Interaction: "a request to create a user",
+			Location:    "response.matchingRules.body.$..name",
+			Message:     "the path is not supported by V3 or V4 matching rules, and is kept as it was",
+		},
+	}, report.Issues)
+	assert.False(t, report.Lossless())
+
+	data, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"consumer": {"name": "web"},
+		"provider": {"name": "users"},
+		"interactions": [{
+			"type": "Synchronous/HTTP",
+			"description": "a request to create a user",
+			"pending": false,
+			"providerStates": [{"name": "no users exist"}],
+			"request": {
+				"method": "POST",
+				"path": "/users",
+				"query": {"dry-run": ["true"]},
+				"headers": {"Content-Type": ["application/json"]},
+				"body": {"content": {"name": "billy"}, "contentType": "application/json", "encoded": false},
+				"matchingRules": {
+					"query": {"dry-run": {"matchers": [{"match": "regex", "regex": "true|false"}]}},
+					"body": {"$.name": {"matchers": [{"match": "type"}]}}
+				}
+			},
+			"response": {
+				"status": 201,
+				"headers": {"Content-Type": ["application/json"], "Location": ["/users/1"]},
+				"body": {"content": {"id": 1, "name": "billy", "friends": [{"name": "sally"}]}, "contentType": "application/json", "encoded": false},
+				"matchingRules": {
+					"header": {"Location": {"matchers": [{"match": "regex", "regex": "^/users/\\d+$"}]}},
+					"body": {
+						"$.id": {"matchers": [{"match": "type"}]},
+						"$.friends": {"matchers": [{"match": "type", "min": 1}]},
+						"$..name": {"matchers": [{"match": "type"}]}
+					}
+				}
+			}
+		}],
+		"metadata": {"pactSpecification": {"version": "4.0.0"}, "pact-go": {"version": "2.0.0"}}
+	}`, string(data))
+
+	_, err = MigrateFile(filepath.Join("testdata", "missing.json"), output, models.V4)
+	assert.Error(t, err)
+}
+
+func TestMigrateMessages(t *testing.T) {
+	pact, err := models.ParsePactFile([]byte(`{
+		"consumer": {"name": "worker"},
+		"provider": {"name": "users"},
+		"messages": [
+			{"description": "a user event", "providerStates": [{"name": "a user", "params": {"id": 1}}],
+			 "contents": {"id": 1}, "metadata": {"contentType": "application/json", "topic": "users"},
+			 "matchingRules": {"body": {"$.id": {"matchers": [{"match": "integer"}]}}},
+			 "generators": {"body": {"$.id": {"type": "RandomInt", "min": 1, "max": 10}}}},
+			{"description": "a user avatar", "contents": "iVBORw0KGgo=", "metadata": {"contentType": "image/png"}}
+		],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`))
+	assert.NoError(t, err)
+
+	report, err := Migrate(pact, models.V4)
+	assert.NoError(t, err)
+	assert.Equal(t, []Issue{
+		{
+			Interaction: "a user avatar",
+			Location:    "message.contents",
+			Message:     "the body has the binary content type image/png, but it is not known whether its content was base64 encoded, so it is kept as text",
+		},
+	}, report.Issues)
+
+	data, err := pact.MarshalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"consumer": {"name": "worker"},
+		"provider": {"name": "users"},
+		"interactions": [
+			{"type": "Asynchronous/Messages", "description": "a user event", "pending": false,
+			 "providerStates": [{"name": "a user", "params": {"id": 1}}],
+			 "contents": {"content": {"id": 1}, "contentType": "application/json", "encoded": false},
+			 "metadata": {"contentType": "application/json", "topic": "users"},
+			 "matchingRules": {"body": {"$.id": {"matchers": [{"match": "integer"}]}}},
+			 "generators": {"body": {"$.id": {"type": "RandomInt", "min": 1, "max": 10}}}},
+			{"type": "Asynchronous/Messages", "description": "a user avatar", "pending": false,
+			 "contents": {"content": "iVBORw0KGgo=", "contentType": "image/png", "encoded": false},
+			 "metadata": {"contentType": "image/png"}}
+		],
+		"metadata": {"pactSpecification": {"version": "4.0.0"}}
+	}`, string(data))
+}
+
+func TestMigrate(t *testing.T) {
+	v3 := `{"interactions": [{"description": "a request", "providerStates": [{"name": "a user"}],
+		"request": {"method": "GET", "path": "/", "matchingRules": {"method": {"": {"matchers": [{"match": "type"}]}}}},
+		"response": {"status": 200, "body": "OK"}}], "metadata": {"pactSpecification": {"version": "3.0.0"}}}`
+
+	pact, err := models.ParsePactFile([]byte(v3))
+	assert.NoError(t, err)
+	report, err := Migrate(pact, models.V4)
+	assert.NoError(t, err)
+	assert.Equal(t, []Issue{
+		{Interaction: "a request", Location: "request.matchingRules.method", Message: "the method category of matching rules is not supported, and is kept as it was"},
+	}, report.Issues)
+	assert.Equal(t, "text/plain", pact.Interactions[0].(*models.HTTPInteraction).Response.Body.ContentType)
+
+	pact, err = models.ParsePactFile([]byte(v3))
+	assert.NoError(t, err)
+	report, err = Migrate(pact, models.V3)
+	assert.NoError(t, err)
+	assert.Len(t, report.Issues, 1)
+	assert.Empty(t, pact.Interactions[0].(*models.HTTPInteraction).Response.Body.ContentType)
+
+	_, err = Migrate(pact, models.V2)
+	assert.EqualError(t, err, "pacts may only be migrated to V3 or V4, not 2.0.0")
+
+	pact.Metadata.SpecificationVersion = models.V4
+	_, err = Migrate(pact, models.V3)
+	assert.EqualError(t, err, "unable to migrate a 4.0.0 pact to the earlier version 3.0.0")
+}
+
+func TestParseVersion(t *testing.T) {
+	for _, s := range []string{"v4", "V4", "4", "4.0", "4.0.0"} {
+		v, err := ParseVersion(s)
+		assert.NoError(t, err)
+		assert.Equal(t, models.SpecificationVersion(models.V4), v)
+	}
+
+	v, err := ParseVersion("v3")
+	assert.NoError(t, err)
+	assert.Equal(t, models.SpecificationVersion(models.V3), v)
+
+	_, err = ParseVersion("v2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,232 @@
+package models
+
+import "github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+
+// V4 interaction types
+const (
+	InteractionTypeHTTP                = "Synchronous/HTTP"
+	InteractionTypeAsynchronousMessage = "Asynchronous/Messages"
+	InteractionTypeSynchronousMessage  = "Synchronous/Messages"
+)
+
+// Interaction is an interaction of a pact file. It is one of *HTTPInteraction,
+// *AsynchronousMessage, *SynchronousMessage or *PluginInteraction.
+type Interaction interface {
+	// Type is the V4 type of the interaction, e.g. Synchronous/HTTP
+	Type() string
+
+	// Details returns the fields common to all types of interaction
+	Details() *InteractionDetails
+}
+
+// InteractionDetails are the fields common to all types of interaction. Fields that
+// are not supported by the specification version of a pact file are not written.
+type InteractionDetails struct {
+	Description    string
+	ProviderStates []ProviderState
+
+	// Key uniquely identifies the interaction (V4)
+	Key string
+
+	// Pending interactions do not fail verification (V4)
+	Pending bool
+
+	// Comments on the interaction, e.g. the test name and text (V4)
+	Comments map[string]interface{}
+
+	// PluginConfiguration is the configuration of the plugins used by the interaction,
+	// keyed by plugin name (V4)
+	PluginConfiguration map[string]interface{}
+
+	// InteractionMarkup describes the interaction for display, e.g. by the broker (V4)
+	InteractionMarkup *InteractionMarkup
+
+	// Transport is the transport used by a plugin interaction, e.g. grpc (V4)
+	Transport string
+
+	// Extra are fields of the interaction not known to this package, which are kept
+	// as they were read
+	Extra map[string]interface{}
+}
+
+// Details returns the fields common to all types of interaction
+func (d *InteractionDetails) Details() *InteractionDetails {
+	return d
+}
+
+// InteractionMarkup is the markup (e.g. CommonMark) describing an interaction
+type InteractionMarkup struct {
+	Markup     string
+	MarkupType string
+}
+
+// HTTPInteraction is a request to, and the response from, an HTTP provider
+type HTTPInteraction struct {
+	InteractionDetails
+
+	Request  HTTPRequest
+	Response HTTPResponse
+}
+
+// Type is the V4 type of the interaction
+func (i *HTTPInteraction) Type() string {
+	return InteractionTypeHTTP
+}
+
+// HTTPRequest is the expected request of an HTTP interaction
+type HTTPRequest struct {
+	Method string
+	Path   string
+
+	// Query is written as a query string in V2 pact files
+	Query   map[string][]string
+	Headers map[string][]string
+	Body    *Body
+
+	MatchingRules MatchingRules
+	Generators    Generators
+
+	// query is the V2 query string read from the pact file, which is written again
+	// if Query still has the same values
+	query string
+}
+
+// HTTPResponse is the expected response of an HTTP interaction
+type HTTPResponse struct {
+	Status  int
+	Headers map[string][]string
+	Body    *Body
+
+	MatchingRules MatchingRules
+	Generators    Generators
+}
+
+// AsynchronousMessage is a message sent by the provider to the consumer, which is
+// stored in the "messages" of a V3 pact file
+type AsynchronousMessage struct {
+	InteractionDetails
+	Message
+}
+
+// Type is the V4 type of the interaction
+func (i *AsynchronousMessage) Type() string {
+	return InteractionTypeAsynchronousMessage
+}
+
+// SynchronousMessage is a request message sent by the consumer, and the response
+// messages sent by the provider, e.g. a gRPC call (V4)
+type SynchronousMessage struct {
+	InteractionDetails
+
+	Request  Message
+	Response []Message
+}
+
+// Type is the V4 type of the interaction
+func (i *SynchronousMessage) Type() string {
+	return InteractionTypeSynchronousMessage
+}
+
+// Message is the content of a message, and its metadata
+type Message struct {
+	Contents *Body
+	Metadata map[string]interface{}
+
+	MatchingRules MatchingRules
+	Generators    Generators
+}
+
+// PluginInteraction is an interaction of a type provided by a plugin, rather than
+// the specification. Its content is kept in Extra as it was read.
+//
+// Interactions that plugins add to the interaction types of the specification (e.g.
+// gRPC calls, which are synchronous messages) are read as those types, with their
+// PluginConfiguration and InteractionMarkup.
+type PluginInteraction struct {
+	InteractionDetails
+
+	// InteractionType is the V4 type of the interaction
+	InteractionType string
+}
+
+// Type is the V4 type of the interaction
+func (i *PluginInteraction) Type() string {
+	return i.InteractionType
+}
+
+// Body is the body of a request or response, or the contents of a message
+type Body struct {
+	// Content is the decoded JSON document for JSON bodies, and the text or base64
+	// encoded content of other bodies. Numbers are decoded as json.Number.
+	Content interface{}
+
+	// ContentType of the body (V4)
+	ContentType string
+
+	// Encoded is false, or the encoding of Content, e.g. "base64" or "json" (V4)
+	Encoded interface{}
+
+	// ContentTypeHint is TEXT or BINARY if the content type does not say which the
+	// body is (V4)
+	ContentTypeHint string
+}
+
+// MatchingRules are the matching rules of a request, response or message, keyed by
+// category (e.g. body, header, query, path or metadata) and then by path (for the
+// body, e.g. $.items[*].id) or name (for headers, query parameters and metadata).
+// The single rule of the path category has the key "".
+type MatchingRules map[string]map[string]*MatchingRule
+
+// MatchingRule is the list of matchers that apply to a value, and how their results
+// are combined (AND or OR)
+type MatchingRule struct {
+	Combine string
+
+	// Matchers in the pact file form, e.g. {"match": "type", "min": 1}
+	Matchers []map[string]interface{}
+}
+
+// Generators are the generators of a request, response or message, keyed by
+// category and then by path or name as for MatchingRules
+type Generators map[string]map[string]Generator
+
+// Generator is a generator in the pact file form, e.g. {"type": "RandomInt", "min": 1}
+type Generator map[string]interface{}
+
+// Categories of matching rules and generators
+const (
+	CategoryPath     = "path"
+	CategoryQuery    = "query"
+	CategoryHeader   = "header"
+	CategoryBody     = "body"
+	CategoryStatus   = "status"
+	CategoryMetadata = "metadata"
+)
+
+// Applying returns the rule of the category that applies to the value at the path of
+// a document, e.g. $.items[0].id. A value without a rule of its own is matched by type
+// if a value that contains it is, and otherwise by equality, for which nil is returned.
+func (rules MatchingRules) Applying(category string, path string) *MatchingRule {
+	segments, err := matchingrules.ParsePath(path)
+	if err != nil {
+		return nil
+	}
+
+	for i := len(segments); i >= 0; i-- {
+		rule := matchingrules.Lookup(rules[category], segments[:i])
+		if rule == nil || len(rule.Matchers) == 0 {
+			continue
+		}
+		if i == len(segments) {
+			return rule
+		}
+		for _, m := range rule.Matchers {
+			if m["match"] == "type" || m["match"] == "values" || (m["match"] == nil && m["regex"] == nil) {
+				return &MatchingRule{Matchers: []map[string]interface{}{{"match": "type"}}}
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
@@ -1,5 +1,15 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+)
+
 // SpecificationVersion is used to determine the current specification version
 type SpecificationVersion string
 
@@ -13,3 +23,173 @@ const (
 	// V4 spec
 	V4 = "4.0.0"
 )
+
+// PactFile is a pact document of any specification version. The layout of the
+// pact file (e.g. how provider states, headers and matching rules are written) is
+// given by the specification version in its metadata.
+type PactFile struct {
+	Consumer Pacticipant
+	Provider Pacticipant
+
+	// Interactions of the pact, in the order they are written. The asynchronous
+	// messages of a V3 pact are read from, and written to, its "messages".
+	Interactions []Interaction
+
+	Metadata Metadata
+
+	// Extra are the top level fields of the pact not known to this package, which
+	// are kept as they were read
+	Extra map[string]interface{}
+
+	// layout is the specification version inferred from the layout of a pact file
+	// without one in its metadata
+	layout SpecificationVersion
+}
+
+// Pacticipant is the consumer or provider of a pact
+type Pacticipant struct {
+	Name string
+}
+
+// Metadata of a pact file
+type Metadata struct {
+	// SpecificationVersion of the pact file, e.g. 3.0.0
+	SpecificationVersion SpecificationVersion
+
+	// Plugins used by the interactions of the pact (V4)
+	Plugins []PluginMetadata
+
+	// Extra are the other entries of the metadata, e.g. the versions of the
+	// libraries that wrote the pact
+	Extra map[string]interface{}
+
+	// legacy is set if the version was read from the pact-specification entry of
+	// older V1 and V2 pacts, where it is written again
+	legacy bool
+}
+
+// PluginMetadata is a plugin used by the interactions of a pact
+type PluginMetadata struct {
+	Name          string
+	Version       string
+	Configuration map[string]interface{}
+}
+
+// Load reads the pact file at path
+func Load(path string) (*PactFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := ParsePactFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read pact file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// ParsePactFile reads a pact document
+func ParsePactFile(data []byte) (*PactFile, error) {
+	p := &PactFile{}
+	if err := p.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Save writes the pact to the file at path, replacing any existing file. The file is
+// written in the layout of the specification version of the pact.
+func (p *PactFile) Save(path string) error {
+	data, err := p.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	return pactfile.Write(path, buf.Bytes(), pactfile.OverwriteMode)
+}
+
+// Version returns the specification version of the pact. If its metadata has no
+// version, the version is inferred from the layout of the interactions.
+func (p *PactFile) Version() SpecificationVersion {
+	if p.Metadata.SpecificationVersion != "" {
+		return p.Metadata.SpecificationVersion
+	}
+	if p.layout != "" {
+		return p.layout
+	}
+
+	version := V2
+	for _, i := range p.Interactions {
+		switch i.(type) {
+		case *HTTPInteraction:
+		case *AsynchronousMessage:
+			version = V3
+		default:
+			return V4
+		}
+	}
+
+	return version
+}
+
+// major returns the major specification version of the pact, e.g. 3
+func (p *PactFile) major() int {
+	return p.Version().major()
+}
+
+// major returns the major version, e.g. 3. V1 versions are read as V2.
+func (v SpecificationVersion) major() int {
+	switch v := string(v); {
+	case strings.HasPrefix(v, "1"), strings.HasPrefix(v, "2"):
+		return 2
+	case strings.HasPrefix(v, "3"):
+		return 3
+	default:
+		return 4
+	}
+}
+
+// UnmarshalJSON reads a pact document of any specification version
+func (p *PactFile) UnmarshalJSON(data []byte) error {
+	var doc map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&doc); err != nil {
+		return err
+	}
+
+	r, err := readPact(doc)
+	if err != nil {
+		return err
+	}
+	*p = *r
+
+	return nil
+}
+
+// MarshalJSON writes the pact in the layout of its specification version. HTML
+// characters are not escaped, so that bodies and matching rules are unchanged.
+func (p *PactFile) MarshalJSON() ([]byte, error) {
+	doc, err := p.write()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
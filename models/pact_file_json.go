@@ -0,0 +1,827 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+)
+
+// Pact files are read leniently: the layout of each field (e.g. a V2 query string or
+// a V3 map of query parameters) is detected as it is read, so that files written by
+// older libraries can be loaded. They are written in the layout of their version.
+
+// object is a JSON object being read, from which the known fields are taken. The
+// fields that remain are kept as the Extra fields of the value being read.
+type object map[string]interface{}
+
+func (o object) take(key string) interface{} {
+	v := o[key]
+	delete(o, key)
+
+	return v
+}
+
+func (o object) str(key string) string {
+	s, _ := o.take(key).(string)
+
+	return s
+}
+
+func (o object) obj(key string) map[string]interface{} {
+	m, _ := o.take(key).(map[string]interface{})
+
+	return m
+}
+
+func (o object) rest() map[string]interface{} {
+	if len(o) == 0 {
+		return nil
+	}
+
+	return o
+}
+
+func readPact(doc map[string]interface{}) (*PactFile, error) {
+	o := object(doc)
+	p := &PactFile{
+		Consumer: Pacticipant{Name: object(o.obj("consumer")).str("name")},
+		Provider: Pacticipant{Name: object(o.obj("provider")).str("name")},
+		Metadata: readMetadata(o.obj("metadata")),
+	}
+
+	interactions, _ := o.take("interactions").([]interface{})
+	messages, _ := o.take("messages").([]interface{})
+	p.layout = layoutOf(interactions, messages)
+
+	for n, v := range interactions {
+		i, err := readInteraction(v, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interaction %d: %w", n, err)
+		}
+		p.Interactions = append(p.Interactions, i)
+	}
+
+	for n, v := range messages {
+		i, err := readInteraction(v, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", n, err)
+		}
+		p.Interactions = append(p.Interactions, i)
+	}
+
+	p.Extra = o.rest()
+
+	return p, nil
+}
+
+// layoutOf infers the specification version of a pact without one in its metadata
+func layoutOf(interactions []interface{}, messages []interface{}) SpecificationVersion {
+	layout := V2
+	if len(messages) > 0 {
+		layout = V3
+	}
+	for _, i := range interactions {
+		m, _ := i.(map[string]interface{})
+		if _, ok := m["type"]; ok {
+			return V4
+		}
+		if _, ok := m["providerStates"]; ok {
+			layout = V3
+		}
+	}
+
+	return layout
+}
+
+func readMetadata(m map[string]interface{}) Metadata {
+	o := object(m)
+	metadata := Metadata{}
+
+	if spec, ok := o.take("pactSpecification").(map[string]interface{}); ok {
+		metadata.SpecificationVersion = SpecificationVersion(object(spec).str("version"))
+	} else if spec, ok := o.take("pact-specification").(map[string]interface{}); ok {
+		metadata.SpecificationVersion = SpecificationVersion(object(spec).str("version"))
+		metadata.legacy = true
+	}
+
+	plugins, _ := o.take("plugins").([]interface{})
+	for _, v := range plugins {
+		plugin, _ := v.(map[string]interface{})
+		po := object(plugin)
+		metadata.Plugins = append(metadata.Plugins, PluginMetadata{
+			Name:          po.str("name"),
+			Version:       po.str("version"),
+			Configuration: po.obj("configuration"),
+		})
+	}
+
+	metadata.Extra = o.rest()
+
+	return metadata
+}
+
+// readInteraction reads an interaction of any version, or a V3 message
+func readInteraction(v interface{}, message bool) (Interaction, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an object but got %v", v)
+	}
+	o := object(m)
+
+	t, _ := o["type"].(string)
+	v4 := t != ""
+	delete(o, "type")
+	if t == "" {
+		t = InteractionTypeHTTP
+		if message {
+			t = InteractionTypeAsynchronousMessage
+		}
+	}
+
+	details, err := readDetails(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var i Interaction
+	switch t {
+	case InteractionTypeHTTP:
+		http := &HTTPInteraction{InteractionDetails: details}
+		if http.Request, err = readRequest(o.obj("request"), v4); err != nil {
+			return nil, fmt.Errorf("request: %w", err)
+		}
+		if http.Response, err = readResponse(o.obj("response"), v4); err != nil {
+			return nil, fmt.Errorf("response: %w", err)
+		}
+		i = http
+	case InteractionTypeAsynchronousMessage:
+		async := &AsynchronousMessage{InteractionDetails: details}
+		if async.Message, err = readMessage(o, v4); err != nil {
+			return nil, err
+		}
+		i = async
+	case InteractionTypeSynchronousMessage:
+		sync := &SynchronousMessage{InteractionDetails: details}
+		if sync.Request, err = readMessage(object(o.obj("request")), v4); err != nil {
+			return nil, fmt.Errorf("request: %w", err)
+		}
+		responses, _ := o.take("response").([]interface{})
+		for _, r := range responses {
+			response, _ := r.(map[string]interface{})
+			message, err := readMessage(object(response), v4)
+			if err != nil {
+				return nil, fmt.Errorf("response: %w", err)
+			}
+			sync.Response = append(sync.Response, message)
+		}
+		i = sync
+	default:
+		i = &PluginInteraction{InteractionDetails: details, InteractionType: t}
+	}
+
+	// Any fields that were not read are kept
+	i.Details().Extra = o.rest()
+
+	return i, nil
+}
+
+func readDetails(o object) (InteractionDetails, error) {
+	d := InteractionDetails{
+		Description:         o.str("description"),
+		Key:                 o.str("key"),
+		Comments:            o.obj("comments"),
+		PluginConfiguration: o.obj("pluginConfiguration"),
+		Transport:           o.str("transport"),
+	}
+	d.Pending, _ = o.take("pending").(bool)
+
+	if state := o.str("providerState"); state != "" {
+		d.ProviderStates = append(d.ProviderStates, ProviderState{Name: state})
+	}
+	states, _ := o.take("providerStates").([]interface{})
+	for _, s := range states {
+		state, ok := s.(map[string]interface{})
+		if !ok {
+			return d, fmt.Errorf("invalid provider state %v", s)
+		}
+		so := object(state)
+		d.ProviderStates = append(d.ProviderStates, ProviderState{Name: so.str("name"), Parameters: so.obj("params")})
+	}
+
+	if markup := o.obj("interactionMarkup"); markup != nil {
+		mo := object(markup)
+		d.InteractionMarkup = &InteractionMarkup{Markup: mo.str("markup"), MarkupType: mo.str("markupType")}
+	}
+
+	return d, nil
+}
+
+func readRequest(m map[string]interface{}, v4 bool) (HTTPRequest, error) {
+	if m == nil {
+		return HTTPRequest{}, fmt.Errorf("missing request")
+	}
+	o := object(m)
+
+	var err error
+	r := HTTPRequest{
+		Method:  o.str("method"),
+		Path:    o.str("path"),
+		Headers: readValues(o.take("headers")),
+		Body:    readBody(o, "body", v4),
+	}
+	switch q := o.take("query").(type) {
+	case string:
+		r.query = q
+		if r.Query, err = url.ParseQuery(q); err != nil {
+			return r, fmt.Errorf("invalid query %q: %w", q, err)
+		}
+	default:
+		r.Query = readValues(q)
+	}
+	if r.MatchingRules, err = readRules(o.take("matchingRules")); err != nil {
+		return r, err
+	}
+	r.Generators = readGenerators(o.take("generators"))
+
+	return r, nil
+}
+
+func readResponse(m map[string]interface{}, v4 bool) (HTTPResponse, error) {
+	if m == nil {
+		return HTTPResponse{}, fmt.Errorf("missing response")
+	}
+	o := object(m)
+
+	r := HTTPResponse{
+		Headers: readValues(o.take("headers")),
+		Body:    readBody(o, "body", v4),
+	}
+	if status, ok := o.take("status").(json.Number); ok {
+		s, err := status.Int64()
+		if err != nil {
+			return r, fmt.Errorf("invalid status %s", status)
+		}
+		r.Status = int(s)
+	}
+
+	var err error
+	if r.MatchingRules, err = readRules(o.take("matchingRules")); err != nil {
+		return r, err
+	}
+	r.Generators = readGenerators(o.take("generators"))
+
+	return r, nil
+}
+
+func readMessage(o object, v4 bool) (Message, error) {
+	var err error
+	m := Message{
+		Contents: readBody(o, "contents", v4),
+		Metadata: o.obj("metadata"),
+	}
+	if m.MatchingRules, err = readRules(o.take("matchingRules")); err != nil {
+		return m, err
+	}
+	m.Generators = readGenerators(o.take("generators"))
+
+	return m, nil
+}
+
+// readValues reads a map of single values (V2/V3 headers) or lists of values (V3
+// query parameters and V4 headers)
+func readValues(v interface{}) map[string][]string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	values := make(map[string][]string, len(m))
+	for k, value := range m {
+		switch list := value.(type) {
+		case []interface{}:
+			values[k] = []string{}
+			for _, s := range list {
+				values[k] = append(values[k], fmt.Sprint(s))
+			}
+		default:
+			values[k] = []string{fmt.Sprint(list)}
+		}
+	}
+
+	return values
+}
+
+// readBody reads a V2/V3 body, which is the content itself, or a V4 body which
+// describes its content
+func readBody(o object, key string, v4 bool) *Body {
+	v, ok := o[key]
+	if !ok {
+		return nil
+	}
+	delete(o, key)
+
+	described, ok := v.(map[string]interface{})
+	if _, hasContent := described["content"]; !v4 || !ok || !hasContent {
+		return &Body{Content: v}
+	}
+
+	do := object(described)
+	return &Body{
+		Content:         do.take("content"),
+		ContentType:     do.str("contentType"),
+		Encoded:         do.take("encoded"),
+		ContentTypeHint: do.str("contentTypeHint"),
+	}
+}
+
+// readRules reads V2 matching rules, which are keyed by a single path (e.g.
+// $.body.name or $.headers.Accept), or V3/V4 matching rules grouped by category
+func readRules(v interface{}) (MatchingRules, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	rules := make(MatchingRules)
+	add := func(category string, key string, rule *MatchingRule) {
+		if rules[category] == nil {
+			rules[category] = make(map[string]*MatchingRule)
+		}
+		rules[category][key] = rule
+	}
+
+	for k, v := range m {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid matching rule for %s", k)
+		}
+
+		if strings.HasPrefix(k, "$") {
+			category, key, err := v2RulePath(k)
+			if err != nil {
+				return nil, err
+			}
+			add(category, key, &MatchingRule{Matchers: []map[string]interface{}{entry}})
+			continue
+		}
+
+		rules[k] = make(map[string]*MatchingRule)
+
+		// The path category has a single rule, rather than a rule per key
+		if _, ok := entry["matchers"]; ok {
+			rule, err := readRule(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s matching rule: %w", k, err)
+			}
+			add(k, "", rule)
+			continue
+		}
+
+		for key, r := range entry {
+			ruleEntry, _ := r.(map[string]interface{})
+			rule, err := readRule(ruleEntry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s matching rule for %s: %w", k, key, err)
+			}
+			add(k, key, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func readRule(entry map[string]interface{}) (*MatchingRule, error) {
+	rule := &MatchingRule{}
+	rule.Combine, _ = entry["combine"].(string)
+
+	list, ok := entry["matchers"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing matchers")
+	}
+	for _, l := range list {
+		matcher, ok := l.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid matcher %v", l)
+		}
+		rule.Matchers = append(rule.Matchers, matcher)
+	}
+
+	return rule, nil
+}
+
+// v2RulePath splits a V2 matching rule path into its category and key, e.g.
+// $.body.name is the body path $.name, and $.headers.Accept is the header Accept
+func v2RulePath(path string) (string, string, error) {
+	segments, err := matchingrules.ParsePath(path)
+	if err != nil {
+		// V2 body paths may use JSONPath that later versions do not support (e.g.
+		// $.body..name), which are kept as they were written
+		if rest, ok := strings.CutPrefix(path, "$.body"); ok && rest != "" && (rest[0] == '.' || rest[0] == '[') {
+			return CategoryBody, "$" + rest, nil
+		}
+		return "", "", err
+	}
+	if len(segments) == 0 {
+		return "", "", fmt.Errorf("invalid matching rule path %s", path)
+	}
+
+	switch segments[0] {
+	case "path":
+		return CategoryPath, "", nil
+	case "query", "header", "headers":
+		if len(segments) != 2 {
+			return "", "", fmt.Errorf("invalid matching rule path %s", path)
+		}
+		if segments[0] == "query" {
+			return CategoryQuery, segments[1], nil
+		}
+		return CategoryHeader, segments[1], nil
+	}
+
+	// Keep the body path as it was written, e.g. $.body['name'] is $['name']
+	if rest := strings.TrimPrefix(path, "$."+segments[0]); rest != path {
+		return segments[0], "$" + rest, nil
+	}
+
+	return segments[0], matchingrules.FormatPath(segments[1:]), nil
+}
+
+// readGenerators reads the generators of a request, response or message
+func readGenerators(v interface{}) Generators {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	generators := make(Generators, len(m))
+	for category, c := range m {
+		entries, _ := c.(map[string]interface{})
+		generators[category] = make(map[string]Generator)
+
+		// The path category has a single generator, rather than a generator per key
+		if _, ok := entries["type"].(string); ok {
+			generators[category][""] = entries
+			continue
+		}
+		for key, g := range entries {
+			generator, _ := g.(map[string]interface{})
+			generators[category][key] = generator
+		}
+	}
+
+	return generators
+}
+
+// write converts the pact into its JSON form, in the layout of its version
+func (p *PactFile) write() (map[string]interface{}, error) {
+	major := p.major()
+
+	doc := make(map[string]interface{})
+	for k, v := range p.Extra {
+		doc[k] = v
+	}
+	doc["consumer"] = map[string]interface{}{"name": p.Consumer.Name}
+	doc["provider"] = map[string]interface{}{"name": p.Provider.Name}
+	doc["metadata"] = p.Metadata.write()
+
+	interactions := []interface{}{}
+	var messages []interface{}
+	for n, i := range p.Interactions {
+		v, err := writeInteraction(i, major)
+		if err != nil {
+			return nil, fmt.Errorf("unable to write interaction %d (%q): %w", n, i.Details().Description, err)
+		}
+		if _, ok := i.(*AsynchronousMessage); ok && major == 3 {
+			messages = append(messages, v)
+		} else {
+			interactions = append(interactions, v)
+		}
+	}
+	if len(interactions) > 0 || messages == nil {
+		doc["interactions"] = interactions
+	}
+	if messages != nil {
+		doc["messages"] = messages
+	}
+
+	return doc, nil
+}
+
+func (m Metadata) write() map[string]interface{} {
+	metadata := make(map[string]interface{})
+	for k, v := range m.Extra {
+		metadata[k] = v
+	}
+
+	if m.SpecificationVersion != "" {
+		key := "pactSpecification"
+		if m.legacy && m.SpecificationVersion.major() < 3 {
+			key = "pact-specification"
+		}
+		metadata[key] = map[string]interface{}{"version": string(m.SpecificationVersion)}
+	}
+
+	if m.Plugins != nil {
+		plugins := make([]interface{}, len(m.Plugins))
+		for i, plugin := range m.Plugins {
+			entry := map[string]interface{}{
+				"name":    plugin.Name,
+				"version": plugin.Version,
+			}
+			if plugin.Configuration != nil {
+				entry["configuration"] = plugin.Configuration
+			}
+			plugins[i] = entry
+		}
+		metadata["plugins"] = plugins
+	}
+
+	return metadata
+}
+
+func writeInteraction(i Interaction, major int) (map[string]interface{}, error) {
+	d := i.Details()
+	m := make(map[string]interface{})
+	for k, v := range d.Extra {
+		m[k] = v
+	}
+
+	m["description"] = d.Description
+	if err := d.writeStates(m, major); err != nil {
+		return nil, err
+	}
+	if major >= 4 {
+		d.writeV4(m, i.Type())
+	}
+
+	switch interaction := i.(type) {
+	case *HTTPInteraction:
+		m["request"] = interaction.Request.write(major)
+		m["response"] = interaction.Response.write(major)
+	case *AsynchronousMessage:
+		if major < 3 {
+			return nil, fmt.Errorf("messages require the V3 specification or later")
+		}
+		interaction.Message.writeTo(m, major)
+	case *SynchronousMessage:
+		if major < 4 {
+			return nil, fmt.Errorf("synchronous messages require the V4 specification")
+		}
+		m["request"] = interaction.Request.writeTo(make(map[string]interface{}), major)
+		responses := make([]interface{}, len(interaction.Response))
+		for n, r := range interaction.Response {
+			responses[n] = r.writeTo(make(map[string]interface{}), major)
+		}
+		m["response"] = responses
+	default:
+		if major < 4 {
+			return nil, fmt.Errorf("%s interactions require the V4 specification", i.Type())
+		}
+	}
+
+	return m, nil
+}
+
+func (d *InteractionDetails) writeStates(m map[string]interface{}, major int) error {
+	if len(d.ProviderStates) == 0 {
+		return nil
+	}
+
+	if major < 3 {
+		if len(d.ProviderStates) > 1 || d.ProviderStates[0].Parameters != nil {
+			return fmt.Errorf("V2 pacts support a single provider state, without parameters")
+		}
+		m["providerState"] = d.ProviderStates[0].Name
+		return nil
+	}
+
+	states := make([]interface{}, len(d.ProviderStates))
+	for n, s := range d.ProviderStates {
+		state := map[string]interface{}{"name": s.Name}
+		if s.Parameters != nil {
+			state["params"] = s.Parameters
+		}
+		states[n] = state
+	}
+	m["providerStates"] = states
+
+	return nil
+}
+
+// writeV4 writes the fields that only V4 interactions have
+func (d *InteractionDetails) writeV4(m map[string]interface{}, t string) {
+	m["type"] = t
+	m["pending"] = d.Pending
+	if d.Key != "" {
+		m["key"] = d.Key
+	}
+	if d.Comments != nil {
+		m["comments"] = d.Comments
+	}
+	if d.PluginConfiguration != nil {
+		m["pluginConfiguration"] = d.PluginConfiguration
+	}
+	if d.InteractionMarkup != nil {
+		m["interactionMarkup"] = map[string]interface{}{
+			"markup":     d.InteractionMarkup.Markup,
+			"markupType": d.InteractionMarkup.MarkupType,
+		}
+	}
+	if d.Transport != "" {
+		m["transport"] = d.Transport
+	}
+}
+
+func (r HTTPRequest) write(major int) map[string]interface{} {
+	m := map[string]interface{}{
+		"method": r.Method,
+		"path":   r.Path,
+	}
+
+	if r.Query != nil {
+		if major < 3 {
+			m["query"] = r.queryString()
+		} else {
+			m["query"] = writeValues(r.Query, true)
+		}
+	}
+	if r.Headers != nil {
+		m["headers"] = writeValues(r.Headers, major >= 4)
+	}
+	if r.Body != nil {
+		m["body"] = r.Body.write(major)
+	}
+	writeRules(m, r.MatchingRules, r.Generators, major)
+
+	return m
+}
+
+// queryString returns the V2 query string. The query string that was read is kept
+// if the query parameters have not changed.
+func (r HTTPRequest) queryString() string {
+	if r.query != "" {
+		if values, err := url.ParseQuery(r.query); err == nil && reflect.DeepEqual(map[string][]string(values), r.Query) {
+			return r.query
+		}
+	}
+
+	return url.Values(r.Query).Encode()
+}
+
+func (r HTTPResponse) write(major int) map[string]interface{} {
+	m := map[string]interface{}{
+		"status": r.Status,
+	}
+
+	if r.Headers != nil {
+		m["headers"] = writeValues(r.Headers, major >= 4)
+	}
+	if r.Body != nil {
+		m["body"] = r.Body.write(major)
+	}
+	writeRules(m, r.MatchingRules, r.Generators, major)
+
+	return m
+}
+
+func (msg Message) writeTo(m map[string]interface{}, major int) map[string]interface{} {
+	if msg.Contents != nil {
+		m["contents"] = msg.Contents.write(major)
+	}
+	if msg.Metadata != nil {
+		m["metadata"] = msg.Metadata
+	}
+	writeRules(m, msg.MatchingRules, msg.Generators, major)
+
+	return m
+}
+
+// writeValues writes headers or query parameters as lists of values, or single
+// values (with multiple values joined) for V2/V3 headers
+func writeValues(values map[string][]string, lists bool) map[string]interface{} {
+	m := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if lists {
+			m[k] = append([]string{}, v...)
+		} else {
+			m[k] = strings.Join(v, ", ")
+		}
+	}
+
+	return m
+}
+
+func (b *Body) write(major int) interface{} {
+	if major < 4 {
+		return b.Content
+	}
+
+	m := map[string]interface{}{"content": b.Content}
+	if b.ContentType != "" {
+		m["contentType"] = b.ContentType
+	}
+	if b.Encoded != nil {
+		m["encoded"] = b.Encoded
+	}
+	if b.ContentTypeHint != "" {
+		m["contentTypeHint"] = b.ContentTypeHint
+	}
+
+	return m
+}
+
+func writeRules(m map[string]interface{}, rules MatchingRules, generators Generators, major int) {
+	if rules != nil {
+		if major < 3 {
+			m["matchingRules"] = rules.writeV2()
+		} else {
+			m["matchingRules"] = rules.write()
+		}
+	}
+	if generators != nil && major >= 3 {
+		m["generators"] = generators.write()
+	}
+}
+
+// writeV2 writes the rules keyed by a single path. V2 rules have a single matcher.
+func (rules MatchingRules) writeV2() map[string]interface{} {
+	m := make(map[string]interface{})
+	for category, entries := range rules {
+		for key, rule := range entries {
+			if rule == nil || len(rule.Matchers) == 0 {
+				continue
+			}
+
+			var path string
+			switch category {
+			case CategoryPath:
+				path = "$.path"
+			case CategoryQuery:
+				path = matchingrules.Path("$.query", key)
+			case CategoryHeader:
+				path = matchingrules.Path("$.headers", key)
+			default:
+				path = "$." + category + strings.TrimPrefix(key, "$")
+			}
+			m[path] = rule.Matchers[0]
+		}
+	}
+
+	return m
+}
+
+func (rules MatchingRules) write() map[string]interface{} {
+	m := make(map[string]interface{}, len(rules))
+	for category, entries := range rules {
+		if rule, ok := entries[""]; ok && len(entries) == 1 {
+			m[category] = rule.write()
+			continue
+		}
+
+		c := make(map[string]interface{}, len(entries))
+		for key, rule := range entries {
+			c[key] = rule.write()
+		}
+		m[category] = c
+	}
+
+	return m
+}
+
+func (r *MatchingRule) write() map[string]interface{} {
+	matchers := make([]interface{}, 0)
+	m := map[string]interface{}{}
+	if r != nil {
+		for _, matcher := range r.Matchers {
+			matchers = append(matchers, matcher)
+		}
+		if r.Combine != "" {
+			m["combine"] = r.Combine
+		}
+	}
+	m["matchers"] = matchers
+
+	return m
+}
+
+func (generators Generators) write() map[string]interface{} {
+	m := make(map[string]interface{}, len(generators))
+	for category, entries := range generators {
+		if generator, ok := entries[""]; ok && len(entries) == 1 {
+			m[category] = generator
+			continue
+		}
+
+		c := make(map[string]interface{}, len(entries))
+		for key, generator := range entries {
+			c[key] = generator
+		}
+		m[category] = c
+	}
+
+	return m
+}
@@ -0,0 +1,273 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPactFileRoundTrip(t *testing.T) {
+	for _, name := range []string{"v2.json", "v3.json", "v3-message.json", "v4.json"} {
+		t.Run(name, func(t *testing.T) {
+			original, err := os.ReadFile(filepath.Join("testdata", name))
+			assert.NoError(t, err)
+
+			p, err := ParsePactFile(original)
+			assert.NoError(t, err)
+
+			written, err := p.MarshalJSON()
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(original), string(written))
+		})
+	}
+}
+
+func TestLoadV2(t *testing.T) {
+	p, err := Load(filepath.Join("testdata", "v2.json"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "consumer", p.Consumer.Name)
+	assert.Equal(t, "provider", p.Provider.Name)
+	assert.Equal(t, V2, p.Version())
+	assert.Equal(t, map[string]interface{}{"pact-jvm": map[string]interface{}{"version": "3.5.0"}}, p.Metadata.Extra)
+	assert.Len(t, p.Interactions, 1)
+
+	i := p.Interactions[0].(*HTTPInteraction)
+	assert.Equal(t, InteractionTypeHTTP, i.Type())
+	assert.Equal(t, "a request for users", i.Description)
+	assert.Equal(t, []ProviderState{{Name: "users exist"}}, i.ProviderStates)
+	assert.Equal(t, map[string][]string{"page": {"1"}, "sort": {"name"}}, i.Request.Query)
+	assert.Equal(t, map[string][]string{"Accept": {"application/json"}}, i.Request.Headers)
+	assert.Nil(t, i.Request.Body)
+	assert.Equal(t, MatchingRules{
+		CategoryQuery:  {"page": {Matchers: []map[string]interface{}{{"match": "regex", "regex": `\d+`}}}},
+		CategoryHeader: {"Accept": {Matchers: []map[string]interface{}{{"match": "type"}}}},
+	}, i.Request.MatchingRules)
+
+	assert.Equal(t, 200, i.Response.Status)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"id":   json.Number("1"),
+		"name": "<billy>",
+		"rate": json.Number("1.50"),
+	}}, i.Response.Body.Content)
+	assert.Equal(t, []string{"$", "$[*].id", "$[*]['name']"}, keys(i.Response.MatchingRules[CategoryBody]))
+}
+
+func TestLoadV2JSONPath(t *testing.T) {
+	p, err := ParsePactFile([]byte(`{
+		"interactions": [{"description": "a request", "request": {"method": "GET", "path": "/"},
+		  "response": {"status": 200, "body": {"name": "billy"}, "matchingRules": {"$.body..name": {"match": "type"}}}}],
+		"metadata": {"pact-specification": {"version": "2.0.0"}}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"$..name"}, keys(p.Interactions[0].(*HTTPInteraction).Response.MatchingRules[CategoryBody]))
+
+	// The legacy key is only written for V1 and V2 pacts
+	p.Metadata.SpecificationVersion = V3
+	data, err := p.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"pactSpecification":{"version":"3.0.0"}`)
+
+	_, err = ParsePactFile([]byte(`{"interactions": [{"description": "a request", "request": {"method": "GET", "path": "/"},
+		"response": {"status": 200, "matchingRules": {"$.headers..name": {"match": "type"}}}}]}`))
+	assert.Error(t, err)
+}
+
+func TestLoadV3(t *testing.T) {
+	p, err := Load(filepath.Join("testdata", "v3.json"))
+	assert.NoError(t, err)
+
+	i := p.Interactions[0].(*HTTPInteraction)
+	assert.Equal(t, []ProviderState{
+		{Name: "no users", Parameters: map[string]interface{}{"id": json.Number("10")}},
+		{Name: "an admin"},
+	}, i.ProviderStates)
+	assert.Equal(t, map[string][]string{"dry-run": {"true"}, "tag": {"a", "b"}}, i.Request.Query)
+	assert.Equal(t, &MatchingRule{Combine: "AND", Matchers: []map[string]interface{}{{"match": "regex", "regex": `/users/\d+`}}}, i.Request.MatchingRules[CategoryPath][""])
+	assert.Equal(t, Generator{"type": "ProviderState", "expression": "/users/${id}"}, i.Request.Generators[CategoryPath][""])
+	assert.Equal(t, "OR", i.Response.MatchingRules[CategoryBody]["$.created"].Combine)
+	assert.Equal(t, Generator{"type": "RandomInt", "min": json.Number("1"), "max": json.Number("10")}, i.Response.Generators[CategoryBody]["$.id"])
+
+	p, err = Load(filepath.Join("testdata", "v3-message.json"))
+	assert.NoError(t, err)
+
+	m := p.Interactions[0].(*AsynchronousMessage)
+	assert.Equal(t, InteractionTypeAsynchronousMessage, m.Type())
+	assert.Equal(t, &Body{Content: map[string]interface{}{"id": json.Number("10")}}, m.Contents)
+	assert.Equal(t, "users", m.Metadata["topic"])
+}
+
+func TestLoadV4(t *testing.T) {
+	p, err := Load(filepath.Join("testdata", "v4.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, SpecificationVersion("4.0"), p.Version())
+	assert.Equal(t, []PluginMetadata{{
+		Name:          "protobuf",
+		Version:       "0.3.0",
+		Configuration: map[string]interface{}{"abc": map[string]interface{}{"protoFile": `syntax = "proto3";`}},
+	}}, p.Metadata.Plugins)
+	assert.Len(t, p.Interactions, 4)
+
+	http := p.Interactions[0].(*HTTPInteraction)
+	assert.Equal(t, "a1b2c3", http.Key)
+	assert.Equal(t, map[string][]string{"Accept": {"application/json", "text/plain"}}, http.Request.Headers)
+	assert.Equal(t, &Body{
+		Content:     map[string]interface{}{"id": json.Number("10")},
+		ContentType: "application/json",
+		Encoded:     false,
+	}, http.Response.Body)
+
+	async := p.Interactions[1].(*AsynchronousMessage)
+	assert.True(t, async.Pending)
+	assert.Equal(t, "base64", async.Contents.Encoded)
+	assert.NotNil(t, async.MatchingRules[CategoryMetadata]["topic"])
+
+	sync := p.Interactions[2].(*SynchronousMessage)
+	assert.Equal(t, InteractionTypeSynchronousMessage, sync.Type())
+	assert.Equal(t, "grpc", sync.Transport)
+	assert.Equal(t, "COMMON_MARK", sync.InteractionMarkup.MarkupType)
+	assert.Contains(t, sync.PluginConfiguration, "protobuf")
+	assert.Equal(t, "BINARY", sync.Request.Contents.ContentTypeHint)
+	assert.Len(t, sync.Response, 1)
+
+	plugin := p.Interactions[3].(*PluginInteraction)
+	assert.Equal(t, "Synchronous/Custom", plugin.Type())
+	assert.Equal(t, "a custom plugin interaction", plugin.Description)
+	assert.Equal(t, map[string]interface{}{
+		"custom": map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("2.5")}},
+	}, plugin.Extra)
+}
+
+func TestSave(t *testing.T) {
+	p := &PactFile{
+		Consumer: Pacticipant{Name: "consumer"},
+		Provider: Pacticipant{Name: "provider"},
+		Interactions: []Interaction{
+			&HTTPInteraction{
+				InteractionDetails: InteractionDetails{Description: "a request", ProviderStates: []ProviderState{{Name: "a state"}}},
+				Request: HTTPRequest{
+					Method:  "GET",
+					Path:    "/",
+					Query:   map[string][]string{"a": {"1", "2"}},
+					Headers: map[string][]string{"Accept": {"text/plain", "text/html"}},
+				},
+				Response: HTTPResponse{
+					Status: 200,
+					Body:   &Body{Content: "<html>", ContentType: "text/html"},
+					MatchingRules: MatchingRules{
+						CategoryBody:   {"$": {Combine: "AND", Matchers: []map[string]interface{}{{"match": "type"}}}},
+						CategoryHeader: {"Content-Type": {Matchers: []map[string]interface{}{{"match": "regex", "regex": "html"}}}},
+					},
+				},
+			},
+		},
+		Metadata: Metadata{SpecificationVersion: V2},
+	}
+
+	tests := []struct {
+		version SpecificationVersion
+		want    string
+	}{
+		{
+			version: V2,
+			want: `{
+				"description": "a request",
+				"providerState": "a state",
+				"request": {"method": "GET", "path": "/", "query": "a=1&a=2", "headers": {"Accept": "text/plain, text/html"}},
+				"response": {"status": 200, "body": "<html>", "matchingRules": {
+					"$.body": {"match": "type"},
+					"$.headers.Content-Type": {"match": "regex", "regex": "html"}
+				}}
+			}`,
+		},
+		{
+			version: V3,
+			want: `{
+				"description": "a request",
+				"providerStates": [{"name": "a state"}],
+				"request": {"method": "GET", "path": "/", "query": {"a": ["1", "2"]}, "headers": {"Accept": "text/plain, text/html"}},
+				"response": {"status": 200, "body": "<html>", "matchingRules": {
+					"body": {"$": {"combine": "AND", "matchers": [{"match": "type"}]}},
+					"header": {"Content-Type": {"matchers": [{"match": "regex", "regex": "html"}]}}
+				}}
+			}`,
+		},
+		{
+			version: V4,
+			want: `{
+				"type": "Synchronous/HTTP",
+				"description": "a request",
+				"pending": false,
+				"providerStates": [{"name": "a state"}],
+				"request": {"method": "GET", "path": "/", "query": {"a": ["1", "2"]}, "headers": {"Accept": ["text/plain", "text/html"]}},
+				"response": {"status": 200, "body": {"content": "<html>", "contentType": "text/html"}, "matchingRules": {
+					"body": {"$": {"combine": "AND", "matchers": [{"match": "type"}]}},
+					"header": {"Content-Type": {"matchers": [{"match": "regex", "regex": "html"}]}}
+				}}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.version), func(t *testing.T) {
+			p.Metadata.SpecificationVersion = tt.version
+			path := filepath.Join(t.TempDir(), "pact.json")
+			assert.NoError(t, p.Save(path))
+
+			var written map[string]interface{}
+			data, _ := os.ReadFile(path)
+			assert.NoError(t, json.Unmarshal(data, &written))
+			interaction, _ := json.Marshal(written["interactions"].([]interface{})[0])
+			assert.JSONEq(t, tt.want, string(interaction))
+			assert.Equal(t, map[string]interface{}{"version": string(tt.version)}, written["metadata"].(map[string]interface{})["pactSpecification"])
+
+			loaded, err := Load(path)
+			assert.NoError(t, err)
+			assert.Equal(t, p.Interactions[0].(*HTTPInteraction).Request.Query, loaded.Interactions[0].(*HTTPInteraction).Request.Query)
+		})
+	}
+}
+
+func TestSaveUnsupportedInteractions(t *testing.T) {
+	p := &PactFile{
+		Interactions: []Interaction{&SynchronousMessage{InteractionDetails: InteractionDetails{Description: "a call"}}},
+		Metadata:     Metadata{SpecificationVersion: V3},
+	}
+	_, err := p.MarshalJSON()
+	assert.EqualError(t, err, `unable to write interaction 0 ("a call"): synchronous messages require the V4 specification`)
+
+	p.Interactions = []Interaction{&AsynchronousMessage{}}
+	p.Metadata.SpecificationVersion = V2
+	_, err = p.MarshalJSON()
+	assert.EqualError(t, err, `unable to write interaction 0 (""): messages require the V3 specification or later`)
+}
+
+func TestVersion(t *testing.T) {
+	p, err := ParsePactFile([]byte(`{"interactions": [{"type": "Synchronous/HTTP", "request": {}, "response": {}}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, SpecificationVersion(V4), p.Version())
+
+	p, err = ParsePactFile([]byte(`{"messages": [{"description": "a message"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, SpecificationVersion(V3), p.Version())
+
+	p = &PactFile{Interactions: []Interaction{&HTTPInteraction{}}}
+	assert.Equal(t, V2, p.Version())
+
+	_, err = ParsePactFile([]byte(`{"interactions": [{"description": "a request", "request": {}}]}`))
+	assert.EqualError(t, err, "invalid interaction 0: response: missing response")
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,338 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+)
+
+// Violation is a part of an interaction that the OpenAPI document does not allow
+type Violation struct {
+	// Interaction is the description of the interaction
+	Interaction string `json:"interaction"`
+
+	// Location is the part of the interaction, e.g. request.path, response.status,
+	// request.query.page, response.header.Location or response.body.$.user.name
+	Location string `json:"location"`
+
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Interaction, v.Location, v.Message)
+}
+
+// Report is the result of checking a pact against an OpenAPI document
+type Report struct {
+	// Interactions is the number of HTTP interactions that were checked
+	Interactions int         `json:"interactions"`
+	Violations   []Violation `json:"violations"`
+}
+
+// OK reports whether the document allows every interaction of the pact
+func (r *Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// CheckPactFile checks the pact file against the OpenAPI document at the path spec
+func CheckPactFile(pact string, spec string) (*Report, error) {
+	doc, err := Load(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(pact)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.CheckPact(data)
+}
+
+// CheckPact checks every HTTP interaction of a V2, V3 or V4 pact against the document,
+// reporting the paths, methods, status codes, headers and body fields it does not allow.
+//
+// The examples of the interaction are validated against the schemas of the document, along
+// with the types of values allowed by matchers (e.g. an integer matcher on a string property).
+// Properties that are not documented are reported, unless additionalProperties allows them.
+// Missing required properties are reported for requests, but not responses, as consumers
+// need not depend on every property of a response.
+func (d *Document) CheckPact(data []byte) (*Report, error) {
+	interactions, err := pactfile.HTTPInteractions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Interactions: len(interactions),
+		Violations:   make([]Violation, 0),
+	}
+	for _, i := range interactions {
+		for _, v := range d.checkInteraction(i) {
+			v.Interaction = i.Description
+			report.Violations = append(report.Violations, v)
+		}
+	}
+
+	return report, nil
+}
+
+func (d *Document) checkInteraction(i pactfile.HTTPInteraction) []Violation {
+	template, params, ok := d.findPath(i.Request.Path)
+	if !ok {
+		return []Violation{{Location: "request.path", Message: fmt.Sprintf("%s is not documented", i.Request.Path)}}
+	}
+
+	item, err := d.pathItem(d.Paths[template])
+	if err != nil {
+		return []Violation{{Location: "request.path", Message: err.Error()}}
+	}
+	op, ok := item.Operations()[i.Request.Method]
+	if !ok {
+		return []Violation{{Location: "request.method", Message: fmt.Sprintf("%s is not documented for %s", i.Request.Method, template)}}
+	}
+
+	violations := d.checkParameters(template, op, params, i.Request)
+	violations = append(violations, d.checkRequestBody(op, i.Request)...)
+
+	return append(violations, d.checkResponse(op, i.Response)...)
+}
+
+// findPath returns the path template of the document matching the path, and the values
+// of its parameters. Templates with fewer parameters take precedence, so that e.g.
+// /users/me is preferred to /users/{id}.
+func (d *Document) findPath(path string) (string, map[string]string, bool) {
+	candidates := []string{path}
+	for _, server := range d.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			continue
+		}
+		if base := strings.TrimSuffix(u.Path, "/"); base != "" && strings.HasPrefix(path, base+"/") {
+			candidates = append(candidates, strings.TrimPrefix(path, base))
+		}
+	}
+
+	var found string
+	var foundParams map[string]string
+
+	for _, candidate := range candidates {
+		for _, template := range d.sortedPaths() {
+			params, ok := matchTemplate(template, candidate)
+			if ok && (foundParams == nil || len(params) < len(foundParams)) {
+				found, foundParams = template, params
+			}
+		}
+	}
+
+	return found, foundParams, foundParams != nil
+}
+
+// matchTemplate matches a path against a path template such as /users/{id}
+func matchTemplate(template string, path string) (map[string]string, bool) {
+	var pattern strings.Builder
+	var names []string
+	last := 0
+
+	for _, loc := range pathParameter.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		pattern.WriteString(`([^/]+)`)
+		names = append(names, template[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+
+	re, err := regexp.Compile("^" + pattern.String() + "$")
+	if err != nil {
+		return nil, false
+	}
+	match := re.FindStringSubmatch(path)
+	if match == nil {
+		return nil, false
+	}
+
+	params := make(map[string]string, len(names))
+	for i, name := range names {
+		value, err := url.PathUnescape(match[i+1])
+		if err != nil {
+			value = match[i+1]
+		}
+		params[name] = value
+	}
+
+	return params, true
+}
+
+func (d *Document) checkParameters(template string, op *Operation, pathParams map[string]string, request pactfile.HTTPRequest) []Violation {
+	params, err := d.Parameters(template, op)
+	if err != nil {
+		return []Violation{{Location: "request", Message: err.Error()}}
+	}
+
+	var violations []Violation
+	documented := make(map[string]bool)
+
+	for _, p := range params {
+		var values []string
+		var location string
+
+		switch p.In {
+		case "path":
+			location = "request.path"
+			if value, ok := pathParams[p.Name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			location = "request.query." + p.Name
+			values = request.Query[p.Name]
+			documented[p.Name] = true
+		case "header":
+			location = "request.header." + p.Name
+			values = pactfile.Header(request.Headers, p.Name)
+		default:
+			continue
+		}
+
+		if len(values) == 0 {
+			if p.Required {
+				violations = append(violations, Violation{Location: location, Message: fmt.Sprintf("required %s parameter %s is missing", p.In, p.Name)})
+			}
+			continue
+		}
+
+		for _, value := range values {
+			v := &validation{doc: d}
+			v.validate(p.Schema, d.parameterValue(p.Schema, value), nil)
+			for _, violation := range v.violations {
+				violations = append(violations, Violation{Location: location, Message: fmt.Sprintf("parameter %s: %s", p.Name, violation.Message)})
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(request.Query) {
+		if !documented[name] {
+			violations = append(violations, Violation{Location: "request.query." + name, Message: "query parameter is not documented"})
+		}
+	}
+
+	return violations
+}
+
+func (d *Document) checkRequestBody(op *Operation, request pactfile.HTTPRequest) []Violation {
+	body, err := d.RequestBody(op)
+	if err != nil {
+		return []Violation{{Location: "request.body", Message: err.Error()}}
+	}
+
+	if request.Body == nil {
+		if body != nil && body.Required {
+			return []Violation{{Location: "request.body", Message: "a request body is required"}}
+		}
+		return nil
+	}
+	if body == nil {
+		return []Violation{{Location: "request.body", Message: "the operation does not accept a request body"}}
+	}
+
+	return d.checkBody("request", body.Content, request.Body, request.Rules[pactfile.RulesBody], true)
+}
+
+func (d *Document) checkResponse(op *Operation, response pactfile.HTTPResponse) []Violation {
+	_, documented, err := d.selectResponse(op, response.Status)
+	if err != nil {
+		return []Violation{{Location: "response.status", Message: fmt.Sprintf("status %d is not documented", response.Status)}}
+	}
+
+	var violations []Violation
+	for _, name := range sortedKeys(response.Headers) {
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		var header *Header
+		for k, h := range documented.Headers {
+			if strings.EqualFold(k, name) {
+				if header, err = d.Header(h); err != nil {
+					return append(violations, Violation{Location: "response.header." + name, Message: err.Error()})
+				}
+			}
+		}
+		if header == nil {
+			violations = append(violations, Violation{Location: "response.header." + name, Message: "header is not documented"})
+			continue
+		}
+
+		for _, value := range response.Headers[name] {
+			v := &validation{doc: d}
+			v.validate(header.Schema, d.parameterValue(header.Schema, value), nil)
+			for _, violation := range v.violations {
+				violations = append(violations, Violation{Location: "response.header." + name, Message: violation.Message})
+			}
+		}
+	}
+
+	if response.Body == nil {
+		return violations
+	}
+	if len(documented.Content) == 0 {
+		return append(violations, Violation{Location: "response.body", Message: fmt.Sprintf("no body is documented for status %d", response.Status)})
+	}
+
+	return append(violations, d.checkBody("response", documented.Content, response.Body, response.Rules[pactfile.RulesBody], false)...)
+}
+
+// checkBody checks the content type of a body, and validates JSON bodies against the schema
+func (d *Document) checkBody(part string, content map[string]*MediaType, body *pactfile.Body, rules map[string]*matchingrules.Rule, required bool) []Violation {
+	location := part + ".body"
+
+	mediaType, ok := findMediaType(content, body.ContentType)
+	if !ok {
+		return []Violation{{Location: location, Message: fmt.Sprintf("content type %s is not documented", body.ContentType)}}
+	}
+	if !body.JSON || mediaType == nil {
+		return nil
+	}
+
+	v := &validation{doc: d, rules: rules, required: required}
+	v.validate(mediaType.Schema, body.Content, nil)
+
+	for i := range v.violations {
+		v.violations[i].Location = location + "." + v.violations[i].Location
+	}
+
+	return v.violations
+}
+
+// findMediaType returns the documented media type of the content type, which may match
+// a range such as application/* or */*. If the content type isn't known, the JSON media
+// type is used.
+func findMediaType(content map[string]*MediaType, contentType string) (*MediaType, bool) {
+	if contentType == "" {
+		if _, m, ok := JSONMediaType(content); ok {
+			return m, true
+		}
+		return nil, len(content) > 0
+	}
+
+	actual := mediaTypeOf(contentType)
+	ranges := []string{actual, strings.Split(actual, "/")[0] + "/*", "*/*"}
+	for _, r := range ranges {
+		for k, m := range content {
+			if mediaTypeOf(k) == r {
+				return m, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// mediaTypeOf returns the media type of a content type without its parameters
+func mediaTypeOf(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
@@ -0,0 +1,211 @@
+package openapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPactFile(t *testing.T) {
+	report, err := CheckPactFile("testdata/web-users.json", "testdata/users.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Interactions)
+	assert.Empty(t, report.Violations)
+	assert.True(t, report.OK())
+
+	_, err = CheckPactFile("testdata/missing.json", "testdata/users.yaml")
+	assert.Error(t, err)
+}
+
+// v3Pact returns a V3 pact with a single interaction
+func v3Pact(request string, response string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"consumer": {"name": "web"},
+		"provider": {"name": "users"},
+		"interactions": [{"description": "an interaction", "request": %s, "response": %s}],
+		"metadata": {"pactSpecification": {"version": "3.0.0"}}
+	}`, request, response))
+}
+
+func TestCheckPact(t *testing.T) {
+	doc := loadUsers(t)
+
+	tests := []struct {
+		name     string
+		request  string
+		response string
+		want     []Violation
+	}{
+		{
+			name:     "undocumented path",
+			request:  `{"method": "GET", "path": "/accounts"}`,
+			response: `{"status": 200}`,
+			want:     []Violation{{Location: "request.path", Message: "/accounts is not documented"}},
+		},
+		{
+			name:     "undocumented method",
+			request:  `{"method": "PATCH", "path": "/users/1"}`,
+			response: `{"status": 200}`,
+			want:     []Violation{{Location: "request.method", Message: "PATCH is not documented for /users/{id}"}},
+		},
+		{
+			name:     "invalid path parameter",
+			request:  `{"method": "DELETE", "path": "/users/billy"}`,
+			response: `{"status": 204}`,
+			want:     []Violation{{Location: "request.path", Message: "parameter id: expected integer, got string"}},
+		},
+		{
+			name:     "undocumented status",
+			request:  `{"method": "DELETE", "path": "/users/1"}`,
+			response: `{"status": 500}`,
+			want:     []Violation{{Location: "response.status", Message: "status 500 is not documented"}},
+		},
+		{
+			name:     "query parameters",
+			request:  `{"method": "GET", "path": "/users", "query": {"page": ["first"], "limit": ["10"]}}`,
+			response: `{"status": 200}`,
+			want: []Violation{
+				{Location: "request.query.page", Message: "parameter page: expected integer, got string"},
+				{Location: "request.query.limit", Message: "query parameter is not documented"},
+			},
+		},
+		{
+			name:     "missing required parameters and body",
+			request:  `{"method": "POST", "path": "/users"}`,
+			response: `{"status": 201, "headers": {"Location": "/users/1"}}`,
+			want: []Violation{
+				{Location: "request.header.X-Request-Id", Message: "required header parameter X-Request-Id is missing"},
+				{Location: "request.body", Message: "a request body is required"},
+			},
+		},
+		{
+			name: "request body",
+			request: `{"method": "POST", "path": "/users",
+				"headers": {"X-Request-Id": "fc763eba-0905-41c5-a27f-3934ab26786c", "Content-Type": "application/json"},
+				"body": {"name": "billy", "email": "billy", "age": 21}}`,
+			response: `{"status": 400}`,
+			want: []Violation{
+				{Location: "request.body.$.age", Message: "property is not documented"},
+				{Location: "request.body.$.email", Message: `"billy" is not a valid email`},
+			},
+		},
+		{
+			name: "request content type",
+			request: `{"method": "POST", "path": "/users",
+				"headers": {"X-Request-Id": "fc763eba-0905-41c5-a27f-3934ab26786c", "Content-Type": "text/plain"},
+				"body": "billy"}`,
+			response: `{"status": 400}`,
+			want:     []Violation{{Location: "request.body", Message: "content type text/plain is not documented"}},
+		},
+		{
+			name:    "response headers and body",
+			request: `{"method": "GET", "path": "/users/1"}`,
+			response: `{"status": 200, "headers": {"Content-Type": "application/json", "X-Version": "1"},
+				"body": {"id": "1", "name": "billy", "status": "deleted", "roles": ["Admin", "a", "b", "c"], "manager": {"id": 2, "age": 50}}}`,
+			want: []Violation{
+				{Location: "response.header.X-Version", Message: "header is not documented"},
+				{Location: "response.body.$.id", Message: "expected integer, got string"},
+				{Location: "response.body.$.manager.age", Message: "property is not documented"},
+				{Location: "response.body.$.roles", Message: "has at least 4 items, but at most 3 are allowed"},
+				{Location: "response.body.$.roles[0]", Message: `"Admin" does not match the pattern "^[a-z]+$"`},
+				{Location: "response.body.$.status", Message: "deleted is not one of the allowed values [active disabled]"},
+			},
+		},
+		{
+			name:    "matchers",
+			request: `{"method": "GET", "path": "/users/1"}`,
+			response: `{"status": 200, "headers": {"Content-Type": "application/json"},
+				"body": {"id": 1, "name": "billy", "roles": ["admin"]},
+				"matchingRules": {"body": {
+					"$.id": {"matchers": [{"match": "decimal"}]},
+					"$.name": {"matchers": [{"match": "null"}]},
+					"$.roles": {"matchers": [{"match": "type", "min": 1, "max": 5}]}
+				}}}`,
+			want: []Violation{
+				{Location: "response.body.$.id", Message: "the decimal matcher allows number values, expected integer"},
+				{Location: "response.body.$.name", Message: "the null matcher allows null, which is not allowed"},
+				{Location: "response.body.$.roles", Message: "the type matcher allows up to 5 items, but at most 3 are allowed"},
+			},
+		},
+		{
+			name:     "undocumented response body",
+			request:  `{"method": "DELETE", "path": "/users/1"}`,
+			response: `{"status": 204, "body": {"deleted": true}}`,
+			want:     []Violation{{Location: "response.body", Message: "no body is documented for status 204"}},
+		},
+		{
+			name:     "default response",
+			request:  `{"method": "GET", "path": "/users/1"}`,
+			response: `{"status": 404, "headers": {"Content-Type": "application/problem+json"}, "body": {"message": 1}}`,
+			want:     []Violation{{Location: "response.body.$.message", Message: "expected string, got integer"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := doc.CheckPact(v3Pact(tt.request, tt.response))
+			assert.NoError(t, err)
+
+			for i := range tt.want {
+				tt.want[i].Interaction = "an interaction"
+			}
+			assert.Equal(t, tt.want, report.Violations)
+		})
+	}
+}
+
+func TestCheckPactV2Rules(t *testing.T) {
+	doc := loadUsers(t)
+
+	report, err := doc.CheckPact([]byte(`{
+		"consumer": {"name": "web"},
+		"provider": {"name": "users"},
+		"interactions": [{
+			"description": "a request for users",
+			"request": {"method": "GET", "path": "/users", "query": "page=1"},
+			"response": {
+				"status": 200,
+				"headers": {"Content-Type": "application/json"},
+				"body": [{"id": 1, "name": "billy"}],
+				"matchingRules": {"$.body[*].id": {"match": "type"}, "$.body[*].name": {"match": "integer"}}
+			}
+		}],
+		"metadata": {"pactSpecification": {"version": "2.0.0"}}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Violation{{
+		Interaction: "a request for users",
+		Location:    "response.body.$[0].name",
+		Message:     "the integer matcher allows integer values, expected string",
+	}}, report.Violations)
+	assert.Equal(t, "a request for users: response.body.$[0].name: the integer matcher allows integer values, expected string", report.Violations[0].String())
+}
+
+func TestFindPath(t *testing.T) {
+	doc, err := Parse([]byte(`
+openapi: 3.1.0
+paths:
+  /users/{id}: {}
+  /users/me: {}
+  /files/{name}.{ext}: {}
+`))
+	assert.NoError(t, err)
+
+	template, params, ok := doc.findPath("/users/me")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/me", template)
+	assert.Empty(t, params)
+
+	template, params, ok = doc.findPath("/users/a%20b")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/{id}", template)
+	assert.Equal(t, map[string]string{"id": "a b"}, params)
+
+	_, params, ok = doc.findPath("/files/report.pdf")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"name": "report", "ext": "pdf"}, params)
+
+	_, _, ok = doc.findPath("/users/1/avatar")
+	assert.False(t, ok)
+}
@@ -0,0 +1,334 @@
+// Package openapi reads OpenAPI 3 documents, so that consumer interactions can be
+// generated from a provider's documented operations, and pacts can be checked
+// against them.
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrOperationNotFound is returned when the document has no operation with the given ID
+var ErrOperationNotFound = errors.New("operation not found")
+
+// Document is an OpenAPI 3.0 or 3.1 document. Only the parts of the specification
+// that describe HTTP requests and responses are read.
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Servers    []Server             `yaml:"servers"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+}
+
+// Info is the metadata of the API
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Server is a server hosting the API. The paths of the document are relative to its URL.
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+// Components holds the reusable objects of the document, that may be referenced with $ref
+type Components struct {
+	Schemas       map[string]*Schema      `yaml:"schemas"`
+	Parameters    map[string]*Parameter   `yaml:"parameters"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	Responses     map[string]*Response    `yaml:"responses"`
+	Headers       map[string]*Header      `yaml:"headers"`
+}
+
+// PathItem describes the operations available on a single path
+type PathItem struct {
+	Ref        string       `yaml:"$ref"`
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Options    *Operation   `yaml:"options"`
+	Head       *Operation   `yaml:"head"`
+	Patch      *Operation   `yaml:"patch"`
+	Trace      *Operation   `yaml:"trace"`
+}
+
+// Operations returns the operations of the path, keyed by HTTP method
+func (p *PathItem) Operations() map[string]*Operation {
+	operations := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		http.MethodGet:     p.Get,
+		http.MethodPut:     p.Put,
+		http.MethodPost:    p.Post,
+		http.MethodDelete:  p.Delete,
+		http.MethodOptions: p.Options,
+		http.MethodHead:    p.Head,
+		http.MethodPatch:   p.Patch,
+		http.MethodTrace:   p.Trace,
+	} {
+		if op != nil {
+			operations[method] = op
+		}
+	}
+
+	return operations
+}
+
+// Operation is a single API operation on a path
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter is a path, query, header or cookie parameter of an operation
+type Parameter struct {
+	Ref      string      `yaml:"$ref"`
+	Name     string      `yaml:"name"`
+	In       string      `yaml:"in"`
+	Required bool        `yaml:"required"`
+	Schema   *Schema     `yaml:"schema"`
+	Example  interface{} `yaml:"example"`
+}
+
+// RequestBody describes the body of a request
+type RequestBody struct {
+	Ref      string                `yaml:"$ref"`
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Headers     map[string]*Header    `yaml:"headers"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// Header describes a response header
+type Header struct {
+	Ref      string      `yaml:"$ref"`
+	Required bool        `yaml:"required"`
+	Schema   *Schema     `yaml:"schema"`
+	Example  interface{} `yaml:"example"`
+}
+
+// MediaType describes the content of a body of a given media type
+type MediaType struct {
+	Schema  *Schema     `yaml:"schema"`
+	Example interface{} `yaml:"example"`
+}
+
+// Load reads an OpenAPI document in YAML or JSON format from a file
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OpenAPI document %s: %w", path, err)
+	}
+
+	return doc, nil
+}
+
+// Parse parses an OpenAPI document in YAML or JSON format
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, only OpenAPI 3 documents are supported", doc.OpenAPI)
+	}
+
+	return &doc, nil
+}
+
+// Operation returns the operation with the given ID, along with its method and path
+func (d *Document) Operation(operationID string) (string, string, *Operation, error) {
+	for _, path := range d.sortedPaths() {
+		item, err := d.pathItem(d.Paths[path])
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		for method, op := range item.Operations() {
+			if op.OperationID == operationID {
+				return method, path, op, nil
+			}
+		}
+	}
+
+	return "", "", nil, fmt.Errorf("%w: %s", ErrOperationNotFound, operationID)
+}
+
+// OperationIDs returns the IDs of all operations in the document, in order
+func (d *Document) OperationIDs() ([]string, error) {
+	ids := make([]string, 0)
+	for _, path := range d.sortedPaths() {
+		item, err := d.pathItem(d.Paths[path])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, op := range item.Operations() {
+			if op.OperationID != "" {
+				ids = append(ids, op.OperationID)
+			}
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+func (d *Document) sortedPaths() []string {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// Parameters returns the parameters of the operation on the given path, including those
+// shared by all operations on the path, with references resolved
+func (d *Document) Parameters(path string, op *Operation) ([]*Parameter, error) {
+	item, err := d.pathItem(d.Paths[path])
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]*Parameter, 0)
+	index := make(map[string]int)
+
+	for _, list := range [][]*Parameter{item.Parameters, op.Parameters} {
+		for _, p := range list {
+			param, err := d.parameter(p)
+			if err != nil {
+				return nil, err
+			}
+
+			// Operation parameters override path parameters with the same name and location
+			key := param.In + ":" + param.Name
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+
+	return params, nil
+}
+
+func (d *Document) pathItem(item *PathItem) (*PathItem, error) {
+	if item == nil || item.Ref == "" {
+		return item, nil
+	}
+
+	return nil, fmt.Errorf("unsupported path item reference %q", item.Ref)
+}
+
+func (d *Document) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+
+	return resolve(p.Ref, "parameters", d.Components.Parameters)
+}
+
+// RequestBody returns the request body of the operation with references resolved, if any
+func (d *Document) RequestBody(op *Operation) (*RequestBody, error) {
+	if op.RequestBody == nil || op.RequestBody.Ref == "" {
+		return op.RequestBody, nil
+	}
+
+	return resolve(op.RequestBody.Ref, "requestBodies", d.Components.RequestBodies)
+}
+
+// Response returns the response with references resolved
+func (d *Document) Response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+
+	return resolve(r.Ref, "responses", d.Components.Responses)
+}
+
+// Header returns the header with references resolved
+func (d *Document) Header(h *Header) (*Header, error) {
+	if h == nil || h.Ref == "" {
+		return h, nil
+	}
+
+	return resolve(h.Ref, "headers", d.Components.Headers)
+}
+
+// Schema returns the schema with references resolved
+func (d *Document) Schema(s *Schema) (*Schema, error) {
+	for s != nil && s.Ref != "" {
+		var err error
+		if s, err = resolve(s.Ref, "schemas", d.Components.Schemas); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// resolve looks up a local reference to a component, e.g. #/components/schemas/User
+func resolve[T any](ref string, kind string, components map[string]*T) (*T, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return nil, fmt.Errorf("unsupported reference %q, only references to #/components/%s are supported", ref, kind)
+	}
+
+	name := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(ref, prefix))
+	component, ok := components[name]
+	if !ok || component == nil {
+		return nil, fmt.Errorf("unresolved reference %q", ref)
+	}
+
+	return component, nil
+}
+
+// JSONMediaType returns the JSON media type of the content, preferring application/json.
+// It returns false if there is no JSON content.
+func JSONMediaType(content map[string]*MediaType) (string, *MediaType, bool) {
+	if m, ok := content["application/json"]; ok {
+		return "application/json", m, true
+	}
+
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		mediaType := strings.TrimSpace(strings.Split(t, ";")[0])
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return t, content[t], true
+		}
+	}
+
+	return "", nil, false
+}
@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+)
+
+// pathParameter matches a parameter in a path template, e.g. {id}
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Interaction is the request and response of an operation, described with matchers
+type Interaction struct {
+	OperationID string
+	Description string
+
+	Method string
+
+	// Path is an example of the path of the operation, e.g. /users/1 for /users/{id}
+	Path string
+
+	// PathMatcher matches the path if it has parameters, and is nil otherwise
+	PathMatcher matchers.Matcher
+
+	// Query and RequestHeaders hold the required parameters of the operation
+	Query          matchers.QueryMatcher
+	RequestHeaders matchers.HeadersMatcher
+
+	// RequestBody is the JSON request body, or nil if there is none
+	RequestBody interface{}
+
+	Status int
+
+	// ResponseHeaders hold the required headers of the response
+	ResponseHeaders matchers.HeadersMatcher
+
+	// ResponseBody is the JSON response body, or nil if there is none
+	ResponseBody interface{}
+}
+
+// Interaction returns the interaction for the operation with the given ID and response
+// status. If status is 0, the first successful (2xx) response is used.
+func (d *Document) Interaction(operationID string, status int) (*Interaction, error) {
+	method, path, op, err := d.Operation(operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	description := op.Summary
+	if description == "" {
+		description = fmt.Sprintf("a %s request to %s", method, path)
+	}
+
+	i := &Interaction{
+		OperationID: operationID,
+		Description: description,
+		Method:      method,
+	}
+
+	if err := d.request(i, path, op); err != nil {
+		return nil, fmt.Errorf("%s: request: %w", operationID, err)
+	}
+	if err := d.response(i, op, status); err != nil {
+		return nil, fmt.Errorf("%s: response: %w", operationID, err)
+	}
+
+	return i, nil
+}
+
+func (d *Document) request(i *Interaction, path string, op *Operation) error {
+	params, err := d.Parameters(path, op)
+	if err != nil {
+		return err
+	}
+
+	pathParams := make(map[string]*Parameter)
+	for _, p := range params {
+		switch {
+		case p.In == "path":
+			pathParams[p.Name] = p
+		case p.In == "query" && p.Required:
+			if i.Query == nil {
+				i.Query = make(matchers.QueryMatcher)
+			}
+			m, err := d.parameterMatcher(p)
+			if err != nil {
+				return err
+			}
+			i.Query[p.Name] = []matchers.Matcher{m}
+		case p.In == "header" && p.Required:
+			if i.RequestHeaders == nil {
+				i.RequestHeaders = make(matchers.HeadersMatcher)
+			}
+			m, err := d.parameterMatcher(p)
+			if err != nil {
+				return err
+			}
+			i.RequestHeaders[p.Name] = []matchers.Matcher{m}
+		}
+	}
+
+	if err := d.path(i, path, pathParams); err != nil {
+		return err
+	}
+
+	body, err := d.RequestBody(op)
+	if err != nil || body == nil {
+		return err
+	}
+
+	mediaType, content, ok := JSONMediaType(body.Content)
+	if !ok {
+		log.Println("[WARN] only JSON request bodies are supported, the body of", i.OperationID, "is ignored")
+		return nil
+	}
+
+	if i.RequestBody, err = d.body(content); err != nil {
+		return err
+	}
+	if i.RequestHeaders == nil {
+		i.RequestHeaders = make(matchers.HeadersMatcher)
+	}
+	i.RequestHeaders["Content-Type"] = []matchers.Matcher{matchers.S(mediaType)}
+
+	return nil
+}
+
+// path sets the example path of the interaction, and a matcher for its parameters
+func (d *Document) path(i *Interaction, template string, params map[string]*Parameter) error {
+	var example, pattern strings.Builder
+	last := 0
+
+	for _, loc := range pathParameter.FindAllStringSubmatchIndex(template, -1) {
+		literal := template[last:loc[0]]
+		example.WriteString(literal)
+		pattern.WriteString(regexp.QuoteMeta(literal))
+
+		name := template[loc[2]:loc[3]]
+		value, regex, err := d.pathValue(name, params[name])
+		if err != nil {
+			return err
+		}
+		example.WriteString(value)
+		pattern.WriteString(regex)
+
+		last = loc[1]
+	}
+
+	example.WriteString(template[last:])
+	i.Path = example.String()
+
+	if last > 0 {
+		pattern.WriteString(regexp.QuoteMeta(template[last:]))
+		i.PathMatcher = matchers.Regex(i.Path, "^"+pattern.String()+"$")
+	}
+
+	return nil
+}
+
+// pathValue returns an example value of a path parameter, and a regular expression matching it
+func (d *Document) pathValue(name string, p *Parameter) (string, string, error) {
+	if p == nil {
+		return name, `[^/]+`, nil
+	}
+
+	schema, err := d.Schema(p.Schema)
+	if err != nil {
+		return "", "", err
+	}
+	if schema == nil {
+		schema = &Schema{}
+	}
+
+	example := p.Example
+	if example == nil {
+		example, _ = schema.example()
+	}
+
+	switch schemaType(schema) {
+	case "integer":
+		if _, ok := toInt(example); !ok {
+			example = 1
+		}
+		return fmt.Sprint(example), `\d+`, nil
+	case "number":
+		if example == nil {
+			example = 1.5
+		}
+		return fmt.Sprint(example), `\d+(\.\d+)?`, nil
+	}
+
+	value := name
+	if s, ok := example.(string); ok && s != "" {
+		value = s
+	}
+
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			values = append(values, regexp.QuoteMeta(fmt.Sprint(v)))
+		}
+		return fmt.Sprint(schema.Enum[0]), "(" + strings.Join(values, "|") + ")", nil
+	}
+
+	if schema.Pattern != "" && matches(schema.Pattern, value) {
+		return value, "(" + strings.TrimSuffix(strings.TrimPrefix(schema.Pattern, "^"), "$") + ")", nil
+	}
+
+	if format, ok := formats[schema.Format]; ok {
+		if !matches(format.regex, value) {
+			value = format.example
+		}
+		return value, "(" + strings.Trim(format.regex, "^$") + ")", nil
+	}
+
+	return value, `[^/]+`, nil
+}
+
+// parameterMatcher returns a matcher for a query or header parameter. Parameters are
+// strings, so typed schemas are matched with a regular expression.
+func (d *Document) parameterMatcher(p *Parameter) (matchers.Matcher, error) {
+	schema, err := d.Schema(p.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		schema = &Schema{}
+	}
+
+	example := p.Example
+	if example == nil {
+		example, _ = schema.example()
+	}
+
+	switch schemaType(schema) {
+	case "integer":
+		if _, ok := toInt(example); !ok {
+			example = 1
+		}
+		return matchers.Regex(fmt.Sprint(example), `^-?\d+$`), nil
+	case "number":
+		if example == nil {
+			example = 1.5
+		}
+		return matchers.Regex(fmt.Sprint(example), `^-?\d+(\.\d+)?$`), nil
+	case "boolean":
+		if _, ok := example.(bool); !ok {
+			example = true
+		}
+		return matchers.Regex(fmt.Sprint(example), `^(true|false)$`), nil
+	}
+
+	if example != nil {
+		schema = &Schema{
+			Type:    schema.Type,
+			Format:  schema.Format,
+			Pattern: schema.Pattern,
+			Enum:    schema.Enum,
+			Example: fmt.Sprint(example),
+		}
+	}
+
+	return stringMatcher(schema, schema.Example, schema.Example != nil), nil
+}
+
+func (d *Document) body(content *MediaType) (interface{}, error) {
+	if content.Schema == nil {
+		if content.Example != nil {
+			return content.Example, nil
+		}
+		return nil, nil
+	}
+
+	return d.Matcher(content.Schema)
+}
+
+func (d *Document) response(i *Interaction, op *Operation, status int) error {
+	code, response, err := d.selectResponse(op, status)
+	if err != nil {
+		return err
+	}
+	i.Status = code
+
+	for _, name := range sortedKeys(response.Headers) {
+		header, err := d.Header(response.Headers[name])
+		if err != nil {
+			return err
+		}
+		if !header.Required {
+			continue
+		}
+
+		m, err := d.parameterMatcher(&Parameter{
+			Name:    name,
+			Schema:  header.Schema,
+			Example: header.Example,
+		})
+		if err != nil {
+			return err
+		}
+		if i.ResponseHeaders == nil {
+			i.ResponseHeaders = make(matchers.HeadersMatcher)
+		}
+		i.ResponseHeaders[name] = []matchers.Matcher{m}
+	}
+
+	mediaType, content, ok := JSONMediaType(response.Content)
+	if !ok {
+		if len(response.Content) > 0 {
+			log.Println("[WARN] only JSON response bodies are supported, the body of", i.OperationID, "is ignored")
+		}
+		return nil
+	}
+
+	if i.ResponseBody, err = d.body(content); err != nil {
+		return err
+	}
+	if i.ResponseHeaders == nil {
+		i.ResponseHeaders = make(matchers.HeadersMatcher)
+	}
+	i.ResponseHeaders["Content-Type"] = []matchers.Matcher{matchers.S(mediaType)}
+
+	return nil
+}
+
+// selectResponse returns the response for the status, or the first successful response if 0
+func (d *Document) selectResponse(op *Operation, status int) (int, *Response, error) {
+	codes := sortedKeys(op.Responses)
+
+	if status == 0 {
+		for _, code := range codes {
+			if strings.HasPrefix(code, "2") {
+				return d.responseFor(code, op.Responses[code])
+			}
+		}
+		return 0, nil, fmt.Errorf("no successful response is documented")
+	}
+
+	// An explicit status takes precedence over a range (e.g. 4XX), which takes precedence over the default
+	for _, code := range []string{strconv.Itoa(status), fmt.Sprintf("%dXX", status/100), "default"} {
+		for _, c := range codes {
+			if strings.EqualFold(c, code) {
+				_, response, err := d.responseFor(c, op.Responses[c])
+				return status, response, err
+			}
+		}
+	}
+
+	return 0, nil, fmt.Errorf("no response is documented for status %d", status)
+}
+
+func (d *Document) responseFor(code string, r *Response) (int, *Response, error) {
+	response, err := d.Response(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		// A range such as 2XX
+		status = http.StatusOK
+	}
+
+	return status, response, nil
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
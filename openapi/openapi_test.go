@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadUsers(t *testing.T) *Document {
+	doc, err := Load("testdata/users.yaml")
+	assert.NoError(t, err)
+
+	return doc
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse([]byte(`{"swagger": "2.0"}`))
+	assert.ErrorContains(t, err, "only OpenAPI 3 documents are supported")
+
+	doc, err := Parse([]byte(`{"openapi": "3.1.0", "info": {"title": "json"}, "paths": {}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "json", doc.Info.Title)
+}
+
+func TestOperation(t *testing.T) {
+	doc := loadUsers(t)
+
+	method, path, op, err := doc.Operation("getUser")
+	assert.NoError(t, err)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/users/{id}", path)
+	assert.NotNil(t, op)
+
+	_, _, _, err = doc.Operation("unknown")
+	assert.ErrorIs(t, err, ErrOperationNotFound)
+
+	ids, err := doc.OperationIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"createUser", "deleteUser", "getAvatar", "getUser", "listUsers"}, ids)
+}
+
+func TestInteraction(t *testing.T) {
+	doc := loadUsers(t)
+
+	i, err := doc.Interaction("getUser", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "a GET request to /users/{id}", i.Description)
+	assert.Equal(t, "GET", i.Method)
+	assert.Equal(t, "/users/10", i.Path)
+	assert.Equal(t, matchers.Regex("/users/10", `^/users/\d+$`), i.PathMatcher)
+	assert.Equal(t, 200, i.Status)
+	assert.Equal(t, matchers.HeadersMatcher{"Content-Type": {matchers.S("application/json")}}, i.ResponseHeaders)
+	assert.Equal(t, matchers.StructMatcher{
+		"id":        matchers.Integer(10),
+		"name":      matchers.Like("billy"),
+		"createdAt": matchers.Regex("2000-02-01T12:30:00Z", dateTimeRegex),
+		"status":    matchers.Regex("active", "^(active|disabled)$"),
+		"roles":     matchers.ArrayMinMaxLike(matchers.Regex("admin", "^[a-z]+$"), 1, 3),
+	}, i.ResponseBody)
+}
+
+func TestInteractionRequest(t *testing.T) {
+	doc := loadUsers(t)
+
+	i, err := doc.Interaction("createUser", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "a request to create a user", i.Description)
+	assert.Nil(t, i.PathMatcher)
+	assert.Equal(t, matchers.HeadersMatcher{
+		"Content-Type": {matchers.S("application/json")},
+		"X-Request-Id": {matchers.Regex("fc763eba-0905-41c5-a27f-3934ab26786c", uuidRegex)},
+	}, i.RequestHeaders)
+	assert.Equal(t, matchers.StructMatcher{
+		"name":  matchers.Like("billy"),
+		"email": matchers.Regex("user@example.com", emailRegex),
+	}, i.RequestBody)
+	assert.Equal(t, 201, i.Status)
+	assert.Equal(t, []matchers.Matcher{matchers.Like("/users/10")}, i.ResponseHeaders["Location"])
+
+	i, err = doc.Interaction("listUsers", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, matchers.QueryMatcher{"page": {matchers.Regex("1", `^-?\d+$`)}}, i.Query)
+	body, _ := json.Marshal(i.ResponseBody)
+	assert.Contains(t, string(body), `"min":1`)
+}
+
+func TestInteractionStatus(t *testing.T) {
+	doc := loadUsers(t)
+
+	i, err := doc.Interaction("createUser", 400)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, i.Status)
+	assert.Equal(t, matchers.HeadersMatcher{"Content-Type": {matchers.S("application/problem+json")}}, i.ResponseHeaders)
+	assert.Equal(t, matchers.StructMatcher{"message": matchers.Like("string")}, i.ResponseBody)
+
+	i, err = doc.Interaction("getUser", 404)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, i.Status)
+
+	_, err = doc.Interaction("deleteUser", 500)
+	assert.ErrorContains(t, err, "no response is documented for status 500")
+
+	i, err = doc.Interaction("deleteUser", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 204, i.Status)
+	assert.Nil(t, i.ResponseBody)
+}
+
+func TestInteractionPathFormats(t *testing.T) {
+	doc := loadUsers(t)
+
+	i, err := doc.Interaction("getAvatar", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/fc763eba-0905-41c5-a27f-3934ab26786c/avatar.png", i.Path)
+	assert.Equal(t, matchers.Regex(i.Path,
+		`^/users/([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})/avatar\.png$`), i.PathMatcher)
+	assert.Nil(t, i.ResponseBody)
+}
+
+func TestMatcherRecursive(t *testing.T) {
+	doc, err := Parse([]byte(`
+openapi: 3.0.0
+paths: {}
+components:
+  schemas:
+    Node:
+      type: object
+      required: [child]
+      properties:
+        child:
+          $ref: "#/components/schemas/Node"
+`))
+	assert.NoError(t, err)
+
+	_, err = doc.Matcher(&Schema{Ref: "#/components/schemas/Node"})
+	assert.ErrorContains(t, err, "recursive schema #/components/schemas/Node")
+
+	_, err = doc.Matcher(&Schema{Ref: "#/components/schemas/Missing"})
+	assert.ErrorContains(t, err, `unresolved reference "#/components/schemas/Missing"`)
+}
+
+func TestMatcherTypes(t *testing.T) {
+	doc := &Document{}
+	one := 2
+
+	tests := []struct {
+		name   string
+		schema *Schema
+		want   interface{}
+	}{
+		{"string", &Schema{Type: Types{"string"}}, matchers.Like("string")},
+		{"string example", &Schema{Type: Types{"string"}, Example: "x"}, matchers.Like("x")},
+		{"date", &Schema{Type: Types{"string"}, Format: "date"}, matchers.Regex("2000-02-01", dateRegex)},
+		{"pattern without example", &Schema{Type: Types{"string"}, Pattern: `^\d+$`}, matchers.Like("string")},
+		{"number", &Schema{Type: Types{"number"}}, matchers.Like(1.1)},
+		{"boolean", &Schema{Type: Types{"boolean"}}, matchers.Like(true)},
+		{"nullable 3.1", &Schema{Type: Types{"integer", "null"}}, matchers.Integer(1)},
+		{"null", &Schema{Type: Types{"null"}}, matchers.Null{}},
+		{"min items", &Schema{Type: Types{"array"}, MinItems: &one, Items: &Schema{Type: Types{"boolean"}}}, matchers.EachLike(matchers.Like(true), 2)},
+		{"map", &Schema{Type: Types{"object"}, AdditionalProperties: &AdditionalProperties{Allowed: true, Schema: &Schema{Type: Types{"integer"}}}},
+			matchers.EachKeyLike("key", matchers.StructMatcher{"key": matchers.Integer(1)})},
+		{"one of", &Schema{OneOf: []*Schema{{Type: Types{"boolean"}}, {Type: Types{"string"}}}}, matchers.Like(true)},
+		{"untyped", &Schema{}, matchers.Like("value")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := doc.Matcher(tt.schema)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"gopkg.in/yaml.v3"
+)
+
+// Regular expressions matching the string formats of a schema
+const (
+	dateTimeRegex = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`
+	dateRegex     = `^\d{4}-\d{2}-\d{2}$`
+	uuidRegex     = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	emailRegex    = `^[^@\s]+@[^@\s]+$`
+	ipv4Regex     = `^(\d{1,3}\.){3}\d{1,3}$`
+)
+
+// formats are the examples and regular expressions of the supported string formats
+var formats = map[string]struct {
+	example string
+	regex   string
+}{
+	"date-time": {"2000-02-01T12:30:00Z", dateTimeRegex},
+	"date":      {"2000-02-01", dateRegex},
+	"uuid":      {"fc763eba-0905-41c5-a27f-3934ab26786c", uuidRegex},
+	"email":     {"user@example.com", emailRegex},
+	"ipv4":      {"127.0.0.1", ipv4Regex},
+}
+
+// Schema is a JSON schema, as used by OpenAPI 3.0 and 3.1
+type Schema struct {
+	Ref                  string                `yaml:"$ref"`
+	Type                 Types                 `yaml:"type"`
+	Format               string                `yaml:"format"`
+	Pattern              string                `yaml:"pattern"`
+	Enum                 []interface{}         `yaml:"enum"`
+	Example              interface{}           `yaml:"example"`
+	Examples             []interface{}         `yaml:"examples"`
+	Default              interface{}           `yaml:"default"`
+	Nullable             bool                  `yaml:"nullable"`
+	Items                *Schema               `yaml:"items"`
+	MinItems             *int                  `yaml:"minItems"`
+	MaxItems             *int                  `yaml:"maxItems"`
+	Properties           map[string]*Schema    `yaml:"properties"`
+	Required             []string              `yaml:"required"`
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	AllOf                []*Schema             `yaml:"allOf"`
+	OneOf                []*Schema             `yaml:"oneOf"`
+	AnyOf                []*Schema             `yaml:"anyOf"`
+}
+
+// Types are the types allowed by a schema. OpenAPI 3.0 allows a single type, while
+// 3.1 allows a list (e.g. ["string", "null"]).
+type Types []string
+
+// UnmarshalYAML reads either a single type or a list of types
+func (t *Types) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = Types{node.Value}
+		return nil
+	}
+
+	var types []string
+	if err := node.Decode(&types); err != nil {
+		return err
+	}
+	*t = types
+
+	return nil
+}
+
+// Is reports whether the type is allowed
+func (t Types) Is(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Primary returns the first allowed type other than null, or "" if there isn't one
+func (t Types) Primary() string {
+	for _, v := range t {
+		if v != "null" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// AdditionalProperties is either a boolean, or the schema of the additional properties of an object
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalYAML reads either a boolean or a schema
+func (a *AdditionalProperties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+
+	a.Allowed = true
+
+	return node.Decode(&a.Schema)
+}
+
+// example returns the documented example of the schema, if any
+func (s *Schema) example() (interface{}, bool) {
+	switch {
+	case s.Example != nil:
+		return s.Example, true
+	case len(s.Examples) > 0:
+		return s.Examples[0], true
+	case len(s.Enum) > 0:
+		return s.Enum[0], true
+	case s.Default != nil:
+		return s.Default, true
+	}
+
+	return nil, false
+}
+
+// Matcher returns a body matching the schema, using matchers derived from its types,
+// formats and patterns, and its examples. Only the required properties of objects are
+// included, as the optional properties may not be present.
+func (d *Document) Matcher(schema *Schema) (interface{}, error) {
+	return d.matcher(schema, nil)
+}
+
+func (d *Document) matcher(schema *Schema, refs []string) (interface{}, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	if schema.Ref != "" {
+		for _, ref := range refs {
+			if ref == schema.Ref {
+				return nil, fmt.Errorf("recursive schema %s", schema.Ref)
+			}
+		}
+		refs = append(refs, schema.Ref)
+
+		resolved, err := d.Schema(schema)
+		if err != nil {
+			return nil, err
+		}
+		return d.matcher(resolved, refs)
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged, err := d.mergeAllOf(schema)
+		if err != nil {
+			return nil, err
+		}
+		return d.matcher(merged, refs)
+	}
+
+	// Any of the alternatives is valid, so the first is used
+	for _, alternatives := range [][]*Schema{schema.OneOf, schema.AnyOf} {
+		if len(alternatives) > 0 {
+			return d.matcher(alternatives[0], refs)
+		}
+	}
+
+	example, hasExample := schema.example()
+
+	switch schemaType(schema) {
+	case "string":
+		return stringMatcher(schema, example, hasExample), nil
+	case "integer":
+		if i, ok := toInt(example); ok {
+			return matchers.Integer(i), nil
+		}
+		return matchers.Integer(1), nil
+	case "number":
+		if hasExample {
+			return matchers.Like(example), nil
+		}
+		return matchers.Like(1.1), nil
+	case "boolean":
+		if b, ok := example.(bool); ok {
+			return matchers.Like(b), nil
+		}
+		return matchers.Like(true), nil
+	case "array":
+		item, err := d.matcher(schema.Items, refs)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			item = matchers.Like("value")
+		}
+		min := 1
+		if schema.MinItems != nil && *schema.MinItems > min {
+			min = *schema.MinItems
+		}
+		if schema.MaxItems != nil {
+			return matchers.ArrayMinMaxLike(item, min, *schema.MaxItems), nil
+		}
+		return matchers.EachLike(item, min), nil
+	case "object":
+		return d.objectMatcher(schema, refs)
+	case "null":
+		return matchers.Null{}, nil
+	}
+
+	// A schema without a type accepts any value
+	if hasExample {
+		return matchers.Like(example), nil
+	}
+
+	return matchers.Like("value"), nil
+}
+
+// schemaType returns the type of the schema, inferring it from its keywords if it isn't given
+func schemaType(schema *Schema) string {
+	if t := schema.Type.Primary(); t != "" {
+		return t
+	}
+
+	switch {
+	case len(schema.Properties) > 0 || schema.AdditionalProperties != nil:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	case schema.Format != "" || schema.Pattern != "":
+		return "string"
+	case schema.Type.Is("null"):
+		return "null"
+	}
+
+	return ""
+}
+
+func stringMatcher(schema *Schema, example interface{}, hasExample bool) matchers.Matcher {
+	s, _ := example.(string)
+	if !hasExample {
+		s = ""
+	}
+
+	if len(schema.Enum) > 1 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			values = append(values, regexp.QuoteMeta(fmt.Sprint(v)))
+		}
+		return matchers.Regex(fmt.Sprint(schema.Enum[0]), "^("+strings.Join(values, "|")+")$")
+	}
+
+	if schema.Pattern != "" {
+		if s != "" && matches(schema.Pattern, s) {
+			return matchers.Regex(s, schema.Pattern)
+		}
+		log.Printf("[WARN] no example matching the pattern %q was found, the value will be matched by type\n", schema.Pattern)
+	}
+
+	if format, ok := formats[schema.Format]; ok {
+		if s == "" || !matches(format.regex, s) {
+			s = format.example
+		}
+		return matchers.Regex(s, format.regex)
+	}
+
+	if s == "" {
+		s = "string"
+	}
+
+	return matchers.Like(s)
+}
+
+// matches reports whether the value matches the (possibly unanchored) pattern
+func matches(pattern string, value string) bool {
+	re, err := regexp.Compile(pattern)
+
+	return err == nil && re.MatchString(value)
+}
+
+func (d *Document) objectMatcher(schema *Schema, refs []string) (interface{}, error) {
+	if len(schema.Properties) == 0 && schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		value, err := d.matcher(schema.AdditionalProperties.Schema, refs)
+		if err != nil {
+			return nil, err
+		}
+		return matchers.EachKeyLike("key", matchers.StructMatcher{"key": value}), nil
+	}
+
+	object := matchers.StructMatcher{}
+	for _, name := range schema.Required {
+		value, err := d.matcher(schema.Properties[name], refs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if value == nil {
+			value = matchers.Like("value")
+		}
+		object[name] = value
+	}
+
+	return object, nil
+}
+
+// mergeAllOf combines the schemas of allOf into a single object schema
+func (d *Document) mergeAllOf(schema *Schema) (*Schema, error) {
+	merged := &Schema{
+		Type:       Types{"object"},
+		Properties: make(map[string]*Schema),
+	}
+	required := make(map[string]bool)
+
+	parts := append([]*Schema{}, schema.AllOf...)
+	own := *schema
+	own.AllOf = nil
+	parts = append(parts, &own)
+
+	for _, part := range parts {
+		resolved, err := d.Schema(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolved.AllOf) > 0 {
+			if resolved, err = d.mergeAllOf(resolved); err != nil {
+				return nil, err
+			}
+		}
+
+		// Only objects can be combined, otherwise the first (e.g. a string with a format) wins
+		if t := schemaType(resolved); t != "object" && t != "" {
+			return resolved, nil
+		}
+
+		for k, v := range resolved.Properties {
+			merged.Properties[k] = v
+		}
+		for _, k := range resolved.Required {
+			required[k] = true
+		}
+	}
+
+	for k := range required {
+		merged.Required = append(merged.Required, k)
+	}
+	sort.Strings(merged.Required)
+
+	return merged, nil
+}
+
+// toInt converts a decoded YAML or JSON number to an int
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		return int(n), n == float64(int(n))
+	}
+
+	return 0, false
+}
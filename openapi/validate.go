@@ -0,0 +1,334 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+)
+
+// validation validates a request or response body, or a parameter, against a schema
+type validation struct {
+	doc *Document
+
+	// rules are the matching rules of the body, keyed by path
+	rules map[string]*matchingrules.Rule
+
+	// required is set when missing required properties are violations, i.e. for
+	// requests. Responses may contain properties the consumer doesn't use.
+	required bool
+
+	violations []Violation
+}
+
+func (v *validation) add(path []string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		Location: matchingrules.FormatPath(path),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// validate reports the parts of the value, at the path of the body, that the schema does not allow
+func (v *validation) validate(schema *Schema, value interface{}, path []string) {
+	schema, err := v.doc.Schema(schema)
+	if err != nil {
+		v.add(path, "%s", err)
+		return
+	}
+	if schema == nil {
+		return
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged, err := v.doc.mergeAllOf(schema)
+		if err != nil {
+			v.add(path, "%s", err)
+			return
+		}
+		v.validate(merged, value, path)
+		return
+	}
+
+	for _, alternatives := range [][]*Schema{schema.OneOf, schema.AnyOf} {
+		if len(alternatives) > 0 {
+			v.alternatives(alternatives, value, path)
+			return
+		}
+	}
+
+	rule := matchingrules.Lookup(v.rules, path)
+	v.matchers(schema, rule, path)
+
+	actual := jsonType(value)
+	if actual == "null" {
+		if !schema.Nullable && !schema.Type.Is("null") && schemaType(schema) != "" {
+			v.add(path, "null is not allowed, expected %s", schemaType(schema))
+		}
+		return
+	}
+
+	if !allows(schema, actual) {
+		v.add(path, "expected %s, got %s", strings.Join(allowedTypes(schema), " or "), actual)
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		v.add(path, "%v is not one of the allowed values %v", value, schema.Enum)
+	}
+
+	switch t := value.(type) {
+	case string:
+		if schema.Pattern != "" && !matches(schema.Pattern, t) {
+			v.add(path, "%q does not match the pattern %q", t, schema.Pattern)
+		}
+		if format, ok := formats[schema.Format]; ok && !matches(format.regex, t) {
+			v.add(path, "%q is not a valid %s", t, schema.Format)
+		}
+	case []interface{}:
+		v.array(schema, rule, t, path)
+	case map[string]interface{}:
+		v.object(schema, t, path)
+	}
+}
+
+// alternatives validates a value against oneOf or anyOf, which requires it to be valid
+// for any of them. Otherwise, the violations of the closest alternative are reported.
+func (v *validation) alternatives(alternatives []*Schema, value interface{}, path []string) {
+	var closest []Violation
+
+	for i, alternative := range alternatives {
+		attempt := &validation{doc: v.doc, rules: v.rules, required: v.required}
+		attempt.validate(alternative, value, path)
+		if len(attempt.violations) == 0 {
+			return
+		}
+		if i == 0 || len(attempt.violations) < len(closest) {
+			closest = attempt.violations
+		}
+	}
+
+	v.add(path, "does not match any of the %d allowed schemas", len(alternatives))
+	v.violations = append(v.violations, closest...)
+}
+
+// matchers reports matchers that allow values of a type the schema doesn't allow,
+// as the provider would then be free to return (or receive) such values
+func (v *validation) matchers(schema *Schema, rule *matchingrules.Rule, path []string) {
+	if rule == nil {
+		return
+	}
+
+	for _, m := range rule.Matchers {
+		match, _ := m["match"].(string)
+		implied := matcherTypes[match]
+		if implied == "" {
+			continue
+		}
+		if implied == "null" {
+			if !schema.Nullable && !schema.Type.Is("null") && schemaType(schema) != "" {
+				v.add(path, "the %s matcher allows null, which is not allowed", match)
+			}
+			continue
+		}
+		if !allows(schema, implied) {
+			v.add(path, "the %s matcher allows %s values, expected %s", match, implied, strings.Join(allowedTypes(schema), " or "))
+		}
+	}
+}
+
+// matcherTypes are the JSON types of the values that matchers allow, for those that
+// constrain it. Other matchers (such as type) allow values of the type of the example.
+var matcherTypes = map[string]string{
+	"integer":   "integer",
+	"decimal":   "number",
+	"number":    "number",
+	"boolean":   "boolean",
+	"null":      "null",
+	"date":      "string",
+	"time":      "string",
+	"timestamp": "string",
+	"datetime":  "string",
+	"include":   "string",
+}
+
+func (v *validation) array(schema *Schema, rule *matchingrules.Rule, values []interface{}, path []string) {
+	// A matcher with a minimum allows the provider to return any number of items above it
+	min := len(values)
+	if rule != nil {
+		for _, m := range rule.Matchers {
+			if n, ok := number(m["min"]); ok {
+				min = int(n)
+			}
+			if n, ok := number(m["max"]); ok && schema.MaxItems != nil && int(n) > *schema.MaxItems {
+				v.add(path, "the %s matcher allows up to %d items, but at most %d are allowed", m["match"], int(n), *schema.MaxItems)
+			}
+		}
+	}
+
+	if schema.MinItems != nil && len(values) < *schema.MinItems && min < *schema.MinItems {
+		v.add(path, "has %d items, but at least %d are required", len(values), *schema.MinItems)
+	}
+	if schema.MaxItems != nil && min > *schema.MaxItems {
+		v.add(path, "has at least %d items, but at most %d are allowed", min, *schema.MaxItems)
+	}
+
+	for i, item := range values {
+		v.validate(schema.Items, item, append(append([]string{}, path...), matchingrules.Index(i)))
+	}
+}
+
+func (v *validation) object(schema *Schema, values map[string]interface{}, path []string) {
+	for _, name := range sortedKeys(values) {
+		property := append(append([]string{}, path...), name)
+
+		if s, ok := schema.Properties[name]; ok {
+			v.validate(s, values[name], property)
+			continue
+		}
+
+		additional := schema.AdditionalProperties
+		switch {
+		case additional != nil && additional.Schema != nil:
+			v.validate(additional.Schema, values[name], property)
+		case additional != nil && additional.Allowed:
+		default:
+			v.add(property, "property is not documented")
+		}
+	}
+
+	if v.required {
+		for _, name := range schema.Required {
+			if _, ok := values[name]; !ok {
+				v.add(path, "required property %q is missing", name)
+			}
+		}
+	}
+}
+
+// jsonType returns the JSON schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch t := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if t == math.Trunc(t) {
+			return "integer"
+		}
+		return "number"
+	case int, int64:
+		return "integer"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// allowedTypes returns the types the schema allows, or nil if it allows any
+func allowedTypes(schema *Schema) []string {
+	if len(schema.Type) > 0 {
+		return schema.Type
+	}
+	if t := schemaType(schema); t != "" {
+		return []string{t}
+	}
+
+	return nil
+}
+
+// allows reports whether the schema allows values of the JSON type
+func allows(schema *Schema, actual string) bool {
+	types := allowedTypes(schema)
+	if len(types) == 0 {
+		return true
+	}
+
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if a, ok := number(e); ok {
+			if b, ok := number(value); ok && a == b {
+				return true
+			}
+			continue
+		}
+		if e == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// number converts a decoded YAML or JSON number to a float64
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+// parameterValue converts the string value of a parameter to the type of its schema,
+// so it can be validated. Values that can't be converted are validated as strings.
+func (d *Document) parameterValue(schema *Schema, value string) interface{} {
+	schema, err := d.Schema(schema)
+	if err != nil || schema == nil {
+		return value
+	}
+
+	switch schemaType(schema) {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return json.Number(value)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "array":
+		values := make([]interface{}, 0)
+		for _, item := range strings.Split(value, ",") {
+			values = append(values, d.parameterValue(schema.Items, item))
+		}
+		return values
+	}
+
+	return value
+}
@@ -14,8 +14,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/pact-foundation/pact-go/v2/command"
 	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/pact-foundation/pact-go/v2/internal/version"
 	logging "github.com/pact-foundation/pact-go/v2/log"
 	"github.com/pact-foundation/pact-go/v2/message"
 	"github.com/pact-foundation/pact-go/v2/models"
@@ -42,7 +42,7 @@ func NewVerifier() *Verifier {
 	native.Init(string(logging.LogLevel()))
 
 	return &Verifier{
-		handle: native.NewVerifier("pact-go", strings.TrimPrefix(command.Version, "v")),
+		handle: native.NewVerifier("pact-go", strings.TrimPrefix(version.Version, "v")),
 	}
 
 }
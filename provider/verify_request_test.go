@@ -4,13 +4,13 @@ import (
 	"os"
 	"testing"
 
-	"github.com/pact-foundation/pact-go/v2/command"
 	"github.com/pact-foundation/pact-go/v2/internal/native"
+	"github.com/pact-foundation/pact-go/v2/internal/version"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestVerifyRequestValidate(t *testing.T) {
-	handle := native.NewVerifier("pact-go", command.Version)
+	handle := native.NewVerifier("pact-go", version.Version)
 
 	t.Run("local validation", func(t *testing.T) {
 		tests := []struct {
@@ -49,7 +49,7 @@ func TestVerifyRequestValidate(t *testing.T) {
 	})
 
 	t.Run("broker integration", func(t *testing.T) {
-		handle := native.NewVerifier("pact-go", command.Version)
+		handle := native.NewVerifier("pact-go", version.Version)
 
 		tests := []struct {
 			name    string
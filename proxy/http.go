@@ -156,7 +156,8 @@ func (c customTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 func createProxy(target *url.URL, ignorePrefix string) *httputil.ReverseProxy {
 	targetQuery := target.RawQuery
 	director := func(req *http.Request) {
-		if !strings.HasPrefix(req.URL.Path, ignorePrefix) {
+		// Without a prefix, there are no internal requests and everything is proxied
+		if ignorePrefix == "" || !strings.HasPrefix(req.URL.Path, ignorePrefix) {
 			log.Println("[DEBUG] setting proxy to target")
 			log.Println("[DEBUG] incoming request", req.URL)
 			req.URL.Scheme = target.Scheme
@@ -3,6 +3,7 @@ package proxy
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
@@ -88,3 +89,30 @@ func TestHTTPReverseProxy(t *testing.T) {
 		t.Errorf("want non-zero port, got %v", port)
 	}
 }
+
+func TestCreateProxy(t *testing.T) {
+	target := &url.URL{Scheme: "http", Host: "127.0.0.1:1234", Path: "/base"}
+
+	tests := []struct {
+		name         string
+		ignorePrefix string
+		path         string
+		wantHost     string
+		wantPath     string
+	}{
+		{"proxies requests to the target", "/__setup", "/users", "127.0.0.1:1234", "/base/users"},
+		{"sends internal requests to localhost", "/__setup", "/__setup/state", "localhost", "/__setup/state"},
+		{"proxies all requests without a prefix", "", "/users", "127.0.0.1:1234", "/base/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			createProxy(target, tt.ignorePrefix).Director(req)
+
+			if req.URL.Host != tt.wantHost || req.URL.Path != tt.wantPath {
+				t.Errorf("want request to %s%s, got %s%s", tt.wantHost, tt.wantPath, req.URL.Host, req.URL.Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+)
+
+// Regular expressions used by inferred matchers
+const (
+	uuidRegex      = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	timestampRegex = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`
+	dateRegex      = `^\d{4}-\d{2}-\d{2}$`
+	bearerRegex    = `^Bearer .+$`
+	basicRegex     = `^Basic .+$`
+)
+
+var (
+	uuidPattern    = regexp.MustCompile(uuidRegex)
+	integerPattern = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Headers that are set by the client or transport, and aren't part of the contract
+var ignoredRequestHeaders = map[string]bool{
+	"Accept-Encoding":   true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Cookie":            true,
+	"Host":              true,
+	"User-Agent":        true,
+	"X-Forwarded-For":   true,
+	"X-Forwarded-Host":  true,
+	"X-Forwarded-Proto": true,
+}
+
+// Headers that are set by the server or transport, and aren't part of the contract
+var ignoredResponseHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Date":              true,
+	"Keep-Alive":        true,
+	"Server":            true,
+	"Set-Cookie":        true,
+	"Transfer-Encoding": true,
+}
+
+// Headers whose values differ between requests
+var volatileHeaders = map[string]bool{
+	"Etag":             true,
+	"Last-Modified":    true,
+	"X-Correlation-Id": true,
+	"X-Request-Id":     true,
+}
+
+// inferString returns a matcher for a string that looks like a UUID or timestamp,
+// or nil if it doesn't
+func inferString(s string) matchers.Matcher {
+	switch {
+	case uuidPattern.MatchString(s):
+		return matchers.Regex(s, uuidRegex)
+	case isTimestamp(s):
+		return matchers.Regex(s, timestampRegex)
+	case isDate(s):
+		return matchers.Regex(s, dateRegex)
+	}
+
+	return nil
+}
+
+func isTimestamp(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+
+	return err == nil
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+
+	return err == nil
+}
+
+// isVolatileKey reports whether a JSON property is likely to hold a generated value,
+// e.g. "id", "userId", "user_id" or "accessToken"
+func isVolatileKey(key string) bool {
+	lower := strings.ToLower(key)
+
+	return lower == "id" || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID") ||
+		strings.HasSuffix(lower, "_id") || strings.Contains(lower, "token")
+}
+
+// inferValue returns the decoded JSON value with matchers in place of its volatile values.
+// key is the property the value belongs to, if any.
+func inferValue(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if m := inferString(v); m != nil {
+			return m
+		}
+		if isVolatileKey(key) {
+			return matchers.Like(v)
+		}
+	case json.Number:
+		if !isVolatileKey(key) {
+			return v
+		}
+		if i, err := v.Int64(); err == nil {
+			return matchers.Integer(int(i))
+		}
+		return matchers.Like(v)
+	case map[string]interface{}:
+		object := make(matchers.StructMatcher, len(v))
+		for k, item := range v {
+			object[k] = inferValue(k, item)
+		}
+		return object
+	case []interface{}:
+		// A list of objects is assumed to vary in length, and contain objects of the same shape
+		if len(v) > 0 && allObjects(v) {
+			return matchers.EachLike(inferValue(key, v[0]), 1)
+		}
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = inferValue(key, item)
+		}
+		return list
+	}
+
+	return value
+}
+
+func allObjects(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inferPath returns a matcher for a path containing numeric or UUID identifiers,
+// e.g. /users/10, or nil if it doesn't contain any
+func inferPath(path string) matchers.Matcher {
+	segments := strings.Split(path, "/")
+	volatile := false
+
+	for i, segment := range segments {
+		switch {
+		case integerPattern.MatchString(segment):
+			segments[i] = `\d+`
+			volatile = true
+		case uuidPattern.MatchString(segment):
+			segments[i] = strings.Trim(uuidRegex, "^$")
+			volatile = true
+		default:
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+
+	if !volatile {
+		return nil
+	}
+
+	return matchers.Regex(path, "^"+strings.Join(segments, "/")+"$")
+}
+
+// inferQuery returns the query parameters, with matchers for volatile values
+func inferQuery(query url.Values) matchers.QueryMatcher {
+	if len(query) == 0 {
+		return nil
+	}
+
+	result := make(matchers.QueryMatcher, len(query))
+	for k, values := range query {
+		for _, v := range values {
+			var m matchers.Matcher = matchers.S(v)
+			if inferred := inferString(v); inferred != nil {
+				m = inferred
+			}
+			result[k] = append(result[k], m)
+		}
+	}
+
+	return result
+}
+
+// inferHeaders returns the headers that form part of the contract, with matchers for
+// volatile values. Credentials are replaced with placeholders.
+func inferHeaders(headers http.Header, ignored map[string]bool) matchers.HeadersMatcher {
+	result := make(matchers.HeadersMatcher)
+
+	for k, values := range headers {
+		name := http.CanonicalHeaderKey(k)
+		if ignored[name] {
+			continue
+		}
+
+		for _, v := range values {
+			result[name] = append(result[name], inferHeader(name, v))
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func inferHeader(name string, value string) matchers.Matcher {
+	if name == "Authorization" {
+		switch {
+		case strings.HasPrefix(value, "Bearer "):
+			return matchers.Regex("Bearer token", bearerRegex)
+		case strings.HasPrefix(value, "Basic "):
+			return matchers.Regex("Basic dXNlcm5hbWU6cGFzc3dvcmQ=", basicRegex)
+		default:
+			return matchers.Like("credentials")
+		}
+	}
+
+	if m := inferString(value); m != nil {
+		return m
+	}
+
+	if volatileHeaders[name] {
+		return matchers.Like(value)
+	}
+
+	return matchers.S(value)
+}
+
+// decodeJSON decodes a JSON document, preserving numbers as written
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
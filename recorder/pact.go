@@ -0,0 +1,210 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/internal/pactfile"
+	"github.com/pact-foundation/pact-go/v2/matchers"
+)
+
+// Pact returns the recorded interactions as a V4 pact document
+func (r *Recorder) Pact() ([]byte, error) {
+	interactions := make([]interface{}, 0)
+
+	for _, i := range r.Interactions() {
+		request, err := requestJSON(i.Request)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode request of %q: %w", i.Description, err)
+		}
+		response, err := responseJSON(i.Response)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode response of %q: %w", i.Description, err)
+		}
+
+		interactions = append(interactions, map[string]interface{}{
+			"type":        "Synchronous/HTTP",
+			"description": i.Description,
+			"pending":     false,
+			"request":     request,
+			"response":    response,
+		})
+	}
+
+	pact := map[string]interface{}{
+		"consumer": map[string]interface{}{
+			"name": r.Consumer,
+		},
+		"provider": map[string]interface{}{
+			"name": r.Provider,
+		},
+		"interactions": interactions,
+		"metadata": map[string]interface{}{
+			"pactSpecification": map[string]interface{}{
+				"version": "4.0",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(pact); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WritePact writes the recorded interactions to the pact file at path, merging them
+// with any interactions already in the file unless overwrite is true
+func (r *Recorder) WritePact(path string, overwrite bool) error {
+	pact, err := r.Pact()
+	if err != nil {
+		return err
+	}
+
+	mode := pactfile.MergeMode
+	if overwrite {
+		mode = pactfile.OverwriteMode
+	}
+
+	return pactfile.Write(path, pact, mode)
+}
+
+// part accumulates the JSON form of a request or response
+type part struct {
+	fields     map[string]interface{}
+	rules      map[string]interface{}
+	generators map[string]interface{}
+}
+
+func newPart() *part {
+	return &part{
+		fields:     make(map[string]interface{}),
+		rules:      make(map[string]interface{}),
+		generators: make(map[string]interface{}),
+	}
+}
+
+func (p *part) json() map[string]interface{} {
+	if len(p.rules) > 0 {
+		p.fields["matchingRules"] = p.rules
+	}
+	if len(p.generators) > 0 {
+		p.fields["generators"] = p.generators
+	}
+
+	return p.fields
+}
+
+// values adds the query parameters or headers, with their rules under the given category
+func (p *part) values(field string, category string, values map[string][]matchers.Matcher) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	examples := make(map[string]interface{})
+	rules := make(map[string]interface{})
+
+	for _, k := range sortedKeys(values) {
+		list := make([]interface{}, 0)
+		for _, v := range values[k] {
+			result, err := matchingrules.Split(v)
+			if err != nil {
+				return err
+			}
+			list = append(list, result.Example)
+			if rule, ok := result.Rules["$"]; ok {
+				rules[k] = rule
+			}
+		}
+		examples[k] = list
+	}
+
+	p.fields[field] = examples
+	if len(rules) > 0 {
+		p.rules[category] = rules
+	}
+
+	return nil
+}
+
+func (p *part) body(body *Body) error {
+	if body == nil {
+		return nil
+	}
+
+	content := map[string]interface{}{
+		"contentType": body.ContentType,
+		"encoded":     false,
+	}
+
+	switch {
+	case body.JSON != nil:
+		result, err := matchingrules.Split(body.JSON)
+		if err != nil {
+			return err
+		}
+		content["content"] = result.Example
+		if len(result.Rules) > 0 {
+			p.rules["body"] = result.Rules
+		}
+		if len(result.Generators) > 0 {
+			p.generators["body"] = result.Generators
+		}
+	case isText(body.ContentType):
+		content["content"] = string(body.Raw)
+	default:
+		content["content"] = base64.StdEncoding.EncodeToString(body.Raw)
+		content["encoded"] = "base64"
+	}
+
+	p.fields["body"] = content
+
+	return nil
+}
+
+func requestJSON(request Request) (map[string]interface{}, error) {
+	p := newPart()
+	p.fields["method"] = request.Method
+	p.fields["path"] = request.Path
+
+	if request.PathMatcher != nil {
+		result, err := matchingrules.Split(request.PathMatcher)
+		if err != nil {
+			return nil, err
+		}
+		p.rules["path"] = result.Rules["$"]
+	}
+
+	if err := p.values("query", "query", request.Query); err != nil {
+		return nil, err
+	}
+	if err := p.values("headers", "header", request.Headers); err != nil {
+		return nil, err
+	}
+	if err := p.body(request.Body); err != nil {
+		return nil, err
+	}
+
+	return p.json(), nil
+}
+
+func responseJSON(response Response) (map[string]interface{}, error) {
+	p := newPart()
+	p.fields["status"] = response.Status
+
+	if err := p.values("headers", "header", response.Headers); err != nil {
+		return nil, err
+	}
+	if err := p.body(response.Body); err != nil {
+		return nil, err
+	}
+
+	return p.json(), nil
+}
@@ -0,0 +1,264 @@
+// Package recorder captures HTTP traffic passing through the proxy package's reverse
+// proxy, and turns it into consumer interactions: either a V4 pact file, or Go source
+// using the consumer.V4HTTPMockProvider DSL, to be refined and committed.
+//
+// Matchers are inferred for values that are likely to vary between runs, such as
+// identifiers, UUIDs, timestamps and credentials.
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/pact-foundation/pact-go/v2/proxy"
+)
+
+// Recorder records the interactions between a consumer and provider
+type Recorder struct {
+	// Consumer is the name of the Consumer/Client.
+	Consumer string
+
+	// Provider is the name of the Providing service.
+	Provider string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	recorded     map[string]bool
+}
+
+// Interaction is a recorded request and response, with matchers inferred
+type Interaction struct {
+	Description string
+	Request     Request
+	Response    Response
+}
+
+// Request is a recorded request
+type Request struct {
+	Method string
+	Path   string
+
+	// PathMatcher matches the path, if it contains identifiers. Nil otherwise.
+	PathMatcher matchers.Matcher
+
+	// RawQuery is the recorded query string, without the leading "?"
+	RawQuery string
+
+	Query   matchers.QueryMatcher
+	Headers matchers.HeadersMatcher
+	Body    *Body
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int
+	Headers matchers.HeadersMatcher
+	Body    *Body
+}
+
+// Body is a recorded request or response body
+type Body struct {
+	ContentType string
+
+	// JSON is the decoded JSON body with matchers inferred, or nil if the body is not JSON
+	JSON interface{}
+
+	// Raw is the body as recorded
+	Raw []byte
+}
+
+// New creates a recorder for the given consumer and provider
+func New(consumer string, provider string) *Recorder {
+	return &Recorder{
+		Consumer: consumer,
+		Provider: provider,
+		recorded: make(map[string]bool),
+	}
+}
+
+// Middleware returns the proxy middleware that records each request and response
+func (r *Recorder) Middleware() proxy.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				log.Println("[ERROR] unable to read request body:", err)
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Request an uncompressed response, so the recorded body is readable
+			req.Header.Del("Accept-Encoding")
+			headers := req.Header.Clone()
+
+			rw := &responseRecorder{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+			next.ServeHTTP(rw, req)
+
+			r.Record(req.Method, req.URL, headers, body, rw.status, w.Header().Clone(), rw.body.Bytes())
+		})
+	}
+}
+
+// Record adds a request and response to the recording. Exchanges identical to one
+// already recorded are ignored.
+func (r *Recorder) Record(method string, u *url.URL, requestHeaders http.Header, requestBody []byte,
+	status int, responseHeaders http.Header, responseBody []byte) {
+	key := fmt.Sprintf("%s %s?%s\n%s\n%d\n%s", method, u.Path, u.RawQuery, requestBody, status, responseBody)
+
+	interaction := Interaction{
+		Request: Request{
+			Method:      method,
+			Path:        u.Path,
+			PathMatcher: inferPath(u.Path),
+			RawQuery:    u.RawQuery,
+			Query:       inferQuery(u.Query()),
+			Headers:     inferHeaders(requestHeaders, ignoredRequestHeaders),
+			Body:        newBody(requestHeaders.Get("Content-Type"), requestBody),
+		},
+		Response: Response{
+			Status:  status,
+			Headers: inferHeaders(responseHeaders, ignoredResponseHeaders),
+			Body:    newBody(responseHeaders.Get("Content-Type"), responseBody),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recorded[key] {
+		log.Println("[DEBUG] ignoring duplicate request", method, u.Path)
+		return
+	}
+	r.recorded[key] = true
+
+	interaction.Description = r.describe(interaction)
+	log.Println("[INFO] recorded", interaction.Description)
+
+	r.interactions = append(r.interactions, interaction)
+}
+
+// Interactions returns the interactions recorded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interactions := make([]Interaction, len(r.interactions))
+	copy(interactions, r.interactions)
+
+	return interactions
+}
+
+// describe returns a unique description for the interaction. The caller must hold r.mu.
+func (r *Recorder) describe(interaction Interaction) string {
+	base := fmt.Sprintf("a %s request to %s", interaction.Request.Method, interaction.Request.Path)
+	if interaction.Response.Status >= http.StatusBadRequest {
+		base = fmt.Sprintf("%s returning %d", base, interaction.Response.Status)
+	}
+
+	description := base
+	for n := 2; r.hasDescription(description); n++ {
+		description = fmt.Sprintf("%s (%d)", base, n)
+	}
+
+	return description
+}
+
+func (r *Recorder) hasDescription(description string) bool {
+	for _, i := range r.interactions {
+		if i.Description == description {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newBody creates the recorded body, decoding JSON so that matchers can be inferred
+func newBody(contentType string, raw []byte) *Body {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	body := &Body{
+		ContentType: contentType,
+		Raw:         raw,
+	}
+	if body.ContentType == "" {
+		body.ContentType = http.DetectContentType(raw)
+	}
+
+	if isJSON(body.ContentType) {
+		if value, err := decodeJSON(raw); err == nil {
+			body.JSON = inferValue("", value)
+		} else {
+			log.Println("[WARN] unable to parse JSON body, it will be recorded as is:", err)
+		}
+	}
+
+	return body
+}
+
+// isJSON reports whether the content type is JSON, e.g. application/json or application/hal+json
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isText reports whether a body of the content type can be recorded as a string
+func isText(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	return strings.HasPrefix(mediaType, "text/") || isJSON(contentType) ||
+		strings.HasSuffix(mediaType, "xml") || mediaType == "application/x-www-form-urlencoded"
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// responseRecorder captures the response written to the consumer
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows the proxy to flush the underlying response writer
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,223 @@
+package recorder
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+func provider(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"name": "billy"}`, string(body))
+		assert.Empty(t, r.Header.Get("Accept-Encoding"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "abc123")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{
+			"id": 10,
+			"name": "billy",
+			"reference": "fc763eba-0905-41c5-a27f-3934ab26786c",
+			"createdAt": "2020-01-01T08:00:45Z",
+			"roles": [{"roleId": 1, "name": "admin"}, {"roleId": 2, "name": "user"}]
+		}`))
+	}
+}
+
+func record(t *testing.T, r *Recorder, path string) {
+	server := httptest.NewServer(r.Middleware()(provider(t)))
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL+path, strings.NewReader(`{"name": "billy"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Contains(t, string(body), `"name": "billy"`)
+}
+
+func TestRecorderMiddleware(t *testing.T) {
+	r := New("consumer", "provider")
+	record(t, r, "/organisations/3/users?since=2020-01-01")
+
+	interactions := r.Interactions()
+	assert.Len(t, interactions, 1)
+
+	i := interactions[0]
+	assert.Equal(t, "a POST request to /organisations/3/users", i.Description)
+	assert.Equal(t, "POST", i.Request.Method)
+	assert.Equal(t, matchers.Regex("/organisations/3/users", `^/organisations/\d+/users$`), i.Request.PathMatcher)
+	assert.Equal(t, matchers.QueryMatcher{"since": {matchers.Regex("2020-01-01", dateRegex)}}, i.Request.Query)
+	assert.Equal(t, matchers.HeadersMatcher{
+		"Authorization": {matchers.Regex("Bearer token", bearerRegex)},
+		"Content-Type":  {matchers.S("application/json")},
+	}, i.Request.Headers)
+	assert.Equal(t, matchers.StructMatcher{"name": "billy"}, i.Request.Body.JSON)
+
+	assert.Equal(t, http.StatusCreated, i.Response.Status)
+	assert.Equal(t, matchers.HeadersMatcher{
+		"Content-Type": {matchers.S("application/json")},
+		"X-Request-Id": {matchers.Like("abc123")},
+	}, i.Response.Headers)
+	assert.Equal(t, matchers.StructMatcher{
+		"id":        matchers.Integer(10),
+		"name":      "billy",
+		"reference": matchers.Regex("fc763eba-0905-41c5-a27f-3934ab26786c", uuidRegex),
+		"createdAt": matchers.Regex("2020-01-01T08:00:45Z", timestampRegex),
+		"roles": matchers.EachLike(matchers.StructMatcher{
+			"roleId": matchers.Integer(1),
+			"name":   "admin",
+		}, 1),
+	}, i.Response.Body.JSON)
+}
+
+func TestRecorderDuplicates(t *testing.T) {
+	r := New("consumer", "provider")
+	record(t, r, "/users")
+	record(t, r, "/users")
+	record(t, r, "/users?page=2")
+
+	interactions := r.Interactions()
+	assert.Len(t, interactions, 2)
+	assert.Equal(t, "a POST request to /users", interactions[0].Description)
+	assert.Equal(t, "a POST request to /users (2)", interactions[1].Description)
+}
+
+func TestRecorderPact(t *testing.T) {
+	r := New("consumer", "provider")
+	record(t, r, "/users/3")
+
+	pact, err := r.Pact()
+	assert.NoError(t, err)
+
+	var doc struct {
+		Interactions []struct {
+			Type     string                 `json:"type"`
+			Request  map[string]interface{} `json:"request"`
+			Response map[string]interface{} `json:"response"`
+		} `json:"interactions"`
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+	assert.NoError(t, json.Unmarshal(pact, &doc))
+	assert.Len(t, doc.Interactions, 1)
+	assert.Equal(t, "Synchronous/HTTP", doc.Interactions[0].Type)
+
+	request, _ := json.Marshal(doc.Interactions[0].Request)
+	assert.JSONEq(t, `{
+		"method": "POST",
+		"path": "/users/3",
+		"headers": {
+			"Authorization": ["Bearer token"],
+			"Content-Type": ["application/json"]
+		},
+		"body": {
+			"content": {"name": "billy"},
+			"contentType": "application/json",
+			"encoded": false
+		},
+		"matchingRules": {
+			"path": {"combine": "AND", "matchers": [{"match": "regex", "regex": "^/users/\\d+$"}]},
+			"header": {
+				"Authorization": {"combine": "AND", "matchers": [{"match": "regex", "regex": "^Bearer .+$"}]}
+			}
+		}
+	}`, string(request))
+
+	rules := doc.Interactions[0].Response["matchingRules"].(map[string]interface{})
+	body := rules["body"].(map[string]interface{})
+	assert.Contains(t, body, "$.id")
+	assert.Contains(t, body, "$.roles")
+	assert.Contains(t, body, "$.roles[*].roleId")
+	assert.NotContains(t, body, "$.name")
+	assert.Equal(t, map[string]interface{}{"version": "4.0"}, doc.Metadata["pactSpecification"])
+}
+
+func TestRecorderWritePact(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "consumer-provider.json")
+
+	first := New("consumer", "provider")
+	record(t, first, "/users")
+	assert.NoError(t, first.WritePact(file, false))
+
+	second := New("consumer", "provider")
+	record(t, second, "/users")
+	record(t, second, "/accounts")
+	assert.NoError(t, second.WritePact(file, false))
+
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+
+	var doc struct {
+		Interactions []interface{} `json:"interactions"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &doc))
+	assert.Len(t, doc.Interactions, 2)
+}
+
+func TestRecorderGoSource(t *testing.T) {
+	r := New("my-consumer", "user service")
+	record(t, r, "/users/3?verbose=true")
+
+	src, err := r.GoSource("client")
+	assert.NoError(t, err)
+
+	for _, expected := range []string{
+		"package client",
+		"func TestMyConsumerUserServicePact(t *testing.T) {",
+		"WithRequestPathMatcher(\"POST\", matchers.Regex(\"/users/3\", `^/users/\\d+$`), func(b *consumer.V4RequestBuilder) {",
+		`b.Header("Authorization", matchers.Regex("Bearer token", "^Bearer .+$"))`,
+		`b.Query("verbose", matchers.S("true"))`,
+		`"id":        matchers.Integer(10),`,
+		`WillRespondWith(201, func(b *consumer.V4ResponseBuilder) {`,
+		"req, err := http.NewRequest(\"POST\", config.BaseURL()+\"/users/3?verbose=true\", strings.NewReader(`{\"name\": \"billy\"}`))",
+		`req.Header.Add("Authorization", "Bearer token")`,
+	} {
+		assert.Contains(t, string(src), expected)
+	}
+}
+
+func TestInferPath(t *testing.T) {
+	assert.Nil(t, inferPath("/users"))
+	assert.Equal(t, matchers.Regex("/users/10/roles", `^/users/\d+/roles$`), inferPath("/users/10/roles"))
+	assert.Equal(t,
+		matchers.Regex("/a.b/fc763eba-0905-41c5-a27f-3934ab26786c",
+			`^/a\.b/[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		inferPath("/a.b/fc763eba-0905-41c5-a27f-3934ab26786c"))
+}
+
+func TestIsVolatileKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"id":          true,
+		"ID":          true,
+		"userId":      true,
+		"user_id":     true,
+		"accessToken": true,
+		"name":        false,
+		"idle":        false,
+		"valid":       false,
+	} {
+		assert.Equal(t, want, isVolatileKey(key), key)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	src, err := New("my-consumer", "2 user service2").GoSource("client")
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "func TestMyConsumerUserService2Pact(t *testing.T) {")
+}
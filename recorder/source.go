@@ -0,0 +1,45 @@
+package recorder
+
+import (
+	"github.com/pact-foundation/pact-go/v2/internal/codegen"
+)
+
+// GoSource returns a Go test file in the given package, containing a consumer test
+// for each recorded interaction using the consumer.V4HTTPMockProvider DSL
+func (r *Recorder) GoSource(pkg string) ([]byte, error) {
+	file := codegen.TestFile{
+		Generator: "pact-go record",
+		Package:   pkg,
+		Consumer:  r.Consumer,
+		Provider:  r.Provider,
+	}
+
+	for _, i := range r.Interactions() {
+		file.Interactions = append(file.Interactions, codegen.Interaction{
+			Description:     i.Description,
+			Method:          i.Request.Method,
+			Path:            i.Request.Path,
+			PathMatcher:     i.Request.PathMatcher,
+			Query:           i.Request.Query,
+			RequestHeaders:  i.Request.Headers,
+			RequestBody:     sourceBody(i.Request.Body),
+			Status:          i.Response.Status,
+			ResponseHeaders: i.Response.Headers,
+			ResponseBody:    sourceBody(i.Response.Body),
+		})
+	}
+
+	return file.Source()
+}
+
+func sourceBody(body *Body) *codegen.Body {
+	if body == nil {
+		return nil
+	}
+
+	return &codegen.Body{
+		ContentType: body.ContentType,
+		JSON:        body.JSON,
+		Raw:         body.Raw,
+	}
+}
@@ -0,0 +1,238 @@
+package stub
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// mismatches returns the differences between the request (with the body and
+// provider states) and the request of the interaction
+func (s *Server) mismatches(i *Interaction, r *http.Request, body []byte, states []string) []Mismatch {
+	expected := i.Request
+	var mismatches []Mismatch
+	add := func(t string, key string, format string, args ...interface{}) {
+		mismatches = append(mismatches, Mismatch{Type: t, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, state := range states {
+		found := false
+		for _, ps := range i.ProviderStates {
+			found = found || ps.Name == state
+		}
+		if !found {
+			add("state", "", "the interaction has no provider state '%s'", state)
+		}
+	}
+
+	if !strings.EqualFold(expected.Method, r.Method) {
+		add("method", "", "expected %s but was %s", strings.ToUpper(expected.Method), r.Method)
+	}
+
+	path := expected.Path
+	if path == "" {
+		path = "/"
+	}
+	for _, m := range matchValue(expected.MatchingRules[models.CategoryPath][""], path, r.URL.Path) {
+		add("path", "", "%s", m)
+	}
+
+	query := r.URL.Query()
+	query.Del(s.options.StateParameter)
+	for _, name := range sortedKeys(expected.Query) {
+		actual, ok := query[name]
+		if !ok {
+			add("query", name, "expected the query parameter but it was missing")
+			continue
+		}
+		for _, m := range matchValues(expected.MatchingRules[models.CategoryQuery][name], expected.Query[name], actual) {
+			add("query", name, "%s", m)
+		}
+	}
+	for _, name := range sortedKeys(query) {
+		if _, ok := expected.Query[name]; !ok {
+			add("query", name, "unexpected query parameter")
+		}
+	}
+
+	for _, name := range sortedKeys(expected.Headers) {
+		actual := r.Header.Values(name)
+		if len(actual) == 0 {
+			add("header", name, "expected the header but it was missing")
+			continue
+		}
+		rule := headerRule(expected.MatchingRules, name)
+		if rule == nil && strings.EqualFold(name, "Content-Type") {
+			if !sameMediaType(strings.Join(expected.Headers[name], ", "), actual[0]) {
+				add("header", name, "expected '%s' but was '%s'", strings.Join(expected.Headers[name], ", "), actual[0])
+			}
+			continue
+		}
+		for _, m := range matchValue(rule, strings.Join(expected.Headers[name], ", "), strings.Join(actual, ", ")) {
+			add("header", name, "%s", m)
+		}
+	}
+
+	for _, m := range matchBody(expected.Body, expected.MatchingRules, body) {
+		add("body", m.Path, "%s", m.Mismatch)
+	}
+
+	return mismatches
+}
+
+// matchValue matches a path, query parameter or header value against its rule, or
+// its expected value if it has no rule
+func matchValue(rule *models.MatchingRule, expected string, actual string) []string {
+	if rule == nil {
+		if expected != actual {
+			return []string{fmt.Sprintf("expected '%s' but was '%s'", expected, actual)}
+		}
+		return nil
+	}
+
+	m, err := matchers.FromMatchingRules(expected, map[string]interface{}{"$": ruleJSON(rule)})
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var messages []string
+	for _, mismatch := range matchers.Evaluate(m, actual) {
+		messages = append(messages, mismatch.Mismatch)
+	}
+
+	return messages
+}
+
+// matchValues matches the values of a query parameter
+func matchValues(rule *models.MatchingRule, expected []string, actual []string) []string {
+	if rule == nil && len(expected) != len(actual) {
+		return []string{fmt.Sprintf("expected %s but was %s", quote(expected), quote(actual))}
+	}
+
+	var messages []string
+	for i, v := range actual {
+		example := expected[len(expected)-1]
+		if i < len(expected) {
+			example = expected[i]
+		}
+		messages = append(messages, matchValue(rule, example, v)...)
+	}
+
+	return messages
+}
+
+// matchBody matches the body of a request against the expected body and its rules.
+// JSON bodies are matched following the rules, and other bodies must be equal.
+// Objects of JSON bodies may have keys that are not expected.
+func matchBody(expected *models.Body, rules models.MatchingRules, actual []byte) []matchers.Mismatch {
+	if expected == nil || expected.Content == nil {
+		return nil
+	}
+
+	doc := expected.Content
+	text, isText := expected.Content.(string)
+	want := []byte(text)
+	if isText && expected.Encoded == "base64" {
+		want, _ = base64.StdEncoding.DecodeString(text)
+	}
+	if isText && (expected.Encoded == "json" || (expected.Encoded == "base64" && isJSON(expected.ContentType))) {
+		if err := json.Unmarshal(want, new(interface{})); err != nil {
+			return []matchers.Mismatch{{Path: "$", Mismatch: "the expected body is not valid JSON"}}
+		}
+		doc, isText = json.RawMessage(want), false
+	}
+
+	if !isText {
+		if err := json.Unmarshal(actual, new(interface{})); err != nil {
+			return []matchers.Mismatch{{Path: "$", Mismatch: "expected a JSON body"}}
+		}
+
+		bodyRules := make(map[string]interface{})
+		for path, rule := range rules[models.CategoryBody] {
+			bodyRules[path] = ruleJSON(rule)
+		}
+		m, err := matchers.FromMatchingRules(doc, bodyRules)
+		if err != nil {
+			return []matchers.Mismatch{{Path: "$", Mismatch: err.Error()}}
+		}
+		return matchers.Evaluate(m, actual)
+	}
+
+	if !bytes.Equal(want, actual) {
+		return []matchers.Mismatch{{Path: "$", Mismatch: fmt.Sprintf("expected a body of %d byte(s) equal to the body of the interaction", len(want))}}
+	}
+
+	return nil
+}
+
+// headerRule returns the rule of the header, whose name is not case sensitive
+func headerRule(rules models.MatchingRules, name string) *models.MatchingRule {
+	for k, rule := range rules[models.CategoryHeader] {
+		if strings.EqualFold(k, name) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// sameMediaType reports whether the actual content type has the media type, and the
+// parameters, of the expected content type
+func sameMediaType(expected string, actual string) bool {
+	expectedType, expectedParams, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return expected == actual
+	}
+	actualType, actualParams, err := mime.ParseMediaType(actual)
+	if err != nil || expectedType != actualType {
+		return false
+	}
+	for k, v := range expectedParams {
+		if !strings.EqualFold(actualParams[k], v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// ruleJSON is the pact file form of a matching rule
+func ruleJSON(rule *models.MatchingRule) map[string]interface{} {
+	list := make([]interface{}, 0, len(rule.Matchers))
+	for _, m := range rule.Matchers {
+		list = append(list, m)
+	}
+	combine := rule.Combine
+	if combine == "" {
+		combine = "AND"
+	}
+
+	return map[string]interface{}{"matchers": list, "combine": combine}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func quote(values []string) string {
+	return "'" + strings.Join(values, "', '") + "'"
+}
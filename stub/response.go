@@ -0,0 +1,156 @@
+package stub
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/pact-foundation/pact-go/v2/internal/matchingrules"
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// respond writes the response of the interaction, with its generators applied
+func (s *Server) respond(w http.ResponseWriter, i *Interaction, serverURL string) error {
+	response := i.Response
+	context := matchers.GeneratorContext{
+		ProviderState: make(map[string]interface{}),
+		MockServerURL: serverURL,
+	}
+	for _, ps := range i.ProviderStates {
+		for k, v := range ps.Parameters {
+			context.ProviderState[k] = v
+		}
+	}
+	generate := func(category string, key string, example interface{}) (interface{}, error) {
+		g := response.Generators[category][key]
+		if g == nil {
+			return example, nil
+		}
+		v, err := matchers.Generate(g, example, context)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", category, key, err)
+		}
+		return v, nil
+	}
+
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	generated, err := generate(models.CategoryStatus, "", status)
+	if err != nil {
+		return err
+	}
+	if status, err = strconv.Atoi(fmt.Sprint(generated)); err != nil {
+		return fmt.Errorf("invalid generated status %v", generated)
+	}
+
+	headers := make(http.Header)
+	for name, values := range response.Headers {
+		for _, v := range values {
+			generated, err := generate(models.CategoryHeader, name, v)
+			if err != nil {
+				return err
+			}
+			headers.Add(name, fmt.Sprint(generated))
+		}
+	}
+
+	body, contentType, err := responseBody(response.Body, response.Generators[models.CategoryBody], context)
+	if err != nil {
+		return err
+	}
+	if headers.Get("Content-Type") == "" && contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
+
+	for name, values := range headers {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+
+	return err
+}
+
+// responseBody returns the content of the body, with the generators applied to JSON
+// bodies, and its content type if it is known
+func responseBody(b *models.Body, generators map[string]models.Generator, context matchers.GeneratorContext) ([]byte, string, error) {
+	if b == nil || b.Content == nil {
+		return nil, "", nil
+	}
+
+	text, isText := b.Content.(string)
+	if isText && b.Encoded != "json" {
+		if b.Encoded == "base64" {
+			data, err := base64.StdEncoding.DecodeString(text)
+			return data, b.ContentType, err
+		}
+		return []byte(text), b.ContentType, nil
+	}
+
+	doc := b.Content
+	if isText {
+		d := json.NewDecoder(bytes.NewReader([]byte(text)))
+		d.UseNumber()
+		if err := d.Decode(&doc); err != nil {
+			return nil, "", fmt.Errorf("invalid JSON body: %w", err)
+		}
+	}
+	doc, err := generateBody(doc, nil, generators, context)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := b.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return data, contentType, nil
+}
+
+// generateBody replaces the values of the document that have a generator, at their
+// path segments, with generated values
+func generateBody(node interface{}, segments []string, generators map[string]models.Generator, context matchers.GeneratorContext) (interface{}, error) {
+	if len(generators) == 0 {
+		return node, nil
+	}
+	if g := matchingrules.Lookup(generators, segments); g != nil {
+		v, err := matchers.Generate(g, node, context)
+		if err != nil {
+			return nil, fmt.Errorf("body %s: %w", matchingrules.FormatPath(segments), err)
+		}
+		return v, nil
+	}
+
+	var err error
+	switch n := node.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(n))
+		for k, v := range n {
+			if result[k], err = generateBody(v, append(segments[:len(segments):len(segments)], k), generators, context); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(n))
+		for i, v := range n {
+			if result[i], err = generateBody(v, append(segments[:len(segments):len(segments)], matchingrules.Index(i)), generators, context); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	default:
+		return node, nil
+	}
+}
@@ -0,0 +1,277 @@
+// Package stub serves the responses of the HTTP interactions of pact files, so that
+// consumers (e.g. a frontend, or end-to-end tests) can run against the contracts
+// without the real provider.
+//
+// A request is answered with the response of the first interaction whose request it
+// matches, following the matching rules of the interaction. Interactions may be
+// selected by provider state, with a header or query parameter naming the state, and
+// the generators of the response (e.g. random identifiers and the current date) are
+// applied as the mock server does. Requests that match no interaction are answered
+// with a 404 response, and logged with the closest interaction and why it did not
+// match.
+package stub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pact-foundation/pact-go/v2/models"
+)
+
+// Defaults of the Options
+const (
+	DefaultStateHeader    = "X-Pact-Provider-State"
+	DefaultStateParameter = "pact-provider-state"
+)
+
+// Options configure a Server
+type Options struct {
+	// StateHeader is the request header that names the provider state of the
+	// interactions to select. Defaults to X-Pact-Provider-State.
+	StateHeader string
+
+	// StateParameter is the query parameter that names the provider state of the
+	// interactions to select. Defaults to pact-provider-state.
+	StateParameter string
+
+	// CORS answers CORS preflight requests, and allows any origin to read the
+	// responses, for use by browsers
+	CORS bool
+}
+
+// Server serves the responses of the HTTP interactions of pact files
+type Server struct {
+	options      Options
+	interactions []*Interaction
+}
+
+// Interaction is an HTTP interaction of a pact file
+type Interaction struct {
+	*models.HTTPInteraction
+
+	// PactFile is the path of the pact file of the interaction
+	PactFile string
+}
+
+// Mismatch is a difference between a request and the request of an interaction
+type Mismatch struct {
+	// Type is the part of the request, one of method, path, query, header, body or
+	// state
+	Type string `json:"type"`
+
+	// Key is the name of the query parameter or header, or the path of the value in
+	// the body, if any
+	Key string `json:"key,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (m Mismatch) String() string {
+	if m.Key == "" {
+		return fmt.Sprintf("%s: %s", m.Type, m.Message)
+	}
+
+	return fmt.Sprintf("%s %s: %s", m.Type, m.Key, m.Message)
+}
+
+// Load returns a server of the HTTP interactions of the pact files at the paths, and
+// of the pact files (*.json) of the directories at the paths
+func Load(paths []string, options Options) (*Server, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	var interactions []*Interaction
+	for _, file := range files {
+		pact, err := models.Load(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read pact file %s: %w", file, err)
+		}
+		for _, i := range pact.Interactions {
+			if h, ok := i.(*models.HTTPInteraction); ok {
+				interactions = append(interactions, &Interaction{HTTPInteraction: h, PactFile: file})
+			}
+		}
+	}
+	if len(interactions) == 0 {
+		return nil, fmt.Errorf("there are no HTTP interactions in the pact files of %s", strings.Join(paths, ", "))
+	}
+
+	return New(interactions, options), nil
+}
+
+// New returns a server of the interactions, which are matched in order
+func New(interactions []*Interaction, options Options) *Server {
+	if options.StateHeader == "" {
+		options.StateHeader = DefaultStateHeader
+	}
+	if options.StateParameter == "" {
+		options.StateParameter = DefaultStateParameter
+	}
+
+	return &Server{options: options, interactions: interactions}
+}
+
+// Interactions returns the interactions of the server
+func (s *Server) Interactions() []*Interaction {
+	return s.interactions
+}
+
+// Match returns the interaction that the request (with the body) matches. If there
+// is none, it returns the closest interaction, if any, and its mismatches.
+//
+// Without a provider state in the request, interactions without provider states are
+// preferred, and otherwise the first interaction that matches is returned.
+func (s *Server) Match(r *http.Request, body []byte) (*Interaction, []Mismatch) {
+	states := s.states(r)
+
+	var closest *Interaction
+	var closestMismatches []Mismatch
+	best := -1
+	var matched *Interaction
+	for _, i := range s.interactions {
+		mismatches := s.mismatches(i, r, body, states)
+		if len(mismatches) == 0 {
+			if len(states) > 0 || len(i.ProviderStates) == 0 {
+				return i, nil
+			}
+			if matched == nil {
+				matched = i
+			}
+			continue
+		}
+		if score := distance(mismatches); best < 0 || score < best {
+			closest, closestMismatches, best = i, mismatches, score
+		}
+	}
+	if matched != nil {
+		return matched, nil
+	}
+
+	return closest, closestMismatches
+}
+
+// ServeHTTP answers the request with the response of the interaction it matches
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.options.CORS {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS")
+			if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	interaction, mismatches := s.Match(r, body)
+	if len(mismatches) > 0 || interaction == nil {
+		s.unmatched(w, r, interaction, mismatches)
+		return
+	}
+
+	log.Printf("[DEBUG] stub: %s %s matched '%s' (%s)", r.Method, r.URL.RequestURI(), interaction.Description, interaction.PactFile)
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if err := s.respond(w, interaction, scheme+"://"+r.Host); err != nil {
+		log.Printf("[ERROR] stub: unable to send the response of '%s': %v", interaction.Description, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// unmatched logs a request that matched no interaction, with the closest
+// interaction, and answers it with a 404 response describing the mismatches
+func (s *Server) unmatched(w http.ResponseWriter, r *http.Request, closest *Interaction, mismatches []Mismatch) {
+	response := map[string]interface{}{
+		"error": fmt.Sprintf("no interaction matched %s %s", r.Method, r.URL.RequestURI()),
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "[WARN] stub: no interaction matched %s %s", r.Method, r.URL.RequestURI())
+	if closest != nil {
+		fmt.Fprintf(&message, ", the closest is '%s' (%s):", closest.Description, closest.PactFile)
+		for _, m := range mismatches {
+			fmt.Fprintf(&message, "\n  %s", m)
+		}
+		response["closest"] = map[string]interface{}{
+			"description": closest.Description,
+			"pactFile":    closest.PactFile,
+			"mismatches":  mismatches,
+		}
+	}
+	log.Println(message.String())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// states returns the provider states named by the request
+func (s *Server) states(r *http.Request) []string {
+	var states []string
+	for _, v := range r.Header.Values(s.options.StateHeader) {
+		if v = strings.TrimSpace(v); v != "" {
+			states = append(states, v)
+		}
+	}
+	for _, v := range r.URL.Query()[s.options.StateParameter] {
+		if v != "" {
+			states = append(states, v)
+		}
+	}
+
+	return states
+}
+
+// distance weighs the mismatches of an interaction, so that the closest interaction
+// to a request can be found. A different path or method weighs more than other
+// mismatches.
+func distance(mismatches []Mismatch) int {
+	d := 0
+	for _, m := range mismatches {
+		switch m.Type {
+		case "path":
+			d += 4
+		case "method":
+			d += 3
+		default:
+			d++
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,205 @@
+package stub
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pact-foundation/pact-go/v2/matchers"
+	"github.com/stretchr/testify/assert"
+)
+
+const pact = `{
+	"consumer": {"name": "web"}, "provider": {"name": "users"},
+	"interactions": [
+		{
+			"description": "a request for a missing user", "providerStates": [{"name": "no users"}],
+			"request": {"method": "GET", "path": "/users/1"},
+			"response": {"status": 404}
+		},
+		{
+			"description": "a request for a user", "providerStates": [{"name": "a user exists", "params": {"id": 7}}],
+			"request": {"method": "GET", "path": "/users/1",
+			  "matchingRules": {"path": {"matchers": [{"match": "regex", "regex": "^/users/\\d+$"}]}}},
+			"response": {"status": 200, "headers": {"Content-Type": "application/json"},
+			  "body": {"id": 1, "token": "abc", "links": {"self": "http://localhost:1234/users/1"}},
+			  "generators": {"body": {
+			    "$.id": {"type": "ProviderState", "expression": "${id}"},
+			    "$.token": {"type": "Uuid"},
+			    "$.links.self": {"type": "MockServerURL", "regex": ".*(/users/\\d+)$", "example": "http://localhost:1234/users/1"}
+			  }}}
+		},
+		{
+			"description": "a request to create a user",
+			"request": {"method": "POST", "path": "/users", "query": {"notify": ["true"]},
+			  "headers": {"Content-Type": "application/json"}, "body": {"name": "billy"},
+			  "matchingRules": {"body": {"$.name": {"matchers": [{"match": "type"}]}}}},
+			"response": {"status": 201, "body": "created", "headers": {"Content-Type": "text/plain"}}
+		}
+	],
+	"metadata": {"pactSpecification": {"version": "3.0.0"}}
+}`
+
+func load(t *testing.T, options Options) *Server {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "web-users.json"), []byte(pact), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a pact"), 0644))
+
+	s, err := Load([]string{dir}, options)
+	assert.NoError(t, err)
+
+	return s
+}
+
+func serve(s *Server, r *http.Request) (*http.Response, string) {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	res := w.Result()
+	body, _ := io.ReadAll(res.Body)
+
+	return res, string(body)
+}
+
+func TestLoad(t *testing.T) {
+	s := load(t, Options{})
+	assert.Len(t, s.Interactions(), 3)
+	assert.Equal(t, "web-users.json", filepath.Base(s.Interactions()[0].PactFile))
+
+	_, err := Load([]string{filepath.Join(t.TempDir(), "missing")}, Options{})
+	assert.Error(t, err)
+
+	_, err = Load([]string{t.TempDir()}, Options{})
+	assert.ErrorContains(t, err, "there are no HTTP interactions")
+}
+
+func TestServeHTTP(t *testing.T) {
+	s := load(t, Options{})
+
+	t.Run("selects the interaction by the state header", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/users/42", nil)
+		r.Header.Set("X-Pact-Provider-State", "a user exists")
+		res, body := serve(s, r)
+
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		var user map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(body), &user))
+		assert.Equal(t, float64(7), user["id"])
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, user["token"])
+		assert.Equal(t, map[string]interface{}{"self": "http://example.com/users/1"}, user["links"])
+	})
+
+	t.Run("selects the interaction by the state parameter", func(t *testing.T) {
+		res, _ := serve(s, httptest.NewRequest("GET", "/users/1?pact-provider-state=no+users", nil))
+		assert.Equal(t, 404, res.StatusCode)
+	})
+
+	t.Run("selects the first interaction without a state", func(t *testing.T) {
+		res, _ := serve(s, httptest.NewRequest("GET", "/users/1", nil))
+		assert.Equal(t, 404, res.StatusCode)
+
+		r := httptest.NewRequest("POST", "/users?notify=true", strings.NewReader(`{"name": "sally", "age": 3}`))
+		r.Header.Set("Content-Type", "application/json; charset=utf-8")
+		res, body := serve(s, r)
+		assert.Equal(t, 201, res.StatusCode)
+		assert.Equal(t, "text/plain", res.Header.Get("Content-Type"))
+		assert.Equal(t, "created", body)
+	})
+
+	t.Run("answers unmatched requests with the closest interaction", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name": 1}`))
+		r.Header.Set("Content-Type", "application/json")
+		res, body := serve(s, r)
+
+		assert.Equal(t, 404, res.StatusCode)
+		var unmatched struct {
+			Error   string
+			Closest struct {
+				Description string
+				Mismatches  []Mismatch
+			}
+		}
+		assert.NoError(t, json.Unmarshal([]byte(body), &unmatched))
+		assert.Equal(t, "no interaction matched POST /users", unmatched.Error)
+		assert.Equal(t, "a request to create a user", unmatched.Closest.Description)
+		assert.Len(t, unmatched.Closest.Mismatches, 2)
+		assert.Equal(t, Mismatch{Type: "query", Key: "notify", Message: "expected the query parameter but it was missing"}, unmatched.Closest.Mismatches[0])
+		assert.Equal(t, "body", unmatched.Closest.Mismatches[1].Type)
+	})
+
+	t.Run("does not match an unknown state", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/users/1", nil)
+		r.Header.Set("X-Pact-Provider-State", "a deleted user")
+		res, body := serve(s, r)
+
+		assert.Equal(t, 404, res.StatusCode)
+		assert.Contains(t, body, "the interaction has no provider state 'a deleted user'")
+	})
+}
+
+func TestServeHTTPWithCORS(t *testing.T) {
+	s := load(t, Options{CORS: true, StateHeader: "X-State"})
+
+	r := httptest.NewRequest("OPTIONS", "/users/1", nil)
+	r.Header.Set("Origin", "http://localhost:3000")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	r.Header.Set("Access-Control-Request-Headers", "X-State")
+	res, _ := serve(s, r)
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, "http://localhost:3000", res.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-State", res.Header.Get("Access-Control-Allow-Headers"))
+
+	r = httptest.NewRequest("GET", "/users/1", nil)
+	r.Header.Set("X-State", "a user exists")
+	res, _ = serve(s, r)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "*", res.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestServeHTTPWithBuiltInRegexes(t *testing.T) {
+	regex := func(m matchers.Matcher) string {
+		data, err := json.Marshal(m)
+		assert.NoError(t, err)
+		var rule struct{ Regex string }
+		assert.NoError(t, json.Unmarshal(data, &rule))
+
+		return rule.Regex
+	}
+	pact, err := json.Marshal(map[string]interface{}{
+		"consumer": map[string]string{"name": "web"}, "provider": map[string]string{"name": "events"},
+		"interactions": []interface{}{map[string]interface{}{
+			"description": "a request to create an event",
+			"request": map[string]interface{}{
+				"method": "POST", "path": "/events", "query": map[string][]string{"on": {"2000-02-01"}},
+				"headers": map[string]string{"Content-Type": "application/json"},
+				"body":    map[string]string{"at": "2000-02-01T12:30:00Z"},
+				"matchingRules": map[string]interface{}{
+					"query": map[string]interface{}{"on": map[string]interface{}{"matchers": []interface{}{map[string]string{"match": "regex", "regex": regex(matchers.Date())}}}},
+					"body":  map[string]interface{}{"$.at": map[string]interface{}{"matchers": []interface{}{map[string]string{"match": "regex", "regex": regex(matchers.Timestamp())}}}},
+				},
+			},
+			"response": map[string]interface{}{"status": 201},
+		}},
+		"metadata": map[string]interface{}{"pactSpecification": map[string]string{"version": "3.0.0"}},
+	})
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "web-events.json"), pact, 0644))
+	s, err := Load([]string{dir}, Options{})
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/events?on=2020-12-31", strings.NewReader(`{"at": "2020-12-31T23:59:59+10:00"}`))
+	r.Header.Set("Content-Type", "application/json")
+	res, body := serve(s, r)
+	assert.Equal(t, 201, res.StatusCode, body)
+
+	r = httptest.NewRequest("POST", "/events?on=2020-12-31", strings.NewReader(`{"at": "yesterday"}`))
+	r.Header.Set("Content-Type", "application/json")
+	res, _ = serve(s, r)
+	assert.Equal(t, 404, res.StatusCode)
+}